@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"dynamodb/internal/api"
+)
+
+// runAuditCommand implements `server audit`, which asks a node to audit the
+// consistency of every replica in the cluster and prints the report
+func runAuditCommand(args []string) int {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	nodeAddr := fs.String("node", "localhost:8081", "Address of the node that coordinates the audit")
+	repair := fs.Bool("repair", false, "Push the winning version of every divergent key to stale replicas")
+	verbose := fs.Bool("verbose", false, "Print every replica's version of each divergent key")
+	timeout := fs.Duration("timeout", 60*time.Second, "Timeout for the audit request")
+	fs.Parse(args)
+
+	url := fmt.Sprintf("http://%s/api/v1/cluster/audit", *nodeAddr)
+	method := http.MethodGet
+	if *repair {
+		url += "/repair"
+		method = http.MethodPost
+	}
+
+	fmt.Printf("🔎 Auditing cluster via %s (repair: %v)\n", *nodeAddr, *repair)
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		fmt.Printf("❌ Failed to create audit request: %v\n", err)
+		return 1
+	}
+
+	client := &http.Client{Timeout: *timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Printf("❌ Audit request failed: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Audit *api.AuditReport `json:"audit"`
+		Error string           `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		fmt.Printf("❌ Failed to decode audit response: %v\n", err)
+		return 1
+	}
+	if resp.StatusCode != http.StatusOK || response.Audit == nil {
+		fmt.Printf("❌ Audit failed (HTTP %d): %s\n", resp.StatusCode, response.Error)
+		return 1
+	}
+
+	printAuditReport(response.Audit, *verbose)
+
+	if !response.Audit.IsConsistent && !(*repair && response.Audit.FailedRepairs == 0) {
+		return 2
+	}
+	return 0
+}
+
+// printAuditReport renders an audit report for the terminal
+func printAuditReport(report *api.AuditReport, verbose bool) {
+	fmt.Printf("📋 Coordinator: %s (N=%d, took %s)\n", report.CoordinatorNode, report.ReplicationFactor, report.Duration)
+	fmt.Printf("🖥️  Nodes audited: %v\n", report.NodesAudited)
+	if len(report.UnreachableNodes) > 0 {
+		fmt.Printf("💀 Unreachable nodes: %v\n", report.UnreachableNodes)
+	}
+	fmt.Printf("📊 Ranges: %d checked, %d consistent, %d divergent\n",
+		report.RangesChecked, report.ConsistentRanges, len(report.DivergentRanges))
+
+	for _, r := range report.DivergentRanges {
+		fmt.Printf("\n⚠️ Range (%d, %d] replicas %v: %d divergent keys\n", r.Start, r.End, r.Replicas, len(r.DivergentKeys))
+		for _, divergent := range r.DivergentKeys {
+			fmt.Printf("   🔥 %s (winner: %s", divergent.Key, divergent.Winner)
+			if len(divergent.Siblings) > 0 {
+				fmt.Printf(", concurrent siblings: %v", divergent.Siblings)
+			}
+			fmt.Printf(")\n")
+
+			if verbose {
+				replicaIDs := make([]string, 0, len(divergent.Versions))
+				for replicaID := range divergent.Versions {
+					replicaIDs = append(replicaIDs, replicaID)
+				}
+				sort.Strings(replicaIDs)
+
+				for _, replicaID := range replicaIDs {
+					version := divergent.Versions[replicaID]
+					switch {
+					case version.Error != "":
+						fmt.Printf("      %s: error: %s\n", replicaID, version.Error)
+					case !version.Found:
+						fmt.Printf("      %s: <missing>\n", replicaID)
+					default:
+						fmt.Printf("      %s: %q %s\n", replicaID, version.Value, version.VectorClock.String())
+					}
+				}
+			}
+
+			if len(divergent.Repaired) > 0 {
+				fmt.Printf("      🔧 repaired on %v\n", divergent.Repaired)
+			}
+			if len(divergent.RepairFailed) > 0 {
+				fmt.Printf("      ❌ repair failed on %v\n", divergent.RepairFailed)
+			}
+		}
+	}
+
+	fmt.Println()
+	if report.IsConsistent {
+		fmt.Printf("✅ All replicas are consistent\n")
+	} else {
+		fmt.Printf("❌ %d divergent keys found\n", report.DivergentKeyCount)
+	}
+	if report.RepairRequested {
+		fmt.Printf("🔧 Repairs: %d succeeded, %d failed\n", report.RepairedKeys, report.FailedRepairs)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"dynamodb/internal/history"
+	"dynamodb/internal/sim"
+)
+
+// runCheckCommand implements `server check`, which drives concurrent clients
+// against a cluster (simulated in-process, or running nodes over HTTP),
+// records what they saw and checks the history against consistency models
+func runCheckCommand(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	target := fs.String("target", "sim", "Cluster to check: sim (in-process, simulated time) or http (running nodes)")
+	nodeList := fs.String("nodes", "", "Comma separated node addresses for --target http (e.g., localhost:8081,localhost:8082)")
+	simNodes := fs.Int("sim-nodes", 3, "Number of simulated nodes for --target sim")
+	seed := fs.Int64("seed", 1, "Seed for the workload and the simulated cluster")
+	clients := fs.Int("clients", 5, "Number of concurrent clients")
+	keys := fs.Int("keys", 5, "Number of distinct keys")
+	ops := fs.Int("ops", 50, "Operations per client")
+	readRatio := fs.Float64("read-ratio", 0.5, "Fraction of operations that are reads")
+	deleteRatio := fs.Float64("delete-ratio", 0.1, "Fraction of the other operations that are deletes")
+	modelList := fs.String("models", "all", "Comma separated models: "+strings.Join(history.AllModels(), ", "))
+	faults := fs.String("faults", "", "Faults for --target sim: random, or a JSON scenario file (its puts are ignored)")
+	settle := fs.Duration("settle", 30*time.Second, "Time allowed to converge before the final reads")
+	timeout := fs.Duration("timeout", 5*time.Second, "Request timeout for --target http")
+	historyOut := fs.String("history-out", "", "Write the recorded history to this file")
+	historyIn := fs.String("history-in", "", "Check a history recorded earlier instead of running a workload")
+	verbose := fs.Bool("verbose", false, "Show the simulated nodes' own logs")
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	fs.Parse(args)
+
+	models, err := history.ParseModels(*modelList)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+
+	workload := history.DefaultWorkload()
+	workload.Clients = *clients
+	workload.Keys = *keys
+	workload.OpsPerClient = *ops
+	workload.ReadRatio = *readRatio
+	workload.DeleteRatio = *deleteRatio
+	workload.Seed = *seed
+
+	var h *history.History
+	switch {
+	case *historyIn != "":
+		h, err = history.Load(*historyIn)
+	case *target == "sim":
+		h, err = recordSimHistory(workload, *seed, *simNodes, *faults, *settle, *verbose)
+	case *target == "http":
+		h, err = recordHTTPHistory(workload, *nodeList, *timeout, *settle)
+	default:
+		err = fmt.Errorf("unknown target %q (expected sim or http)", *target)
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+
+	if *historyOut != "" {
+		if err := h.Save(*historyOut); err != nil {
+			fmt.Printf("❌ Failed to save history: %v\n", err)
+			return 1
+		}
+	}
+
+	report := history.Check(h, models)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		printCheckReport(report)
+	}
+
+	if !report.Valid {
+		return 2
+	}
+	return 0
+}
+
+// recordSimHistory runs the workload against an in-process cluster
+func recordSimHistory(workload *history.Workload, seed int64, nodes int, faults string, settle time.Duration, verbose bool) (*history.History, error) {
+	var scenario *sim.Scenario
+	switch faults {
+	case "":
+	case "random":
+		// Enough steps for faults to overlap the whole workload
+		steps := 50 + workload.OpsPerClient*10
+		scenario = sim.RandomScenario(seed, nodes, steps)
+	default:
+		data, err := os.ReadFile(faults)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scenario: %v", err)
+		}
+		scenario = &sim.Scenario{}
+		if err := json.Unmarshal(data, scenario); err != nil {
+			return nil, fmt.Errorf("invalid scenario %s: %v", faults, err)
+		}
+	}
+
+	// The nodes log to stdout; keep the report readable unless asked for them
+	if !verbose {
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err == nil {
+			out := os.Stdout
+			os.Stdout = devNull
+			defer func() {
+				os.Stdout = out
+				devNull.Close()
+			}()
+		}
+	}
+
+	config := sim.DefaultConfig()
+	config.Seed = seed
+	config.Nodes = nodes
+
+	cluster, err := sim.NewCluster(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start simulated cluster: %v", err)
+	}
+	defer cluster.Close()
+
+	return cluster.RunWorkload(workload, scenario, settle), nil
+}
+
+// recordHTTPHistory runs the workload against running nodes through their
+// public API, spreading the clients over the nodes
+func recordHTTPHistory(workload *history.Workload, nodeList string, timeout, settle time.Duration) (*history.History, error) {
+	addresses := make([]string, 0)
+	for _, address := range strings.Split(nodeList, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("--nodes is required for --target http")
+	}
+
+	nodes := make([]history.Client, len(addresses))
+	for i, address := range addresses {
+		nodes[i] = history.NewHTTPClient(address, timeout)
+	}
+
+	// Keys from earlier runs would show up as values no write in this
+	// history produced
+	workload.KeyPrefix = fmt.Sprintf("check-%d", time.Now().UnixNano())
+
+	recorder := history.NewRecorder(nil)
+	fmt.Printf("📝 Running %d clients × %d operations on %d keys against %d nodes\n", workload.Clients, workload.OpsPerClient, workload.Keys, len(nodes))
+	workload.Run(recorder, nodes, func(f func()) { go f() })
+
+	fmt.Printf("⏳ Waiting %s for the cluster to settle before the final reads\n", settle)
+	time.Sleep(settle)
+	workload.FinalReads(recorder, nodes)
+
+	return recorder.History(), nil
+}
+
+// printCheckReport renders a check report for the terminal
+func printCheckReport(report *history.Report) {
+	fmt.Printf("\n📜 %d operations on %d keys\n", report.Operations, report.Keys)
+
+	for _, result := range report.Models {
+		switch {
+		case result.Valid && len(result.Undecided) == 0:
+			fmt.Printf("✅ %s\n", result.Model)
+		case result.Valid:
+			fmt.Printf("❔ %s: no violations found, undecided for keys %s\n", result.Model, strings.Join(result.Undecided, ", "))
+		default:
+			fmt.Printf("❌ %s: %d anomalies\n", result.Model, len(result.Anomalies))
+		}
+
+		for _, anomaly := range result.Anomalies {
+			fmt.Printf("   • %s\n", anomaly.Description)
+			for _, op := range anomaly.Operations {
+				fmt.Printf("       %s\n", op)
+			}
+		}
+	}
+}
@@ -1,39 +1,94 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"dynamodb/internal/api"
+	"dynamodb/internal/fault"
 	"dynamodb/internal/gossip"
+	"dynamodb/internal/membership"
 	"dynamodb/internal/node"
 	"dynamodb/internal/replication"
 	"dynamodb/internal/ring"
+	"dynamodb/internal/rpc"
 	"dynamodb/internal/storage"
+	"dynamodb/internal/transport"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Subcommands run as a client against an existing cluster
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		os.Exit(runAuditCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		os.Exit(runSimulateCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(runCheckCommand(os.Args[2:]))
+	}
+
 	// Parse command line flags
 	port := flag.String("port", "8080", "Port to run the server on")
 	nodeID := flag.String("node-id", "node-1", "Unique identifier for this node")
 	dataPath := flag.String("data-dir", "./data", "Directory to store data")
-	seedNode := flag.String("seed-node", "", "Seed node address for gossip discovery (e.g., localhost:8081)")
+	seedNodes := flag.String("seed-nodes", "", "Comma-separated seed node addresses for gossip discovery (e.g., localhost:8081,localhost:8082)")
+	seedNode := flag.String("seed-node", "", "Single seed node address, added to --seed-nodes")
 	enableGossip := flag.Bool("gossip", true, "Enable gossip protocol for cluster discovery")
+	replicationFactor := flag.Int("replication-factor", 3, "Number of replicas per key (N)")
+	readQuorum := flag.Int("read-quorum", 2, "Replicas that must answer a read (R)")
+	writeQuorum := flag.Int("write-quorum", 2, "Replicas that must acknowledge a write (W)")
+	namespaceConfigPath := flag.String("namespace-config", "", "JSON file with per-namespace N/R/W overrides")
+	sloppyQuorum := flag.Bool("sloppy-quorum", true, "Write to stand-in nodes further along the ring when an owner is down")
+	asyncReplication := flag.Bool("async-replication", false, "Replicate writes that need a single acknowledgement through the durable background queue")
+	peerMaxConns := flag.Int("peer-max-conns", 32, "Maximum open connections to each peer node (0 = unlimited)")
+	internalTransport := flag.String("internal-transport", rpc.TransportHTTP, "Transport for node-to-node traffic: http or grpc")
+	grpcPortOffset := flag.Int("grpc-port-offset", rpc.DefaultPortOffset, "gRPC listens on the HTTP port plus this offset (same on every node)")
+	gossipUDP := flag.Bool("gossip-udp", true, "Send gossip probes and heartbeats over UDP on the HTTP port number, keeping HTTP/gRPC for full state sync (same on every node)")
+	phiSuspect := flag.Float64("phi-suspect", 5, "Failure detector phi at which a node is suspected")
+	phiDead := flag.Float64("phi-dead", 12, "Failure detector phi at which a node is declared dead")
+	faultInjection := flag.Bool("fault-injection", false, "Enable the /api/v1/faults admin API for dropping, delaying, duplicating or failing inter-node HTTP requests")
+	faultSeed := flag.Int64("fault-seed", 0, "Seed for which requests fault rules affect, to repeat a run (0 = random)")
 	flag.Parse()
 
+	if *internalTransport != rpc.TransportHTTP && *internalTransport != rpc.TransportGRPC {
+		log.Fatalf("Invalid internal transport %q: must be %s or %s", *internalTransport, rpc.TransportHTTP, rpc.TransportGRPC)
+	}
+	useGRPC := *internalTransport == rpc.TransportGRPC
+
+	// Seeds are known by address only; each one tells us its node ID
+	var seeds []string
+	for _, address := range strings.Split(*seedNodes+","+*seedNode, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			seeds = append(seeds, address)
+		}
+	}
+
+	if *phiSuspect <= 0 || *phiDead <= *phiSuspect {
+		log.Fatalf("Invalid phi thresholds: need 0 < --phi-suspect (%v) < --phi-dead (%v)", *phiSuspect, *phiDead)
+	}
+
+	replicationConfig := &replication.ReplicationConfig{N: *replicationFactor, R: *readQuorum, W: *writeQuorum}
+	if err := replicationConfig.Validate(); err != nil {
+		log.Fatal("Invalid replication config:", err)
+	}
+
 	fmt.Printf("🚀 Starting DynamoDB Node: %s on port %s\n", *nodeID, *port)
 	fmt.Printf("📁 Data will be stored in: %s/%s\n", *dataPath, *nodeID)
 	if *enableGossip {
 		fmt.Printf("🗣️ Gossip protocol enabled\n")
-		if *seedNode != "" {
-			fmt.Printf("🌱 Seed node: %s\n", *seedNode)
+		if len(seeds) > 0 {
+			fmt.Printf("🌱 Seed nodes: %s\n", strings.Join(seeds, ", "))
 		}
 	}
 
@@ -53,62 +108,118 @@ func main() {
 
 	fmt.Printf("✅ Node %s added to hash ring\n", *nodeID)
 
+	// One view of which nodes are alive, feeding the ring, the replicator
+	// and the APIs. Gossip detects failures for it, or with gossip off the
+	// service pings the members itself.
+	members := membership.New(currentNode, nil)
+	members.SetPhiThresholds(*phiSuspect, *phiDead)
+	members.FeedRing(hashRing)
+
+	// One pooled client carries all node-to-node traffic
+	transportConfig := transport.DefaultConfig()
+	transportConfig.MaxConnsPerPeer = *peerMaxConns
+	interNode := transport.NewClient(transportConfig)
+	interNode.SetNodeID(*nodeID)
+	defer interNode.Close()
+
+	// Rules for simulating partitions and flaky links, managed at runtime
+	var faultInjector *fault.Injector
+	if *faultInjection {
+		faultInjector = fault.NewInjector(*faultSeed, nil)
+		faultInjector.SetPeerResolver(func(address string) string {
+			for _, member := range members.Members() {
+				if member.Address == address {
+					return member.NodeID
+				}
+			}
+			return ""
+		})
+		interNode.SetFaults(faultInjector)
+		fmt.Printf("💥 Fault injection enabled (seed %d): manage rules at /api/v1/faults\n", faultInjector.Seed())
+		if useGRPC {
+			fmt.Printf("⚠️ Fault rules only apply to HTTP traffic and gossip packets; gRPC calls are not affected\n")
+		}
+	}
+
+	// Replication, repair, Merkle exchange and gossip can go over gRPC instead
+	var rpcClient *rpc.Client
+	var replicationTransport replication.Transport = replication.NewHTTPTransport(interNode)
+	var gossipTransport gossip.Transport = gossip.NewHTTPTransport(interNode)
+	if useGRPC {
+		rpcClient = rpc.NewClient(*grpcPortOffset)
+		defer rpcClient.Close()
+
+		replicationTransport = replication.NewGRPCTransport(rpcClient)
+		gossipTransport = gossip.NewGRPCTransport(rpcClient)
+	}
+
 	// Initialize replication system
-	replicator := replication.NewReplicator(hashRing, localStorage, currentNode)
-	defer replicator.Stop() // Clean shutdown of health monitoring
+	replicator := replication.NewReplicator(hashRing, localStorage, currentNode, replicationConfig, replicationTransport, members, nil)
+	defer replicator.Stop() // Clean shutdown of background work
+
+	replicator.SetSloppyQuorum(*sloppyQuorum)
+	replicator.SetAsyncReplication(*asyncReplication)
+
+	fmt.Printf("⚙️ Replication: N=%d R=%d W=%d (sloppy quorum: %t)\n", replicationConfig.N, replicationConfig.R, replicationConfig.W, *sloppyQuorum)
+	if !replicationConfig.IsStrong() {
+		fmt.Printf("⚠️ R+W <= N: reads may not observe the latest acknowledged write\n")
+	}
+
+	if *namespaceConfigPath != "" {
+		namespaceConfigs, err := replication.LoadNamespaceConfigs(*namespaceConfigPath)
+		if err != nil {
+			log.Fatal("Failed to load namespace config:", err)
+		}
+		for namespace, config := range namespaceConfigs {
+			if err := replicator.SetNamespaceConfig(namespace, config); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
 
 	// Initialize gossip protocol
 	var gossipManager *gossip.GossipManager
 	var gossipHandler *gossip.GossipHandler
-	
+
 	if *enableGossip {
-		gossipManager = gossip.NewGossipManager(currentNode, gossip.DefaultGossipConfig())
-		
-		// Set up callbacks for gossip events
-		gossipManager.SetCallbacks(
-			func(joinNodeID, address string) {
-				// Node joined callback - skip if it's ourselves
-				if joinNodeID != *nodeID {
-					fmt.Printf("🤝 Gossip: Node %s joined at %s\n", joinNodeID, address)
-					newNode := node.NewNode(joinNodeID, address)
-					hashRing.AddNode(newNode)
-					
-					// Also mark the node as alive in the replication system
-					fmt.Printf("💚 Marking gossip-discovered node %s as alive in replication system\n", joinNodeID)
-					replicator.MarkNodeAlive(joinNodeID)
-				}
-			},
-			func(leaveNodeID string) {
-				// Node left callback - skip if it's ourselves
-				if leaveNodeID != *nodeID {
-					fmt.Printf("👋 Gossip: Node %s left cluster\n", leaveNodeID)
-					hashRing.RemoveNode(leaveNodeID)
-				}
-			},
-			func(failNodeID string) {
-				// Node failed callback - skip if it's ourselves
-				if failNodeID != *nodeID {
-					fmt.Printf("💀 Gossip: Node %s failed\n", failNodeID)
-					hashRing.RemoveNode(failNodeID)
-				}
-			},
-		)
-		
-		// Add seed node if provided
-		if *seedNode != "" {
-			// Extract nodeID from seed node address (simple heuristic)
-			seedNodeID := "seed-node"
-			if *seedNode == "localhost:8081" {
-				seedNodeID = "node-1"
-			} else if *seedNode == "localhost:8082" {
-				seedNodeID = "node-2"
+		gossipConfig := gossip.DefaultGossipConfig()
+		gossipConfig.PhiSuspect = *phiSuspect
+		gossipConfig.PhiDead = *phiDead
+		gossipManager = gossip.NewGossipManager(currentNode, gossipConfig, gossipTransport)
+
+		// Probes and heartbeats go over UDP on the same port number
+		if *gossipUDP {
+			udpTransport, err := gossip.ListenUDP(":"+*port, gossip.DefaultPacketSize, nil)
+			if err != nil {
+				log.Fatal("Failed to open gossip UDP port:", err)
+			}
+			defer udpTransport.Close()
+
+			if faultInjector != nil {
+				udpTransport.SetFaults(faultInjector)
 			}
-			gossipManager.AddSeedNode(seedNodeID, *seedNode)
+			gossipManager.SetPacketTransport(udpTransport)
+			go udpTransport.Serve(gossipManager.HandleGossipMessage)
+			fmt.Printf("📦 Gossip probes and heartbeats over UDP port %s\n", *port)
 		}
-		
+
+		// Gossip is the membership service's failure detector
+		gossipManager.SetMembership(members)
+
+		// Namespace overrides set through the API reach every node by gossip
+		replicator.SetClusterSettings(gossipManager)
+
+		// Join through the seeds, retrying until one answers
+		gossipManager.AddSeeds(seeds...)
+
 		gossipHandler = gossip.NewGossipHandler(gossipManager)
 		gossipManager.Start()
 		defer gossipManager.Stop()
+	} else {
+		monitorCtx, stopMonitor := context.WithCancel(context.Background())
+		defer stopMonitor()
+		members.Monitor(monitorCtx, replicationTransport)
+		fmt.Printf("🩺 Gossip disabled: pinging members every %s\n", membership.PingInterval)
 	}
 
 	// Initialize API server
@@ -128,7 +239,32 @@ func main() {
 		c.Next()
 	})
 
-	apiHandler := api.NewHandler(hashRing, currentNode, localStorage, replicator)
+	apiHandler := api.NewHandler(hashRing, currentNode, localStorage, replicator, members, interNode)
+
+	// Serve the internal gRPC services next to the HTTP API
+	if useGRPC {
+		httpPort, err := strconv.Atoi(*port)
+		if err != nil {
+			log.Fatal("Invalid port:", err)
+		}
+		grpcAddress := fmt.Sprintf(":%d", httpPort+*grpcPortOffset)
+
+		grpcServer := rpc.NewServer()
+		replicator.RegisterRPC(grpcServer)
+		apiHandler.UseGRPC(rpcClient)
+		apiHandler.RegisterRPC(grpcServer)
+		if gossipManager != nil {
+			gossipManager.RegisterRPC(grpcServer)
+		}
+		defer grpcServer.Stop()
+
+		go func() {
+			if err := rpc.Serve(grpcServer, grpcAddress); err != nil {
+				log.Fatal("Failed to start gRPC server:", err)
+			}
+		}()
+		fmt.Printf("📡 Internal gRPC transport listening on %s\n", grpcAddress)
+	}
 
 	// Setup routes
 	v1 := router.Group("/api/v1")
@@ -140,9 +276,17 @@ func main() {
 		v1.GET("/data/:key", apiHandler.GetData)
 		v1.DELETE("/data/:key", apiHandler.DeleteData)
 
+		// Replication settings (N/R/W)
+		v1.GET("/replication/config", apiHandler.GetReplicationConfig)
+		v1.PUT("/replication/namespaces/:namespace", apiHandler.SetNamespaceConfig)
+		v1.GET("/replication/hints", apiHandler.GetHints)
+		v1.GET("/transport/metrics", apiHandler.GetTransportMetrics)
+
 		// Cluster management endpoints
 		v1.POST("/cluster/join", apiHandler.JoinCluster)
 		v1.GET("/cluster", apiHandler.GetCluster)
+		v1.GET("/cluster/audit", apiHandler.AuditCluster)
+		v1.POST("/cluster/audit/repair", apiHandler.RepairCluster)
 
 		// Merkle tree endpoints for data integrity
 		v1.GET("/merkle-tree", apiHandler.GetMerkleTree)
@@ -154,17 +298,34 @@ func main() {
 		v1.GET("/events", apiHandler.GetEventHistory)
 		v1.GET("/vector-clock/compare/:target_node", apiHandler.CompareVectorClocks)
 		v1.POST("/vector-clock/sync", apiHandler.SyncVectorClocks)
+
+		// Fault injection for inter-node traffic
+		if faultInjector != nil {
+			faultHandler := fault.NewHandler(faultInjector)
+			v1.GET("/faults", faultHandler.ListRules)
+			v1.POST("/faults", faultHandler.AddRule)
+			v1.DELETE("/faults", faultHandler.ClearRules)
+			v1.DELETE("/faults/:id", faultHandler.RemoveRule)
+		}
 	}
 
 	// Internal replication endpoint (for node-to-node communication)
 	internal := router.Group("/internal")
+	if faultInjector != nil {
+		internal.Use(faultInjector.Middleware(router))
+	}
 	{
 		internal.POST("/replicate", apiHandler.HandleReplication)
+		internal.POST("/replicate/batch", apiHandler.HandleBatchReplication)
+		internal.GET("/data/:key", apiHandler.GetLocalVersion)
 	}
 
 	// Gossip protocol endpoints
 	if *enableGossip && gossipHandler != nil {
 		gossipGroup := router.Group("/gossip")
+		if faultInjector != nil {
+			gossipGroup.Use(faultInjector.Middleware(router))
+		}
 		{
 			gossipGroup.POST("/receive", gossipHandler.ReceiveGossip)
 			gossipGroup.GET("/members", gossipHandler.GetClusterMembers)
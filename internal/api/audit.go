@@ -0,0 +1,407 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"dynamodb/internal/ring"
+	"dynamodb/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplicaVersion describes what a single replica holds for a key
+type ReplicaVersion struct {
+	NodeID      string               `json:"node_id"`
+	Found       bool                 `json:"found"`
+	Deleted     bool                 `json:"deleted,omitempty"` // Found a delete's tombstone
+	Value       string               `json:"value,omitempty"`
+	VectorClock *storage.VectorClock `json:"vector_clock,omitempty"`
+	Timestamp   int64                `json:"timestamp,omitempty"`
+	LeafHash    string               `json:"leaf_hash,omitempty"`
+	Error       string               `json:"error,omitempty"`
+
+	stored *storage.StorageValue
+}
+
+// DivergentKey describes a key whose replicas disagree
+type DivergentKey struct {
+	Key          string                     `json:"key"`
+	MetadataOnly bool                       `json:"metadata_only"` // Same value, different version metadata
+	Replicas     []string                   `json:"replicas"`
+	Versions     map[string]*ReplicaVersion `json:"versions"`
+	Winner       string                     `json:"winner,omitempty"`
+	Siblings     []string                   `json:"siblings,omitempty"`
+	Repaired     []string                   `json:"repaired,omitempty"`
+	RepairFailed []string                   `json:"repair_failed,omitempty"`
+}
+
+// RangeAudit is the audit result for one ring range, for the keys kept at
+// one replication factor
+type RangeAudit struct {
+	Start             uint32            `json:"start"`
+	End               uint32            `json:"end"`
+	ReplicationFactor int               `json:"replication_factor"`
+	Replicas          []string          `json:"replicas"`
+	KeyCount          int               `json:"key_count"`
+	RootHashes        map[string]string `json:"root_hashes"`
+	Consistent        bool              `json:"consistent"`
+	DivergentKeys     []*DivergentKey   `json:"divergent_keys,omitempty"`
+}
+
+// AuditReport summarizes a cluster-wide consistency audit
+type AuditReport struct {
+	CoordinatorNode   string        `json:"coordinator_node"`
+	ReplicationFactor int           `json:"replication_factor"`
+	NodesAudited      []string      `json:"nodes_audited"`
+	UnreachableNodes  []string      `json:"unreachable_nodes"`
+	RangesChecked     int           `json:"ranges_checked"`
+	ConsistentRanges  int           `json:"consistent_ranges"`
+	DivergentRanges   []*RangeAudit `json:"divergent_ranges"`
+	DivergentKeyCount int           `json:"divergent_key_count"`
+	IsConsistent      bool          `json:"is_consistent"`
+	RepairRequested   bool          `json:"repair_requested"`
+	RepairedKeys      int           `json:"repaired_keys"`
+	FailedRepairs     int           `json:"failed_repairs"`
+	Duration          string        `json:"duration"`
+	Timestamp         int64         `json:"timestamp"`
+}
+
+// AuditCluster checks whether all replicas of every ring range agree
+func (h *Handler) AuditCluster(c *gin.Context) {
+	report, err := h.runClusterAudit(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audit":     report,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// RepairCluster runs a cluster audit and pushes the winning version of every
+// divergent key without concurrent versions to the replicas that are stale or
+// missing it
+func (h *Handler) RepairCluster(c *gin.Context) {
+	report, err := h.runClusterAudit(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audit":     report,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// GetLocalVersion returns this node's stored version of a key without going
+// through the quorum read path (used by other nodes to compare replicas)
+func (h *Handler) GetLocalVersion(c *gin.Context) {
+	key := c.Param("key")
+
+	value, err := h.storage.GetVersion(key)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"key":     key,
+			"node_id": h.currentNode.ID,
+			"found":   false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"key":     key,
+		"node_id": h.currentNode.ID,
+		"found":   true,
+		"version": value,
+	})
+}
+
+// runClusterAudit compares the Merkle trees of all replicas range by range.
+// Every key is audited against its own replica set: keys in namespaces with
+// their own N are grouped into the ranges for that N.
+func (h *Handler) runClusterAudit(repair bool) (*AuditReport, error) {
+	start := time.Now()
+	replicationFactor := h.replicator.GetReplicationFactor()
+
+	report := &AuditReport{
+		CoordinatorNode:   h.currentNode.ID,
+		ReplicationFactor: replicationFactor,
+		NodesAudited:      make([]string, 0),
+		UnreachableNodes:  make([]string, 0),
+		DivergentRanges:   make([]*RangeAudit, 0),
+		RepairRequested:   repair,
+	}
+
+	// Collect every node's tree
+	trees := make(map[string]*storage.MerkleTree)
+	for _, n := range h.ring.GetAllNodes() {
+		var tree *storage.MerkleTree
+		var err error
+		if n.ID == h.currentNode.ID {
+			tree, err = h.storage.BuildMerkleTree()
+		} else {
+			tree, err = h.fetchMerkleTreeFromNode(n)
+		}
+		if err != nil {
+			fmt.Printf("⚠️ Audit: could not get Merkle tree from %s: %v\n", n.ID, err)
+			report.UnreachableNodes = append(report.UnreachableNodes, n.ID)
+			continue
+		}
+		trees[n.ID] = tree
+		report.NodesAudited = append(report.NodesAudited, n.ID)
+	}
+	sort.Strings(report.NodesAudited)
+	sort.Strings(report.UnreachableNodes)
+
+	rangesByN := map[int][]ring.RingRange{replicationFactor: h.ring.GetRanges(replicationFactor)}
+	if len(rangesByN[replicationFactor]) == 0 {
+		return nil, fmt.Errorf("hash ring is empty")
+	}
+
+	// Bucket each node's leaves by the key's replication factor and the
+	// range they fall into
+	rangeLeaves := make(map[auditBucket]map[string][]*storage.MerkleNode)
+	for nodeID, tree := range trees {
+		for _, leaf := range tree.Leaves {
+			n := h.replicator.ConfigForKey(leaf.Key).N
+			if _, ok := rangesByN[n]; !ok {
+				rangesByN[n] = h.ring.GetRanges(n)
+			}
+			bucket := auditBucket{n: n, idx: h.ring.FindRange(rangesByN[n], leaf.Key)}
+			if rangeLeaves[bucket] == nil {
+				rangeLeaves[bucket] = make(map[string][]*storage.MerkleNode)
+			}
+			rangeLeaves[bucket][nodeID] = append(rangeLeaves[bucket][nodeID], leaf)
+		}
+	}
+
+	factors := make([]int, 0, len(rangesByN))
+	for n := range rangesByN {
+		factors = append(factors, n)
+	}
+	sort.Ints(factors)
+
+	for _, n := range factors {
+		for idx, r := range rangesByN[n] {
+			bucket := auditBucket{n: n, idx: idx}
+			// Namespace factors only cover the ranges their keys fall into
+			if n != replicationFactor && rangeLeaves[bucket] == nil {
+				continue
+			}
+			report.RangesChecked++
+
+			audit := h.auditRange(r, n, rangeLeaves[bucket], trees)
+			if audit.Consistent {
+				report.ConsistentRanges++
+				continue
+			}
+
+			if repair {
+				for _, divergent := range audit.DivergentKeys {
+					h.repairDivergentKey(divergent)
+					report.RepairedKeys += len(divergent.Repaired)
+					report.FailedRepairs += len(divergent.RepairFailed)
+				}
+			}
+
+			report.DivergentKeyCount += len(audit.DivergentKeys)
+			report.DivergentRanges = append(report.DivergentRanges, audit)
+		}
+	}
+
+	report.IsConsistent = len(report.DivergentRanges) == 0 && len(report.UnreachableNodes) == 0
+	report.Duration = time.Since(start).String()
+	report.Timestamp = time.Now().Unix()
+
+	fmt.Printf("🔎 Cluster audit complete: %d/%d ranges consistent, %d divergent keys, %d unreachable nodes\n",
+		report.ConsistentRanges, report.RangesChecked, report.DivergentKeyCount, len(report.UnreachableNodes))
+
+	return report, nil
+}
+
+// auditBucket identifies a ring range among the ranges for one replication factor
+type auditBucket struct {
+	n   int
+	idx int
+}
+
+// auditRange compares the replicas of a single ring range for the keys kept
+// at replication factor n
+func (h *Handler) auditRange(r ring.RingRange, n int, leavesByNode map[string][]*storage.MerkleNode, trees map[string]*storage.MerkleTree) *RangeAudit {
+	audit := &RangeAudit{
+		Start:             r.Start,
+		End:               r.End,
+		ReplicationFactor: n,
+		Replicas:          r.Replicas,
+		RootHashes:        make(map[string]string),
+		Consistent:        true,
+	}
+
+	// Build a per-range tree for every reachable replica and compare roots
+	replicaLeaves := make(map[string]map[string]*storage.MerkleNode)
+	keys := make(map[string]bool)
+	var referenceHash string
+	for _, replicaID := range r.Replicas {
+		if _, reachable := trees[replicaID]; !reachable {
+			continue
+		}
+
+		rangeTree := storage.NewMerkleTreeFromLeaves(replicaID, leavesByNode[replicaID])
+		audit.RootHashes[replicaID] = rangeTree.Root.Hash
+		if referenceHash == "" {
+			referenceHash = rangeTree.Root.Hash
+		} else if rangeTree.Root.Hash != referenceHash {
+			audit.Consistent = false
+		}
+
+		replicaLeaves[replicaID] = make(map[string]*storage.MerkleNode)
+		for _, leaf := range rangeTree.Leaves {
+			replicaLeaves[replicaID][leaf.Key] = leaf
+			keys[leaf.Key] = true
+		}
+	}
+	audit.KeyCount = len(keys)
+
+	if audit.Consistent {
+		return audit
+	}
+
+	// Root hashes differ - drill down to the individual keys
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		hashes := make(map[string]bool)
+		valueDiffers := false
+		var firstLeaf *storage.MerkleNode
+		for replicaID := range replicaLeaves {
+			leaf, ok := replicaLeaves[replicaID][key]
+			if !ok {
+				hashes[""] = true
+				valueDiffers = true
+				continue
+			}
+			hashes[leaf.Hash] = true
+			if firstLeaf == nil {
+				firstLeaf = leaf
+			} else if storage.LeafValueDiffers(firstLeaf, leaf) {
+				valueDiffers = true
+			}
+		}
+		if len(hashes) <= 1 {
+			continue
+		}
+
+		divergent := &DivergentKey{
+			Key:          key,
+			MetadataOnly: !valueDiffers,
+			Replicas:     r.Replicas,
+			Versions:     make(map[string]*ReplicaVersion),
+		}
+		for replicaID := range replicaLeaves {
+			version := h.fetchReplicaVersion(replicaID, key)
+			if leaf, ok := replicaLeaves[replicaID][key]; ok {
+				version.LeafHash = leaf.Hash
+			}
+			divergent.Versions[replicaID] = version
+		}
+		divergent.Winner, divergent.Siblings = storage.ReconcileVersions(versionsForReconcile(divergent.Versions))
+
+		audit.DivergentKeys = append(audit.DivergentKeys, divergent)
+	}
+
+	return audit
+}
+
+// repairDivergentKey pushes the winning version of a key (a tombstone if the
+// key was deleted) to every replica that doesn't already hold it. A replica
+// without the key never saw it, since a delete leaves a tombstone. Keys with
+// concurrent versions are left alone so no sibling is overwritten.
+func (h *Handler) repairDivergentKey(divergent *DivergentKey) {
+	if divergent.Winner == "" {
+		return
+	}
+	if len(divergent.Siblings) > 0 {
+		fmt.Printf("⚠️ Audit repair of %s skipped: replicas %s and %v hold concurrent versions\n", divergent.Key, divergent.Winner, divergent.Siblings)
+		return
+	}
+	winning := divergent.Versions[divergent.Winner]
+
+	for replicaID, version := range divergent.Versions {
+		if replicaID == divergent.Winner || version.Error != "" {
+			continue
+		}
+		if version.Found && version.LeafHash == winning.LeafHash {
+			continue
+		}
+
+		targetNode := h.ring.GetNode(replicaID)
+		if targetNode == nil {
+			divergent.RepairFailed = append(divergent.RepairFailed, replicaID)
+			continue
+		}
+
+		if err := h.replicator.PushVersion(targetNode, divergent.Key, winning.stored); err != nil {
+			fmt.Printf("❌ Audit repair of %s on %s failed: %v\n", divergent.Key, replicaID, err)
+			divergent.RepairFailed = append(divergent.RepairFailed, replicaID)
+			continue
+		}
+
+		fmt.Printf("🔧 Audit repaired %s on %s (winner: %s)\n", divergent.Key, replicaID, divergent.Winner)
+		divergent.Repaired = append(divergent.Repaired, replicaID)
+	}
+}
+
+// fetchReplicaVersion gets a replica's stored version of a key
+func (h *Handler) fetchReplicaVersion(replicaID, key string) *ReplicaVersion {
+	version := &ReplicaVersion{NodeID: replicaID}
+
+	var value *storage.StorageValue
+	if replicaID == h.currentNode.ID {
+		value, _ = h.storage.GetVersion(key)
+	} else {
+		targetNode := h.ring.GetNode(replicaID)
+		if targetNode == nil {
+			version.Error = "node not found in ring"
+			return version
+		}
+
+		var err error
+		value, err = h.replicator.FetchVersion(targetNode, key)
+		if err != nil {
+			version.Error = err.Error()
+			return version
+		}
+	}
+
+	if value != nil {
+		version.Found = true
+		version.Deleted = value.Deleted
+		version.Value = value.Value
+		version.VectorClock = value.GetVectorClock()
+		version.Timestamp = value.Timestamp
+		version.stored = value
+	}
+
+	return version
+}
+
+// versionsForReconcile converts audit versions into storage values for conflict resolution
+func versionsForReconcile(versions map[string]*ReplicaVersion) map[string]*storage.StorageValue {
+	values := make(map[string]*storage.StorageValue)
+	for replicaID, version := range versions {
+		if version.Found {
+			values[replicaID] = version.stored
+		}
+	}
+	return values
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"net"
+	"testing"
+
+	"dynamodb/internal/membership"
+	"dynamodb/internal/node"
+	"dynamodb/internal/replication"
+	"dynamodb/internal/ring"
+	"dynamodb/internal/rpc"
+	"dynamodb/internal/storage"
+	"dynamodb/internal/transport"
+)
+
+// auditNode is one node of an audit test cluster
+type auditNode struct {
+	handler    *Handler
+	storage    *storage.LevelDBStorage
+	replicator *replication.Replicator
+}
+
+// newAuditCluster starts nodes that replicate over an in-memory network and
+// serve their Merkle trees over gRPC. Each node's address is its gRPC
+// address, so peers reach it with a port offset of 0.
+func newAuditCluster(t *testing.T, config replication.ReplicationConfig, ids ...string) map[string]*auditNode {
+	t.Helper()
+
+	listeners := make(map[string]net.Listener)
+	var nodes []*node.Node
+	for _, id := range ids {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		listeners[id] = listener
+		nodes = append(nodes, node.NewNode(id, listener.Addr().String()))
+	}
+
+	network := transport.NewNetwork(1)
+	cluster := make(map[string]*auditNode)
+	for _, self := range nodes {
+		localStorage, err := storage.NewLevelDBStorage(self.ID, t.TempDir())
+		if err != nil {
+			t.Fatalf("NewLevelDBStorage: %v", err)
+		}
+
+		hashRing := ring.NewConsistentHashRing()
+		members := membership.New(self, nil)
+		for _, n := range nodes {
+			hashRing.AddNode(node.NewNode(n.ID, n.Address))
+			if n.ID != self.ID {
+				members.Join(n.ID, n.Address)
+			}
+		}
+
+		nodeConfig := config
+		replicator := replication.NewReplicator(hashRing, localStorage, self, &nodeConfig,
+			replication.NewMemoryTransport(network, self.Address), members, nil)
+		replicator.RegisterMemory(network)
+
+		handler := NewHandler(hashRing, self, localStorage, replicator, members, nil)
+		client := rpc.NewClient(0)
+		handler.UseGRPC(client)
+
+		server := rpc.NewServer()
+		handler.RegisterRPC(server)
+		go server.Serve(listeners[self.ID])
+
+		t.Cleanup(func() {
+			server.Stop()
+			client.Close()
+			replicator.Stop()
+			localStorage.Close()
+		})
+		cluster[self.ID] = &auditNode{handler: handler, storage: localStorage, replicator: replicator}
+	}
+	return cluster
+}
+
+// writeAll writes a key on every owner before returning
+func writeAll(t *testing.T, n *auditNode, key, value string) {
+	t.Helper()
+
+	opts := &replication.ConsistencyOptions{Level: replication.ConsistencyAll}
+	if _, err := n.replicator.WriteWithReplication(key, value, opts); err != nil {
+		t.Fatalf("write %s: %v", key, err)
+	}
+}
+
+func audit(t *testing.T, n *auditNode, repair bool) *AuditReport {
+	t.Helper()
+
+	report, err := n.handler.runClusterAudit(repair)
+	if err != nil {
+		t.Fatalf("audit: %v", err)
+	}
+	if len(report.UnreachableNodes) != 0 {
+		t.Fatalf("audit couldn't reach %v", report.UnreachableNodes)
+	}
+	return report
+}
+
+// divergentKeys returns the audited divergent keys by key
+func divergentKeys(report *AuditReport) map[string]*DivergentKey {
+	keys := make(map[string]*DivergentKey)
+	for _, r := range report.DivergentRanges {
+		for _, divergent := range r.DivergentKeys {
+			keys[divergent.Key] = divergent
+		}
+	}
+	return keys
+}
+
+func TestAuditFindsAndRepairsStaleReplicas(t *testing.T) {
+	tests := []struct {
+		name         string
+		diverge      func(s *storage.LevelDBStorage) error
+		metadataOnly bool
+		deleted      bool
+	}{
+		{name: "newer value", diverge: func(s *storage.LevelDBStorage) error { return s.Put("user:1", "v2") }},
+		{name: "same value, newer version", metadataOnly: true,
+			diverge: func(s *storage.LevelDBStorage) error { return s.Put("user:1", "v1") }},
+		{name: "delete", deleted: true, diverge: func(s *storage.LevelDBStorage) error { return s.Delete("user:1") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newAuditCluster(t, replication.ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c")
+			writeAll(t, cluster["a"], "user:1", "v1")
+			writeAll(t, cluster["a"], "user:2", "v1")
+			if report := audit(t, cluster["a"], false); !report.IsConsistent {
+				t.Fatalf("audit after writing to every owner: %+v", report)
+			}
+
+			// c moves ahead on its own; a and b are stale
+			if err := tt.diverge(cluster["c"].storage); err != nil {
+				t.Fatalf("diverge: %v", err)
+			}
+			report := audit(t, cluster["a"], false)
+			keys := divergentKeys(report)
+			divergent := keys["user:1"]
+			if report.IsConsistent || len(keys) != 1 || divergent == nil {
+				t.Fatalf("divergent keys %v, want only user:1", keys)
+			}
+			if divergent.Winner != "c" || len(divergent.Siblings) != 0 || divergent.MetadataOnly != tt.metadataOnly {
+				t.Errorf("user:1 winner %s, siblings %v, metadata only %t; want c, none, %t",
+					divergent.Winner, divergent.Siblings, divergent.MetadataOnly, tt.metadataOnly)
+			}
+			if got := divergent.Versions["c"]; !got.Found || got.Deleted != tt.deleted {
+				t.Errorf("c's version %+v, want found with deleted %t", got, tt.deleted)
+			}
+
+			report = audit(t, cluster["a"], true)
+			if report.RepairedKeys != 2 || report.FailedRepairs != 0 {
+				t.Errorf("repaired %d and failed %d replicas, want a and b repaired", report.RepairedKeys, report.FailedRepairs)
+			}
+			if report := audit(t, cluster["a"], false); !report.IsConsistent {
+				t.Errorf("still divergent after repair: %v", divergentKeys(report))
+			}
+
+			want, _ := cluster["c"].storage.GetVersion("user:1")
+			for _, id := range []string{"a", "b"} {
+				got, err := cluster[id].storage.GetVersion("user:1")
+				if err != nil || got.Deleted != tt.deleted || got.Value != want.Value ||
+					got.GetVectorClock().Compare(want.GetVectorClock()) != storage.Equal {
+					t.Errorf("%s holds %+v, %v after repair; want c's version %+v", id, got, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditLeavesConcurrentVersionsAlone(t *testing.T) {
+	cluster := newAuditCluster(t, replication.ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c")
+	writeAll(t, cluster["a"], "user:1", "v1")
+
+	// b and c each write on top of v1 without seeing the other's write
+	cluster["b"].storage.Put("user:1", "from-b")
+	cluster["c"].storage.Put("user:1", "from-c")
+
+	report := audit(t, cluster["a"], true)
+	divergent := divergentKeys(report)["user:1"]
+	if divergent == nil || len(divergent.Siblings) != 1 {
+		t.Fatalf("user:1 audited as %+v, want a winner with one sibling", divergent)
+	}
+	if len(divergent.Repaired) != 0 || report.RepairedKeys != 0 {
+		t.Errorf("repaired %v, want concurrent versions left for the client", divergent.Repaired)
+	}
+	for id, want := range map[string]string{"b": "from-b", "c": "from-c"} {
+		if got, _ := cluster[id].storage.Get("user:1"); got == nil || got.Value != want {
+			t.Errorf("%s holds %+v after the audit, want %s", id, got, want)
+		}
+	}
+}
+
+func TestAuditChecksKeysAgainstTheirOwnN(t *testing.T) {
+	cluster := newAuditCluster(t, replication.ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c", "d")
+	for _, n := range cluster {
+		if err := n.replicator.SetNamespaceConfig("solo", &replication.ReplicationConfig{N: 1, R: 1, W: 1}); err != nil {
+			t.Fatalf("SetNamespaceConfig: %v", err)
+		}
+	}
+	writeAll(t, cluster["a"], "user:1", "v1")
+	writeAll(t, cluster["a"], "solo:1", "v1")
+
+	// solo:1 lives on one node only; the others don't miss it
+	report := audit(t, cluster["a"], false)
+	if !report.IsConsistent {
+		t.Errorf("audit flagged %v", divergentKeys(report))
+	}
+
+	if want := len(cluster["a"].handler.ring.GetRanges(3)) + 1; report.RangesChecked != want {
+		t.Errorf("checked %d ranges, want every N=3 range and solo:1's N=1 range (%d)", report.RangesChecked, want)
+	}
+}
@@ -2,15 +2,19 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
+	"dynamodb/internal/membership"
 	"dynamodb/internal/node"
 	"dynamodb/internal/replication"
 	"dynamodb/internal/ring"
+	"dynamodb/internal/rpc"
 	"dynamodb/internal/storage"
+	"dynamodb/internal/transport"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -28,15 +32,21 @@ type Handler struct {
 	currentNode *node.Node
 	storage     *storage.LevelDBStorage
 	replicator  *replication.Replicator
+	members     *membership.Service
+	transport   *transport.Client
+	// Set when inter-node traffic goes over gRPC instead of HTTP
+	rpc *rpc.Client
 }
 
 // NewHandler creates a new API handler
-func NewHandler(hashRing *ring.ConsistentHashRing, currentNode *node.Node, localStorage *storage.LevelDBStorage, replicator *replication.Replicator) *Handler {
+func NewHandler(hashRing *ring.ConsistentHashRing, currentNode *node.Node, localStorage *storage.LevelDBStorage, replicator *replication.Replicator, members *membership.Service, client *transport.Client) *Handler {
 	return &Handler{
 		ring:        hashRing,
 		currentNode: currentNode,
 		storage:     localStorage,
 		replicator:  replicator,
+		members:     members,
+		transport:   client,
 	}
 }
 
@@ -71,6 +81,11 @@ func (h *Handler) GetRing(c *gin.Context) {
 func (h *Handler) PutData(c *gin.Context) {
 	key := c.Param("key")
 
+	// Writes are coordinated by one of the key's owners
+	if h.forwardToOwner(c, key) {
+		return
+	}
+
 	var data struct {
 		Value string `json:"value" binding:"required"`
 	}
@@ -80,10 +95,16 @@ func (h *Handler) PutData(c *gin.Context) {
 		return
 	}
 
+	opts, err := parseConsistencyOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Use replication system for distributed write with vector clock sync
-	result, err := h.replicator.WriteWithReplication(key, data.Value)
+	result, err := h.replicator.WriteWithReplication(key, data.Value, opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(writeErrorStatus(err), gin.H{
 			"error":  err.Error(),
 			"result": result,
 		})
@@ -92,14 +113,16 @@ func (h *Handler) PutData(c *gin.Context) {
 
 	// Find which node should handle this key
 	responsibleNode := h.ring.GetNodeForKey(key)
-	replicationNodes := h.ring.GetNodesForKey(key, 3)
+	replicationNodes := h.ring.GetNodesForKey(key, h.replicator.ConfigForKey(key).N)
 
 	// Get the current vector clock state after the write
 	eventLog := h.storage.GetEventLog()
 
-	c.JSON(http.StatusOK, gin.H{
+	c.JSON(writeSuccessStatus(result), gin.H{
 		"key":                key,
 		"value":              data.Value,
+		"status":             result.Status,
+		"coordinator":        h.currentNode.ID,
 		"responsible_node":   responsibleNode.ID,
 		"replication_nodes":  getNodeIDs(replicationNodes),
 		"replication_result": result,
@@ -113,19 +136,42 @@ func (h *Handler) PutData(c *gin.Context) {
 func (h *Handler) GetData(c *gin.Context) {
 	key := c.Param("key")
 
+	opts, err := parseConsistencyOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Use replication system for distributed read
-	result, err := h.replicator.ReadWithQuorum(key)
+	result, err := h.replicator.ReadWithQuorum(key, opts)
+	if consistencyErr, ok := err.(*replication.ConsistencyError); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": consistencyErr.Error()})
+		return
+	}
+	if err == storage.ErrKeyNotFound {
+		response := gin.H{"error": err.Error()}
+		if result != nil && result.VectorClock != nil {
+			response["vector_clock"] = result.VectorClock // The delete the read saw
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":       err.Error(),
+			"read_result": result,
+		})
 		return
 	}
 
 	responsibleNode := h.ring.GetNodeForKey(key)
-	replicationNodes := h.ring.GetNodesForKey(key, 3)
+	replicationNodes := h.ring.GetNodesForKey(key, h.replicator.ConfigForKey(key).N)
 
 	c.JSON(http.StatusOK, gin.H{
 		"key":               key,
 		"value":             result.Value,
+		"vector_clock":      result.VectorClock,
+		"siblings":          result.Siblings,
 		"responsible_node":  responsibleNode.ID,
 		"replication_nodes": getNodeIDs(replicationNodes),
 		"read_result":       result,
@@ -137,22 +183,23 @@ func (h *Handler) GetData(c *gin.Context) {
 func (h *Handler) DeleteData(c *gin.Context) {
 	key := c.Param("key")
 
-	// Check if key exists first
-	exists, err := h.storage.Exists(key)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Deletes are coordinated by one of the key's owners
+	if h.forwardToOwner(c, key) {
 		return
 	}
 
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Key not found"})
+	// Even without a local copy the delete goes ahead: another replica may
+	// hold the key, and the tombstone has to win over it
+	opts, err := parseConsistencyOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Use replication system for distributed delete with vector clock sync
-	result, err := h.replicator.DeleteWithReplication(key)
+	result, err := h.replicator.DeleteWithReplication(key, opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(writeErrorStatus(err), gin.H{
 			"error":  err.Error(),
 			"result": result,
 		})
@@ -161,14 +208,16 @@ func (h *Handler) DeleteData(c *gin.Context) {
 
 	// Find which node should handle this key
 	responsibleNode := h.ring.GetNodeForKey(key)
-	replicationNodes := h.ring.GetNodesForKey(key, 3)
+	replicationNodes := h.ring.GetNodesForKey(key, h.replicator.ConfigForKey(key).N)
 
 	// Get the current vector clock state after the delete
 	eventLog := h.storage.GetEventLog()
 
-	c.JSON(http.StatusOK, gin.H{
+	c.JSON(writeSuccessStatus(result), gin.H{
 		"key":                key,
 		"message":            "Key deleted successfully",
+		"status":             result.Status,
+		"coordinator":        h.currentNode.ID,
 		"responsible_node":   responsibleNode.ID,
 		"replication_nodes":  getNodeIDs(replicationNodes),
 		"replication_result": result,
@@ -196,6 +245,24 @@ func (h *Handler) HandleReplication(c *gin.Context) {
 	}
 }
 
+// HandleBatchReplication applies a batch of queued mutations from another node
+func (h *Handler) HandleBatchReplication(c *gin.Context) {
+	var batch replication.BatchReplicationRequest
+
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := h.replicator.HandleBatchReplication(&batch)
+
+	if response.Error == "" {
+		c.JSON(http.StatusOK, response)
+	} else {
+		c.JSON(http.StatusInternalServerError, response)
+	}
+}
+
 // GetStorageStats returns detailed storage statistics
 func (h *Handler) GetStorageStats(c *gin.Context) {
 	stats := h.storage.GetStats()
@@ -223,40 +290,19 @@ func (h *Handler) WebSocketHandler(c *gin.Context) {
 	// Helper function to get enhanced node information with health status
 	getEnhancedNodeInfo := func() []map[string]interface{} {
 		nodes := h.ring.GetAllNodes()
-		replicationStatus := h.replicator.GetReplicationStatus()
 
 		nodeInfos := make([]map[string]interface{}, len(nodes))
 		for i, node := range nodes {
 			nodeInfo := node.GetInfo()
 
-			// Check if this node is alive according to replication system
-			aliveNodes, _ := replicationStatus["alive_nodes"].(int)
-			totalNodes, _ := replicationStatus["total_nodes"].(int)
-
-			// For now, mark node as failed if we don't have quorum and this isn't the current node
-			isCurrentNode := node.ID == h.currentNode.ID
-			if isCurrentNode {
-				// Current node is always considered alive
-				nodeInfo["status"] = "alive"
+			// Liveness comes from the membership service, like everywhere else
+			if member, exists := h.members.Member(node.ID); exists {
+				nodeInfo["status"] = member.Status
 				nodeInfo["health_status"] = map[string]interface{}{
-					"is_alive":      true,
-					"failure_count": 0,
-				}
-			} else {
-				// For remote nodes, check if we have fewer alive nodes than total
-				// This is a simplified check - in real implementation we'd use the actual health map
-				if aliveNodes < totalNodes {
-					nodeInfo["status"] = "failed"
-					nodeInfo["health_status"] = map[string]interface{}{
-						"is_alive":      false,
-						"failure_count": 1,
-					}
-				} else {
-					nodeInfo["status"] = "alive"
-					nodeInfo["health_status"] = map[string]interface{}{
-						"is_alive":      true,
-						"failure_count": 0,
-					}
+					"is_alive": member.IsAlive,
+					"status":   member.Status,
+					"phi":      member.Phi,
+					"since":    member.Since.Unix(),
 				}
 			}
 
@@ -318,9 +364,8 @@ func (h *Handler) JoinCluster(c *gin.Context) {
 		return
 	}
 
-	// Create and add the new node to our ring
-	newNode := node.NewNode(req.NodeID, req.Address)
-	h.ring.AddNode(newNode)
+	// The membership service puts the new node on our ring
+	h.members.Join(req.NodeID, req.Address)
 
 	// Get updated ring info
 	ringInfo := h.ring.GetRingInfo()
@@ -685,8 +730,10 @@ func (h *Handler) copyKeyFromTarget(key string, targetNode *node.Node) error {
 	// Fetch the key from the target node
 	url := fmt.Sprintf("http://%s/api/v1/data/%s", targetNode.Address, key)
 	
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := h.transport.Get(ctx, url)
 	if err != nil {
 		return fmt.Errorf("failed to fetch key from target: %v", err)
 	}
@@ -744,14 +791,16 @@ func (h *Handler) pushKeyToTarget(key string, targetNode *node.Node) error {
 		return fmt.Errorf("failed to marshal payload: %v", err)
 	}
 	
-	client := &http.Client{Timeout: 5 * time.Second}
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	
-	resp, err := client.Do(req)
+	resp, err := h.transport.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send key to target: %v", err)
 	}
@@ -769,8 +818,10 @@ func (h *Handler) getValueWithVectorClock(key string, targetNode *node.Node) (*s
 	// For now, just get the regular value - vector clock comparison can be enhanced later
 	url := fmt.Sprintf("http://%s/api/v1/data/%s", targetNode.Address, key)
 	
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := h.transport.Get(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch key from target: %v", err)
 	}
@@ -950,13 +1001,19 @@ func (h *Handler) SyncVectorClocks(c *gin.Context) {
 	})
 }
 
-// fetchMerkleTreeFromNode makes HTTP request to get Merkle tree from another node
+// fetchMerkleTreeFromNode gets the Merkle tree of another node over HTTP or gRPC
 func (h *Handler) fetchMerkleTreeFromNode(targetNode *node.Node) (*storage.MerkleTree, error) {
+	if h.rpc != nil {
+		return h.fetchMerkleTreeRPC(targetNode)
+	}
+
 	// Make actual HTTP request to target node
 	url := fmt.Sprintf("http://%s/api/v1/merkle-tree", targetNode.Address)
 	
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(url)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := h.transport.Get(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch Merkle tree from %s: %v", targetNode.ID, err)
 	}
@@ -993,6 +1050,116 @@ func (h *Handler) HandleWebSocket(c *gin.Context) {
 	h.WebSocketHandler(c)
 }
 
+// GetReplicationConfig returns the cluster-wide and per-namespace N/R/W settings
+func (h *Handler) GetReplicationConfig(c *gin.Context) {
+	status := h.replicator.GetReplicationStatus()
+
+	c.JSON(http.StatusOK, gin.H{
+		"replication_factor": status["replication_factor"],
+		"read_quorum":        status["read_quorum"],
+		"write_quorum":       status["write_quorum"],
+		"strong_consistency": status["strong_consistency"],
+		"namespaces":         status["namespaces"],
+		"timestamp":          time.Now().Unix(),
+	})
+}
+
+// GetTransportMetrics returns connection pool and per-peer call metrics for
+// node-to-node traffic
+func (h *Handler) GetTransportMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"node_id":   h.currentNode.ID,
+		"transport": h.transport.Metrics(),
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// GetHints returns the hinted handoff backlog per target node
+func (h *Handler) GetHints(c *gin.Context) {
+	hints := h.replicator.GetHintStats()
+	hints["node_id"] = h.currentNode.ID
+	hints["timestamp"] = time.Now().Unix()
+
+	c.JSON(http.StatusOK, hints)
+}
+
+// SetNamespaceConfig overrides N/R/W for a key namespace on every node
+func (h *Handler) SetNamespaceConfig(c *gin.Context) {
+	namespace := c.Param("namespace")
+
+	var config replication.ReplicationConfig
+	if err := c.ShouldBindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.replicator.ConfigureNamespace(namespace, &config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	effective := h.replicator.ConfigForKey(namespace + ":")
+	c.JSON(http.StatusOK, gin.H{
+		"namespace":          namespace,
+		"config":             effective,
+		"strong_consistency": effective.IsStrong(),
+		"timestamp":          time.Now().Unix(),
+	})
+}
+
+// parseConsistencyOptions reads the per-request consistency level and strong
+// consistency flag from the query string or the X-Consistency-Level and
+// X-Strong-Consistency headers
+func parseConsistencyOptions(c *gin.Context) (*replication.ConsistencyOptions, error) {
+	levelName := c.Query("consistency")
+	if levelName == "" {
+		levelName = c.GetHeader("X-Consistency-Level")
+	}
+
+	level, err := replication.ParseConsistencyLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+
+	strong := c.Query("strong")
+	if strong == "" {
+		strong = c.GetHeader("X-Strong-Consistency")
+	}
+
+	acceptPartial := c.Query("accept_partial")
+	if acceptPartial == "" {
+		acceptPartial = c.GetHeader("X-Accept-Partial")
+	}
+
+	return &replication.ConsistencyOptions{
+		Level:         level,
+		Strong:        strong == "true" || strong == "1",
+		AcceptPartial: acceptPartial == "true" || acceptPartial == "1",
+	}, nil
+}
+
+// writeErrorStatus maps a failed replicated write to an HTTP status
+func writeErrorStatus(err error) int {
+	switch err.(type) {
+	case *replication.ConsistencyError:
+		return http.StatusBadRequest
+	case *replication.QuorumError:
+		// Fewer than W replicas have the write; it may still be visible on some
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeSuccessStatus distinguishes writes that reached the write quorum
+// (200, durable or sloppy) from writes accepted below it (202)
+func writeSuccessStatus(result *replication.WriteResult) int {
+	if result.Status == replication.WriteStatusAccepted {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
+
 func getNodeIDs(nodes []*node.Node) []string {
 	ids := make([]string, len(nodes))
 	for i, node := range nodes {
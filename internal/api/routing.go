@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// forwardedByHeader marks a request one node forwarded to a key's owner, so
+// the owner coordinates it instead of forwarding it again. It is only
+// trusted from cluster members, see forwardedByMember.
+const forwardedByHeader = "X-Forwarded-By"
+
+// forwardTimeout bounds a request proxied to a key's owner
+const forwardTimeout = 5 * time.Second
+
+// forwardToOwner proxies a data request this node doesn't own to the first
+// alive owner of the key and relays its response. Returns false when this
+// node should coordinate the request itself: it owns the key, another member
+// already forwarded the request, or no owner could be reached.
+//
+// A write is only retried on the next owner if it never left this node
+// (e.g. connection refused). Once an owner may have received it, it may also
+// have applied it, and coordinating it again would turn one client write into
+// two concurrent versions, so the client gets a 504 instead.
+func (h *Handler) forwardToOwner(c *gin.Context, key string) bool {
+	if h.replicator.IsOwner(key) || h.forwardedByMember(c) {
+		return false
+	}
+
+	// Keep the body so we can retry other owners, or coordinate ourselves
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return true
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	for _, owner := range h.replicator.Owners(key) {
+		if !h.replicator.IsNodeAlive(owner.ID) {
+			continue
+		}
+
+		status, contentType, respBody, sent, err := h.forwardRequest(c, owner.Address, body)
+		if err != nil && sent && c.Request.Method != http.MethodGet {
+			fmt.Printf("⌛ %s %s forwarded to owner %s, outcome unknown: %v\n", c.Request.Method, key, owner.ID, err)
+			c.JSON(http.StatusGatewayTimeout, gin.H{
+				"error":       fmt.Sprintf("owner %s did not answer; the request may or may not have been applied", owner.ID),
+				"key":         key,
+				"coordinator": owner.ID,
+				"details":     err.Error(),
+			})
+			return true
+		}
+		if err != nil {
+			fmt.Printf("⚠️ Failed to forward %s %s to owner %s: %v\n", c.Request.Method, key, owner.ID, err)
+			continue
+		}
+
+		fmt.Printf("↪️ Forwarded %s %s to owner %s (%d)\n", c.Request.Method, key, owner.ID, status)
+		c.Header("X-Coordinator", owner.ID)
+		c.Data(status, contentType, respBody)
+		return true
+	}
+
+	fmt.Printf("🧭 No owner of %s reachable, coordinating without a local copy\n", key)
+	return false
+}
+
+// forwardedByMember reports whether another cluster member forwarded the
+// request: X-Forwarded-By has to name a member, and the request has to come
+// from that member's host. Clients can set the header too; taken on its own,
+// it would let them have any node coordinate a write it doesn't own.
+func (h *Handler) forwardedByMember(c *gin.Context) bool {
+	nodeID := c.GetHeader(forwardedByHeader)
+	if nodeID == "" {
+		return false
+	}
+
+	member, exists := h.members.Member(nodeID)
+	if !exists || nodeID == h.currentNode.ID || !sameHost(member.Address, c.Request.RemoteAddr) {
+		fmt.Printf("🚫 Ignoring %s: %s from %s\n", forwardedByHeader, nodeID, c.Request.RemoteAddr)
+		return false
+	}
+	return true
+}
+
+// sameHost reports whether a connection from remoteAddr comes from the host
+// of a member's address, resolving the member's host name if it has one
+func sameHost(memberAddress, remoteAddr string) bool {
+	remoteHost, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	remoteIP := net.ParseIP(remoteHost)
+	if remoteIP == nil {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(memberAddress)
+	if err != nil {
+		host = memberAddress
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.Equal(remoteIP)
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return false
+	}
+	for _, ip := range ips {
+		if ip.Equal(remoteIP) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardRequest replays the client's request on another node and returns
+// that node's response. sent reports whether the request reached the wire,
+// i.e. whether the other node may have acted on it even if err is set.
+func (h *Handler) forwardRequest(c *gin.Context, address string, body []byte) (status int, contentType string, respBody []byte, sent bool, err error) {
+	var wrote atomic.Bool
+	ctx, cancel := context.WithTimeout(c.Request.Context(), forwardTimeout)
+	defer cancel()
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteHeaders: func() { wrote.Store(true) },
+	})
+
+	endpoint := fmt.Sprintf("http://%s%s", address, c.Request.URL.RequestURI())
+	req, err := http.NewRequestWithContext(ctx, c.Request.Method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", nil, false, err
+	}
+	req.Header = c.Request.Header.Clone()
+	req.Header.Set(forwardedByHeader, h.currentNode.ID)
+
+	resp, err := h.transport.Do(req)
+	if err != nil {
+		return 0, "", nil, wrote.Load(), err
+	}
+	defer resp.Body.Close()
+
+	respBody, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", nil, true, err
+	}
+
+	return resp.StatusCode, resp.Header.Get("Content-Type"), respBody, true, nil
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"dynamodb/internal/membership"
+	"dynamodb/internal/node"
+	"dynamodb/internal/replication"
+	"dynamodb/internal/ring"
+	"dynamodb/internal/storage"
+	"dynamodb/internal/transport"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ownerBehavior is how a fake owner treats a forwarded request
+type ownerBehavior int
+
+const (
+	ownerAnswers ownerBehavior = iota // Coordinates it and answers 201
+	ownerDrops                        // Reads it, then hangs up without an answer
+	ownerRefuses                      // Isn't listening: the request never leaves
+)
+
+// fakeOwner is a key owner as seen from the forwarding node
+type fakeOwner struct {
+	address  string
+	requests atomic.Int64
+}
+
+func newFakeOwner(t *testing.T, id string, behavior ownerBehavior) *fakeOwner {
+	t.Helper()
+
+	owner := &fakeOwner{}
+	if behavior == ownerRefuses {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		owner.address = listener.Addr().String()
+		listener.Close()
+		return owner
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		owner.requests.Add(1)
+		if r.Header.Get(forwardedByHeader) == "" {
+			t.Errorf("%s got a request without %s", id, forwardedByHeader)
+		}
+
+		if behavior == ownerDrops {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"coordinator":%q}`, id)
+	}))
+	t.Cleanup(server.Close)
+	owner.address = strings.TrimPrefix(server.URL, "http://")
+	return owner
+}
+
+// forwardingSetup is a node that owns none of key's replicas, in front of
+// fake owners that behave as told, primary first
+type forwardingSetup struct {
+	router  *gin.Engine
+	members *membership.Service
+	key     string
+	owners  []string
+	fakes   map[string]*fakeOwner
+}
+
+func newForwardingSetup(t *testing.T, behaviors ...ownerBehavior) *forwardingSetup {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	ids := []string{"coordinator", "node-1", "node-2", "node-3"}
+	config := replication.DefaultReplicationConfig()
+
+	// Placement only depends on node IDs: find a key the coordinator doesn't own
+	placement := ring.NewConsistentHashRing()
+	for _, id := range ids {
+		placement.AddNode(node.NewNode(id, id))
+	}
+	setup := &forwardingSetup{fakes: make(map[string]*fakeOwner)}
+	for i := 0; setup.key == ""; i++ {
+		key := fmt.Sprintf("user:%d", i)
+		owners := placement.GetNodesForKey(key, config.N)
+		owned := false
+		for _, owner := range owners {
+			owned = owned || owner.ID == "coordinator"
+		}
+		if !owned {
+			setup.key = key
+			for _, owner := range owners {
+				setup.owners = append(setup.owners, owner.ID)
+			}
+		}
+	}
+
+	self := node.NewNode("coordinator", "127.0.0.1:1")
+	hashRing := ring.NewConsistentHashRing()
+	hashRing.AddNode(self)
+	members := membership.New(self, nil)
+	for i, id := range setup.owners {
+		behavior := ownerAnswers
+		if i < len(behaviors) {
+			behavior = behaviors[i]
+		}
+		fake := newFakeOwner(t, id, behavior)
+		setup.fakes[id] = fake
+		hashRing.AddNode(node.NewNode(id, fake.address))
+		members.Join(id, fake.address)
+	}
+
+	localStorage, err := storage.NewLevelDBStorage("coordinator", t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLevelDBStorage: %v", err)
+	}
+	network := transport.NewNetwork(1)
+	replicator := replication.NewReplicator(hashRing, localStorage, self, config,
+		replication.NewMemoryTransport(network, self.Address), members, nil)
+	t.Cleanup(func() {
+		replicator.Stop()
+		localStorage.Close()
+	})
+
+	handler := NewHandler(hashRing, self, localStorage, replicator, members, transport.NewClient(nil))
+	setup.members = members
+	setup.router = gin.New()
+	setup.router.PUT("/api/v1/data/:key", handler.PutData)
+	setup.router.DELETE("/api/v1/data/:key", handler.DeleteData)
+	return setup
+}
+
+func (s *forwardingSetup) send(method string) *httptest.ResponseRecorder {
+	return s.sendFrom(method, "", "192.0.2.1:1234")
+}
+
+// sendFrom sends a request from remoteAddr, claiming to be forwarded by
+// forwardedBy unless it is empty
+func (s *forwardingSetup) sendFrom(method, forwardedBy, remoteAddr string) *httptest.ResponseRecorder {
+	body := strings.NewReader(`{"value":"v1"}`)
+	req := httptest.NewRequest(method, "/api/v1/data/"+s.key, body)
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = remoteAddr
+	if forwardedBy != "" {
+		req.Header.Set(forwardedByHeader, forwardedBy)
+	}
+
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestForwardRetriesNextOwnerWhenNeverSent(t *testing.T) {
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			s := newForwardingSetup(t, ownerRefuses, ownerAnswers)
+
+			resp := s.send(method)
+			if resp.Code != http.StatusCreated {
+				t.Fatalf("status %d, want 201 relayed from the second owner: %s", resp.Code, resp.Body)
+			}
+			if got := resp.Header().Get("X-Coordinator"); got != s.owners[1] {
+				t.Errorf("X-Coordinator = %q, want %s", got, s.owners[1])
+			}
+			if got := s.fakes[s.owners[1]].requests.Load(); got != 1 {
+				t.Errorf("second owner got %d requests, want 1", got)
+			}
+		})
+	}
+}
+
+func TestForwardAnswers504WhenOwnerMayHaveApplied(t *testing.T) {
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			s := newForwardingSetup(t, ownerDrops, ownerAnswers)
+
+			resp := s.send(method)
+			if resp.Code != http.StatusGatewayTimeout {
+				t.Fatalf("status %d, want 504: %s", resp.Code, resp.Body)
+			}
+			if !strings.Contains(resp.Body.String(), s.owners[0]) {
+				t.Errorf("504 body doesn't name owner %s: %s", s.owners[0], resp.Body)
+			}
+
+			// Sending it on would risk applying the write twice
+			if got := s.fakes[s.owners[0]].requests.Load(); got != 1 {
+				t.Errorf("first owner got %d requests, want 1", got)
+			}
+			if got := s.fakes[s.owners[1]].requests.Load(); got != 0 {
+				t.Errorf("second owner got %d requests after the first may have applied it, want 0", got)
+			}
+		})
+	}
+}
+
+func TestForwardSkipsOwnersNotAlive(t *testing.T) {
+	s := newForwardingSetup(t, ownerAnswers, ownerAnswers)
+	s.members.Update(s.owners[0], s.fakes[s.owners[0]].address, "dead", 0)
+
+	resp := s.send(http.MethodPut)
+	if got := resp.Header().Get("X-Coordinator"); resp.Code != http.StatusCreated || got != s.owners[1] {
+		t.Fatalf("status %d from %q, want 201 from %s", resp.Code, got, s.owners[1])
+	}
+	if got := s.fakes[s.owners[0]].requests.Load(); got != 0 {
+		t.Errorf("dead owner got %d requests, want 0", got)
+	}
+}
+
+func TestForwardedByOnlyTrustedFromMembers(t *testing.T) {
+	tests := []struct {
+		name        string
+		forwardedBy func(s *forwardingSetup) string
+		remoteAddr  string
+		forwarded   bool // Whether the node still forwards to the owner
+	}{
+		{
+			name:        "unknown node",
+			forwardedBy: func(s *forwardingSetup) string { return "intruder" },
+			remoteAddr:  "127.0.0.1:5555",
+			forwarded:   true,
+		},
+		{
+			name:        "member name from another host",
+			forwardedBy: func(s *forwardingSetup) string { return s.owners[0] },
+			remoteAddr:  "192.0.2.1:1234",
+			forwarded:   true,
+		},
+		{
+			name:        "itself",
+			forwardedBy: func(s *forwardingSetup) string { return "coordinator" },
+			remoteAddr:  "127.0.0.1:5555",
+			forwarded:   true,
+		},
+		{
+			name:        "member from its host",
+			forwardedBy: func(s *forwardingSetup) string { return s.owners[0] },
+			remoteAddr:  "127.0.0.1:5555",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newForwardingSetup(t, ownerAnswers)
+
+			resp := s.sendFrom(http.MethodPut, tt.forwardedBy(s), tt.remoteAddr)
+			forwarded := s.fakes[s.owners[0]].requests.Load() > 0
+			if forwarded != tt.forwarded {
+				t.Errorf("forwarded to the owner: %t, want %t (status %d)", forwarded, tt.forwarded, resp.Code)
+			}
+		})
+	}
+}
+
+func TestOwnerDeletesKeyItHasNoCopyOf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	self := node.NewNode("owner", "127.0.0.1:1")
+	hashRing := ring.NewConsistentHashRing()
+	hashRing.AddNode(self)
+	members := membership.New(self, nil)
+
+	localStorage, err := storage.NewLevelDBStorage("owner", t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLevelDBStorage: %v", err)
+	}
+	replicator := replication.NewReplicator(hashRing, localStorage, self, &replication.ReplicationConfig{N: 1, R: 1, W: 1},
+		replication.NewMemoryTransport(transport.NewNetwork(1), self.Address), members, nil)
+	t.Cleanup(func() {
+		replicator.Stop()
+		localStorage.Close()
+	})
+
+	router := gin.New()
+	router.DELETE("/api/v1/data/:key", NewHandler(hashRing, self, localStorage, replicator, members, transport.NewClient(nil)).DeleteData)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/data/user:1", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200: %s", recorder.Code, recorder.Body)
+	}
+	if tombstone, err := localStorage.GetVersion("user:1"); err != nil || !tombstone.Deleted {
+		t.Errorf("owner holds %+v, %v; want a tombstone", tombstone, err)
+	}
+}
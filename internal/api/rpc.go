@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"dynamodb/internal/node"
+	"dynamodb/internal/rpc"
+	"dynamodb/internal/rpc/internodepb"
+	"dynamodb/internal/storage"
+
+	"google.golang.org/grpc"
+)
+
+// merkleChunkSize is the number of leaves per streamed Merkle tree message
+const merkleChunkSize = 256
+
+// UseGRPC fetches other nodes' Merkle trees over gRPC instead of HTTP
+func (h *Handler) UseGRPC(client *rpc.Client) {
+	h.rpc = client
+}
+
+// RegisterRPC exposes the anti-entropy service on a gRPC server
+func (h *Handler) RegisterRPC(server *grpc.Server) {
+	internodepb.RegisterAntiEntropyServer(server, &antiEntropyServer{handler: h})
+}
+
+// antiEntropyServer serves the AntiEntropy gRPC service
+type antiEntropyServer struct {
+	internodepb.UnimplementedAntiEntropyServer
+	handler *Handler
+}
+
+func (s *antiEntropyServer) StreamMerkleTree(req *internodepb.MerkleTreeRequest, stream internodepb.AntiEntropy_StreamMerkleTreeServer) error {
+	tree, err := s.handler.storage.BuildMerkleTree()
+	if err != nil {
+		return err
+	}
+
+	// Always send at least one chunk so an empty tree still carries its root
+	for start := 0; start == 0 || start < len(tree.Leaves); start += merkleChunkSize {
+		end := start + merkleChunkSize
+		if end > len(tree.Leaves) {
+			end = len(tree.Leaves)
+		}
+
+		chunk := &internodepb.MerkleLeafBatch{
+			NodeId:   tree.NodeID,
+			RootHash: tree.Root.Hash,
+			KeyCount: int32(tree.KeyCount),
+			Leaves:   make([]*internodepb.MerkleLeaf, 0, end-start),
+		}
+		for _, leaf := range tree.Leaves[start:end] {
+			chunk.Leaves = append(chunk.Leaves, rpc.LeafToProto(leaf))
+		}
+
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchMerkleTreeRPC streams another node's Merkle leaves over gRPC and
+// rebuilds the tree locally, checking it against the sender's root hash
+func (h *Handler) fetchMerkleTreeRPC(targetNode *node.Node) (*storage.MerkleTree, error) {
+	conn, err := h.rpc.Conn(targetNode.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Merkle tree from %s: %v", targetNode.ID, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := internodepb.NewAntiEntropyClient(conn).StreamMerkleTree(ctx, &internodepb.MerkleTreeRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Merkle tree from %s: %v", targetNode.ID, err)
+	}
+
+	var nodeID, rootHash string
+	leaves := make([]*storage.MerkleNode, 0)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch Merkle tree from %s: %v", targetNode.ID, err)
+		}
+
+		nodeID, rootHash = chunk.NodeId, chunk.RootHash
+		for _, leaf := range chunk.Leaves {
+			merkleLeaf := rpc.LeafFromProto(leaf)
+			merkleLeaf.Position = len(leaves)
+			leaves = append(leaves, merkleLeaf)
+		}
+	}
+
+	tree := storage.NewMerkleTreeFromLeaves(nodeID, leaves)
+	if tree.Root.Hash != rootHash {
+		return nil, fmt.Errorf("merkle tree from %s failed verification: root %s, expected %s",
+			targetNode.ID, tree.Root.Hash, rootHash)
+	}
+
+	fmt.Printf("✅ Successfully fetched Merkle tree from %s over gRPC (%d keys)\n",
+		targetNode.ID, tree.KeyCount)
+
+	return tree, nil
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"dynamodb/internal/node"
+	"dynamodb/internal/rpc"
+	"dynamodb/internal/storage"
+)
+
+// serveMerkleTrees serves a node's AntiEntropy service on a loopback port and
+// returns the node, addressed by its gRPC address (port offset 0), with the
+// storage behind it
+func serveMerkleTrees(t *testing.T, id string) (*node.Node, *storage.LevelDBStorage) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	self := node.NewNode(id, listener.Addr().String())
+
+	localStorage, err := storage.NewLevelDBStorage(id, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLevelDBStorage: %v", err)
+	}
+
+	server := rpc.NewServer()
+	NewHandler(nil, self, localStorage, nil, nil, nil).RegisterRPC(server)
+	go server.Serve(listener)
+
+	t.Cleanup(func() {
+		server.Stop()
+		localStorage.Close()
+	})
+	return self, localStorage
+}
+
+func TestStreamMerkleTreeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    int
+		deleted int // How many of the keys are tombstones
+	}{
+		{name: "empty", keys: 0},
+		{name: "one chunk", keys: 10, deleted: 2},
+		{name: "several chunks", keys: 2*merkleChunkSize + 7, deleted: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, targetStorage := serveMerkleTrees(t, "b")
+			for i := 0; i < tt.keys; i++ {
+				if err := targetStorage.Put(fmt.Sprintf("user:%04d", i), "v1"); err != nil {
+					t.Fatalf("Put: %v", err)
+				}
+			}
+			for i := 0; i < tt.deleted; i++ {
+				if err := targetStorage.Delete(fmt.Sprintf("user:%04d", i)); err != nil {
+					t.Fatalf("Delete: %v", err)
+				}
+			}
+			want, err := targetStorage.BuildMerkleTree()
+			if err != nil {
+				t.Fatalf("BuildMerkleTree: %v", err)
+			}
+
+			client := rpc.NewClient(0)
+			defer client.Close()
+			h := NewHandler(nil, node.NewNode("a", "127.0.0.1:1"), nil, nil, nil, nil)
+			h.UseGRPC(client)
+
+			tree, err := h.fetchMerkleTreeRPC(target)
+			if err != nil {
+				t.Fatalf("fetchMerkleTreeRPC: %v", err)
+			}
+			if tree.Root.Hash != want.Root.Hash || tree.KeyCount != want.KeyCount || tree.NodeID != "b" {
+				t.Errorf("fetched tree of %s with %d keys, root %s; want b's %d keys, root %s",
+					tree.NodeID, tree.KeyCount, tree.Root.Hash, want.KeyCount, want.Root.Hash)
+			}
+
+			// Tombstones come through as leaves, so a delete can be repaired
+			deleted := 0
+			for _, leaf := range tree.Leaves {
+				if leaf.Deleted {
+					deleted++
+				}
+			}
+			if deleted != tt.deleted {
+				t.Errorf("fetched %d tombstone leaves, want %d", deleted, tt.deleted)
+			}
+		})
+	}
+}
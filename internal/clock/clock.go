@@ -0,0 +1,66 @@
+// Package clock abstracts time and background scheduling so that gossip,
+// replication and storage can run against simulated time. Production code
+// uses Real(); the simulation harness drives a Simulated clock by hand.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of time and the scheduler for background work
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	// After delivers the time on the returned channel once d has passed
+	After(d time.Duration) <-chan time.Time
+	// Every calls f every interval until ctx is done
+	Every(ctx context.Context, interval time.Duration, f func())
+	// Go runs f on its own goroutine
+	Go(f func())
+	// WithTimeout returns a context that is done once d has passed or
+	// parent is done
+	WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc)
+}
+
+// Real returns the wall clock
+func Real() Clock {
+	return realClock{}
+}
+
+// OrReal returns c, or the wall clock if c is nil
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real()
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Go(f func())                            { go f() }
+
+func (realClock) WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, d)
+}
+
+func (realClock) Every(ctx context.Context, interval time.Duration, f func()) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f()
+			}
+		}
+	}()
+}
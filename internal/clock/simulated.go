@@ -0,0 +1,308 @@
+package clock
+
+import (
+	"container/heap"
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Settling waits until the clock has seen no activity for settleYields
+// yields of the processor in a row, and still none after a settlePoll pause
+const (
+	settleYields = 200
+	settlePoll   = 100 * time.Microsecond
+)
+
+// Simulated is a clock that only moves when Advance is called, and a
+// scheduler for the goroutines started through Go. Go queues a goroutine
+// instead of starting it; the clock starts the queued goroutines when it
+// settles, and fires timers in deadline order (ties in the order they were
+// set), settling after each one.
+//
+// Settling waits for the goroutines the clock started to return or go quiet:
+// none of them touched the clock (read the time, set a timer, started or
+// finished a goroutine) for a few polls. The goroutines still run
+// concurrently on the Go runtime, so the clock makes runs repeatable in
+// simulated time, not deterministic: two runs with the same inputs can
+// interleave goroutines woken at the same instant differently.
+type Simulated struct {
+	mu       sync.Mutex
+	now      time.Time
+	seq      uint64
+	timers   timerQueue
+	queued   []func() // goroutines waiting to be started, in order
+	running  int      // goroutines started through Go that haven't returned
+	activity uint64   // bumped every time the clock is used
+}
+
+// NewSimulated creates a simulated clock starting at start
+func NewSimulated(start time.Time) *Simulated {
+	return &Simulated{now: start}
+}
+
+// Now returns the simulated time
+func (s *Simulated) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.activity++
+	return s.now
+}
+
+// Since returns the simulated time elapsed since t
+func (s *Simulated) Since(t time.Time) time.Duration {
+	return s.Now().Sub(t)
+}
+
+// Sleep blocks until the clock has been advanced by d
+func (s *Simulated) Sleep(d time.Duration) {
+	<-s.After(d)
+}
+
+// After delivers the simulated time once the clock has been advanced by d
+func (s *Simulated) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	s.schedule(d, func(now time.Time) {
+		ch <- now
+	})
+	return ch
+}
+
+// Every queues f each time interval passes, until ctx is done
+func (s *Simulated) Every(ctx context.Context, interval time.Duration, f func()) {
+	var tick func(time.Time)
+	tick = func(time.Time) {
+		if ctx.Err() != nil {
+			return
+		}
+		s.Go(f)
+		s.schedule(interval, tick)
+	}
+	s.schedule(interval, tick)
+}
+
+// Go queues f to run on its own goroutine. It starts the next time the clock
+// settles.
+func (s *Simulated) Go(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running++
+	s.activity++
+	s.queued = append(s.queued, f)
+}
+
+// WithTimeout returns a context that expires once the clock has been
+// advanced by d, or when parent is done
+func (s *Simulated) WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	timeout := &simContext{Context: ctx, deadline: s.Now().Add(d)}
+
+	s.schedule(d, func(time.Time) {
+		timeout.expire()
+		cancel()
+	})
+	return timeout, cancel
+}
+
+// Advance moves the clock forward by d, firing every timer that falls due in
+// deadline order and settling after each one
+func (s *Simulated) Advance(d time.Duration) {
+	s.Settle()
+
+	s.mu.Lock()
+	target := s.now.Add(d)
+	s.mu.Unlock()
+
+	for {
+		s.mu.Lock()
+		if len(s.timers) == 0 || s.timers[0].at.After(target) {
+			s.now = target
+			s.mu.Unlock()
+			break
+		}
+
+		next := heap.Pop(&s.timers).(*simTimer)
+		s.now = next.at
+		s.activity++
+		s.mu.Unlock()
+
+		next.fire(next.at)
+		s.Settle()
+	}
+
+	s.Settle()
+}
+
+// Settle starts the queued goroutines and waits for the goroutines the
+// clock knows about to go quiet, repeating until nothing is queued
+func (s *Simulated) Settle() {
+	for {
+		s.mu.Lock()
+		queued := s.queued
+		s.queued = nil
+		s.mu.Unlock()
+
+		for _, f := range queued {
+			go s.run(f)
+		}
+		s.waitQuiet()
+
+		s.mu.Lock()
+		done := len(s.queued) == 0
+		s.mu.Unlock()
+		if done {
+			return
+		}
+	}
+}
+
+// Pending returns the number of timers waiting to fire
+func (s *Simulated) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.timers)
+}
+
+// Running returns the number of goroutines started through Go that haven't
+// returned yet, including queued ones
+func (s *Simulated) Running() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.running
+}
+
+// run runs a goroutine started through Go and counts it out when it returns
+func (s *Simulated) run(f func()) {
+	defer func() {
+		s.mu.Lock()
+		s.running--
+		s.activity++
+		s.mu.Unlock()
+	}()
+	f()
+}
+
+// waitQuiet returns once every goroutine started through Go has returned, or
+// the clock saw no activity for a while: the goroutines left are blocked on
+// the clock, on each other or on something outside
+func (s *Simulated) waitQuiet() {
+	last := s.activityCount()
+	for quiet := 0; ; {
+		if s.Running() == 0 && s.queuedCount() == 0 {
+			return
+		}
+
+		if quiet < settleYields {
+			runtime.Gosched()
+		} else {
+			time.Sleep(settlePoll)
+		}
+
+		current := s.activityCount()
+		if current != last {
+			last = current
+			quiet = 0
+			continue
+		}
+		if quiet == settleYields {
+			return
+		}
+		quiet++
+	}
+}
+
+func (s *Simulated) activityCount() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.activity
+}
+
+func (s *Simulated) queuedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.queued)
+}
+
+// schedule registers fire to run once d has passed
+func (s *Simulated) schedule(d time.Duration, fire func(time.Time)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d < 0 {
+		d = 0
+	}
+	s.seq++
+	s.activity++
+	heap.Push(&s.timers, &simTimer{at: s.now.Add(d), seq: s.seq, fire: fire})
+}
+
+// simContext is a context whose deadline is in simulated time
+type simContext struct {
+	context.Context
+	deadline time.Time
+
+	mu      sync.Mutex
+	expired bool
+}
+
+func (c *simContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *simContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.expired {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
+
+// expire marks the context as timed out, unless it was cancelled before
+func (c *simContext) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Context.Err() == nil {
+		c.expired = true
+	}
+}
+
+// simTimer is one pending timer
+type simTimer struct {
+	at   time.Time
+	seq  uint64
+	fire func(time.Time)
+}
+
+// timerQueue orders timers by deadline, then by the order they were set
+type timerQueue []*simTimer
+
+func (q timerQueue) Len() int { return len(q) }
+
+func (q timerQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at.Before(q[j].at)
+}
+
+func (q timerQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *timerQueue) Push(x interface{}) { *q = append(*q, x.(*simTimer)) }
+
+func (q *timerQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
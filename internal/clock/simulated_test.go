@@ -0,0 +1,133 @@
+package clock
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects events from several goroutines
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func TestTimersFireInDeadlineOrder(t *testing.T) {
+	clk := NewSimulated(time.Unix(0, 0))
+	var r recorder
+
+	// Timers are set here, in this order; the goroutines only wait on them
+	for _, timer := range []struct {
+		name  string
+		after time.Duration
+	}{{"c", 3 * time.Second}, {"a", time.Second}, {"b1", 2 * time.Second}, {"b2", 2 * time.Second}} {
+		name, fired := timer.name, clk.After(timer.after)
+		clk.Go(func() {
+			<-fired
+			r.add(name)
+		})
+	}
+
+	clk.Advance(2 * time.Second)
+	if got, want := r.get(), []string{"a", "b1", "b2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after 2s fired %v, want %v", got, want)
+	}
+	clk.Advance(time.Second)
+	if got, want := r.get(), []string{"a", "b1", "b2", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after 3s fired %v, want %v", got, want)
+	}
+	if got := clk.Running(); got != 0 {
+		t.Errorf("%d goroutines still running", got)
+	}
+}
+
+func TestGoStartsOnSettle(t *testing.T) {
+	clk := NewSimulated(time.Unix(0, 0))
+	var r recorder
+
+	clk.Go(func() { r.add("first") })
+	clk.Go(func() { r.add("second") })
+	if got := r.get(); len(got) != 0 {
+		t.Fatalf("goroutines ran before the clock settled: %v", got)
+	}
+
+	clk.Settle()
+	got := r.get()
+	sort.Strings(got)
+	if want := []string{"first", "second"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+}
+
+func TestSettleWaitsForWokenGoroutines(t *testing.T) {
+	clk := NewSimulated(time.Unix(0, 0))
+	var r recorder
+
+	// A goroutine woken by a timer hands off to another one, which has to
+	// finish before the next timer fires
+	handoff := make(chan string)
+	clk.Go(func() {
+		for event := range handoff {
+			r.add(event)
+		}
+	})
+	clk.Go(func() {
+		clk.Sleep(time.Second)
+		handoff <- "woken"
+	})
+	clk.Go(func() {
+		clk.Sleep(time.Second + time.Millisecond)
+		r.add("next timer")
+	})
+
+	clk.Advance(time.Second + time.Millisecond)
+	if got, want := r.get(), []string{"woken", "next timer"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events %v, want %v", got, want)
+	}
+
+	// The reader stays blocked on the channel; settling doesn't wait for it
+	close(handoff)
+	clk.Settle()
+}
+
+func TestWithTimeoutExpiresInSimulatedTime(t *testing.T) {
+	clk := NewSimulated(time.Unix(0, 0))
+
+	ctx, cancel := clk.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || !deadline.Equal(time.Unix(1, 0)) {
+		t.Errorf("deadline %v, want 1s into simulated time", deadline)
+	}
+
+	clk.Advance(999 * time.Millisecond)
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("context done after 999ms: %v", err)
+	}
+	clk.Advance(time.Millisecond)
+	if err := ctx.Err(); err != context.DeadlineExceeded {
+		t.Errorf("context error %v after 1s, want DeadlineExceeded", err)
+	}
+
+	// Cancelling first isn't reported as a timeout
+	cancelled, cancel := clk.WithTimeout(context.Background(), time.Second)
+	cancel()
+	clk.Advance(time.Second)
+	if err := cancelled.Err(); err != context.Canceled {
+		t.Errorf("cancelled context error %v, want Canceled", err)
+	}
+}
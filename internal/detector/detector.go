@@ -0,0 +1,249 @@
+// Package detector implements the phi accrual failure detector (Hayashibara
+// et al.). Instead of declaring a node dead after a fixed timeout, it learns
+// the distribution of each peer's heartbeat inter-arrival times and reports
+// phi: how unlikely it is, on a -log10 scale, that a heartbeat is still on
+// its way. Phi 1 means a 10% chance, phi 3 a 0.1% chance.
+package detector
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"dynamodb/internal/clock"
+)
+
+// Config tunes a detector
+type Config struct {
+	// Phi at or above which a peer is suspected, and declared dead
+	SuspectPhi float64 `json:"suspect_phi"`
+	DeadPhi    float64 `json:"dead_phi"`
+	// Expected time between heartbeats, used until real samples arrive
+	HeartbeatInterval time.Duration `json:"heartbeat_interval"`
+	// Extra silence tolerated on top of the learned mean (e.g. GC pauses)
+	AcceptablePause time.Duration `json:"acceptable_pause"`
+	// Floor on the learned standard deviation, so a very regular peer isn't
+	// suspected on the first small hiccup
+	MinStdDev time.Duration `json:"min_std_dev"`
+	// Inter-arrival samples kept per peer
+	WindowSize int `json:"window_size"`
+}
+
+// DefaultConfig returns defaults for peers that heartbeat every interval.
+// With them, a peer silent for about four intervals is suspected and after
+// about five is declared dead.
+func DefaultConfig(interval time.Duration) Config {
+	return Config{
+		SuspectPhi:        5,
+		DeadPhi:           12,
+		HeartbeatInterval: interval,
+		AcceptablePause:   interval,
+		MinStdDev:         interval / 2,
+		WindowSize:        100,
+	}
+}
+
+// Stats describes what the detector knows about one peer
+type Stats struct {
+	Phi           float64   `json:"phi"`
+	Status        string    `json:"status"` // "alive", "suspected" or "dead"
+	Samples       int       `json:"samples"`
+	MeanInterval  float64   `json:"mean_interval_ms"`
+	StdDev        float64   `json:"std_dev_ms"`
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+}
+
+// history is one peer's recent inter-arrival times, in milliseconds
+type history struct {
+	intervals []float64
+	next      int
+	sum       float64
+	squares   float64
+	last      time.Time
+}
+
+// Detector tracks heartbeats from a set of peers
+type Detector struct {
+	mu     sync.Mutex
+	config Config
+	clock  clock.Clock
+	peers  map[string]*history
+}
+
+// New creates a detector. Time comes from clk (nil = wall clock).
+func New(config Config, clk clock.Clock) *Detector {
+	if config.WindowSize <= 0 {
+		config.WindowSize = 100
+	}
+	return &Detector{
+		config: config,
+		clock:  clock.OrReal(clk),
+		peers:  make(map[string]*history),
+	}
+}
+
+// SetThresholds changes the phi levels for suspected and dead
+func (d *Detector) SetThresholds(suspectPhi, deadPhi float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.config.SuspectPhi = suspectPhi
+	d.config.DeadPhi = deadPhi
+}
+
+// Config returns the detector's settings
+func (d *Detector) Config() Config {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.config
+}
+
+// Heartbeat records that a peer was heard from now. The first heartbeat
+// starts tracking the peer with samples around HeartbeatInterval.
+func (d *Detector) Heartbeat(peerID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	h, exists := d.peers[peerID]
+	if !exists {
+		h = &history{intervals: make([]float64, 0, d.config.WindowSize)}
+
+		// Bootstrap with the expected interval plus and minus a quarter
+		expected := float64(d.config.HeartbeatInterval) / float64(time.Millisecond)
+		h.add(expected-expected/4, d.config.WindowSize)
+		h.add(expected+expected/4, d.config.WindowSize)
+		h.last = now
+		d.peers[peerID] = h
+		return
+	}
+
+	interval := float64(now.Sub(h.last)) / float64(time.Millisecond)
+	h.last = now
+	if interval > 0 {
+		h.add(interval, d.config.WindowSize)
+	}
+}
+
+// Remove forgets a peer. Call it when the peer is declared dead, so the
+// outage doesn't skew its intervals if it comes back.
+func (d *Detector) Remove(peerID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.peers, peerID)
+}
+
+// Phi returns the current suspicion level for a peer, 0 for peers that
+// aren't tracked
+func (d *Detector) Phi(peerID string) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	h, exists := d.peers[peerID]
+	if !exists {
+		return 0
+	}
+	return d.phi(h)
+}
+
+// Status maps a peer's phi onto alive, suspected or dead
+func (d *Detector) Status(peerID string) string {
+	return d.Classify(d.Phi(peerID))
+}
+
+// Classify maps a phi value onto alive, suspected or dead
+func (d *Detector) Classify(phi float64) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.statusLocked(phi)
+}
+
+// Stats returns what the detector knows about a peer
+func (d *Detector) Stats(peerID string) (Stats, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	h, exists := d.peers[peerID]
+	if !exists {
+		return Stats{}, false
+	}
+
+	phi := d.phi(h)
+	mean, stdDev := h.meanAndStdDev()
+	return Stats{
+		Phi:           phi,
+		Status:        d.statusLocked(phi),
+		Samples:       len(h.intervals),
+		MeanInterval:  mean,
+		StdDev:        stdDev,
+		LastHeartbeat: h.last,
+	}, true
+}
+
+func (d *Detector) statusLocked(phi float64) string {
+	switch {
+	case phi >= d.config.DeadPhi:
+		return "dead"
+	case phi >= d.config.SuspectPhi:
+		return "suspected"
+	default:
+		return "alive"
+	}
+}
+
+// phi computes -log10 of the probability that a heartbeat arrives later
+// than now, under a normal distribution fitted to the peer's intervals. It
+// uses the logistic approximation of the normal CDF, P = 1 / (1 + e^x), in a
+// form that stays finite however long the peer has been silent.
+func (d *Detector) phi(h *history) float64 {
+	elapsed := float64(d.clock.Since(h.last)) / float64(time.Millisecond)
+
+	mean, stdDev := h.meanAndStdDev()
+	mean += float64(d.config.AcceptablePause) / float64(time.Millisecond)
+	if minStdDev := float64(d.config.MinStdDev) / float64(time.Millisecond); stdDev < minStdDev {
+		stdDev = minStdDev
+	}
+	if stdDev <= 0 {
+		stdDev = 1
+	}
+
+	y := (elapsed - mean) / stdDev
+	x := y * (1.5976 + 0.070566*y*y)
+	if x > 0 {
+		return (x + math.Log1p(math.Exp(-x))) / math.Ln10
+	}
+	return math.Log1p(math.Exp(x)) / math.Ln10
+}
+
+// add records an interval, evicting the oldest once the window is full
+func (h *history) add(interval float64, windowSize int) {
+	if len(h.intervals) < windowSize {
+		h.intervals = append(h.intervals, interval)
+	} else {
+		evicted := h.intervals[h.next]
+		h.sum -= evicted
+		h.squares -= evicted * evicted
+		h.intervals[h.next] = interval
+		h.next = (h.next + 1) % windowSize
+	}
+	h.sum += interval
+	h.squares += interval * interval
+}
+
+// meanAndStdDev returns the mean and standard deviation of the window, in
+// milliseconds
+func (h *history) meanAndStdDev() (float64, float64) {
+	n := float64(len(h.intervals))
+	if n == 0 {
+		return 0, 0
+	}
+	mean := h.sum / n
+	variance := h.squares/n - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	return mean, math.Sqrt(variance)
+}
@@ -0,0 +1,111 @@
+package detector
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+)
+
+// trained returns a detector whose only peer has heartbeated at intervals
+// alternating between 900ms and 1100ms: mean 1s, standard deviation 100ms
+func trained(t *testing.T) (*Detector, *clock.Simulated) {
+	t.Helper()
+
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	d := New(Config{
+		SuspectPhi:        1,
+		DeadPhi:           2,
+		HeartbeatInterval: time.Second,
+		MinStdDev:         time.Millisecond,
+		WindowSize:        10,
+	}, clk)
+
+	d.Heartbeat("peer")
+	for i := 0; i < 20; i++ {
+		if i%2 == 0 {
+			clk.Advance(900 * time.Millisecond)
+		} else {
+			clk.Advance(1100 * time.Millisecond)
+		}
+		d.Heartbeat("peer")
+	}
+	return d, clk
+}
+
+func TestPhiFollowsNormalDistribution(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		sigmas  float64 // Elapsed time in standard deviations past the mean
+	}{
+		{name: "one mean", elapsed: 1000 * time.Millisecond, sigmas: 0},
+		{name: "mean plus one sigma", elapsed: 1100 * time.Millisecond, sigmas: 1},
+		{name: "mean plus two sigma", elapsed: 1200 * time.Millisecond, sigmas: 2},
+		{name: "mean plus three sigma", elapsed: 1300 * time.Millisecond, sigmas: 3},
+		{name: "half the mean", elapsed: 500 * time.Millisecond, sigmas: -5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, clk := trained(t)
+
+			stats, _ := d.Stats("peer")
+			if math.Abs(stats.MeanInterval-1000) > 1e-6 || math.Abs(stats.StdDev-100) > 1e-6 {
+				t.Fatalf("learned mean %.3fms, std dev %.3fms, want 1000ms and 100ms", stats.MeanInterval, stats.StdDev)
+			}
+
+			clk.Advance(tt.elapsed)
+
+			// -log10 of the chance a heartbeat is still to come
+			want := -math.Log10(0.5 * math.Erfc(tt.sigmas/math.Sqrt2))
+			if got := d.Phi("peer"); math.Abs(got-want) > 0.05 {
+				t.Errorf("phi = %.3f, want %.3f", got, want)
+			}
+		})
+	}
+}
+
+func TestStatusThresholds(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{elapsed: 1000 * time.Millisecond, want: "alive"},     // phi 0.3
+		{elapsed: 1150 * time.Millisecond, want: "suspected"}, // phi 1.2
+		{elapsed: 1300 * time.Millisecond, want: "dead"},      // phi 2.9
+		{elapsed: 24 * time.Hour, want: "dead"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.elapsed.String(), func(t *testing.T) {
+			d, clk := trained(t)
+			clk.Advance(tt.elapsed)
+
+			phi := d.Phi("peer")
+			if math.IsInf(phi, 0) || math.IsNaN(phi) {
+				t.Fatalf("phi = %f, want a finite value", phi)
+			}
+			if got := d.Status("peer"); got != tt.want {
+				t.Errorf("status = %s (phi %.2f), want %s", got, phi, tt.want)
+			}
+		})
+	}
+}
+
+func TestUntrackedPeers(t *testing.T) {
+	d, _ := trained(t)
+
+	if phi := d.Phi("stranger"); phi != 0 {
+		t.Errorf("phi for an untracked peer = %f, want 0", phi)
+	}
+
+	d.Remove("peer")
+	if _, tracked := d.Stats("peer"); tracked {
+		t.Error("peer still tracked after Remove")
+	}
+	if status := d.Status("peer"); status != "alive" {
+		t.Errorf("status after Remove = %s, want alive", status)
+	}
+}
@@ -0,0 +1,266 @@
+// Package fault injects failures into node-to-node traffic so partitions and
+// flaky links can be reproduced on one machine. Rules match requests by peer,
+// path and direction and drop, delay, duplicate or fail a percentage of them.
+package fault
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"dynamodb/internal/clock"
+)
+
+// Actions a rule can take on a matching request
+const (
+	ActionDrop      = "drop"      // The request never reaches the other side
+	ActionDelay     = "delay"     // The request is held for DelayMs first
+	ActionDuplicate = "duplicate" // The request is delivered twice
+	ActionError     = "error"     // The request is answered with Status without being handled
+)
+
+// Directions a rule applies to
+const (
+	DirectionInbound  = "inbound"  // Requests this node receives
+	DirectionOutbound = "outbound" // Requests this node sends
+)
+
+// NodeIDHeader names the sending node on inter-node requests so inbound
+// rules can match by peer
+const NodeIDHeader = "X-Node-ID"
+
+// Rule describes which requests to interfere with and how
+type Rule struct {
+	ID string `json:"id"`
+	// Peer node ID or address; empty matches every peer
+	Peer string `json:"peer,omitempty"`
+	// Path prefix such as /internal/replicate or /gossip/receive; empty
+	// matches every inter-node path
+	Path string `json:"path,omitempty"`
+	// inbound, outbound or empty for both
+	Direction string `json:"direction,omitempty"`
+	Action    string `json:"action"`
+	// Share of matching requests affected, 0-100
+	Percent float64 `json:"percent"`
+	DelayMs int     `json:"delay_ms,omitempty"`
+	// HTTP status for the error action (default 503)
+	Status int `json:"status,omitempty"`
+
+	// Matched counts requests the rule matched; Applied those it affected
+	Matched int64 `json:"matched"`
+	Applied int64 `json:"applied"`
+}
+
+// Validate checks the rule and fills in defaults
+func (r *Rule) Validate() error {
+	switch r.Action {
+	case ActionDrop, ActionDuplicate:
+	case ActionDelay:
+		if r.DelayMs <= 0 {
+			return fmt.Errorf("delay rule needs delay_ms > 0")
+		}
+	case ActionError:
+		if r.Status == 0 {
+			r.Status = 503
+		}
+		if r.Status < 400 || r.Status > 599 {
+			return fmt.Errorf("error rule needs a 4xx or 5xx status, got %d", r.Status)
+		}
+	default:
+		return fmt.Errorf("unknown action %q (expected drop, delay, duplicate or error)", r.Action)
+	}
+
+	switch r.Direction {
+	case "", DirectionInbound, DirectionOutbound:
+	default:
+		return fmt.Errorf("unknown direction %q (expected inbound, outbound or empty for both)", r.Direction)
+	}
+
+	if r.Percent <= 0 || r.Percent > 100 {
+		return fmt.Errorf("percent must be in (0, 100], got %v", r.Percent)
+	}
+	return nil
+}
+
+// Delay returns the rule's delay
+func (r *Rule) Delay() time.Duration {
+	return time.Duration(r.DelayMs) * time.Millisecond
+}
+
+// matches reports whether the rule covers a request. peers holds what is
+// known about the other side (its node ID, its address).
+func (r *Rule) matches(direction, path string, peers ...string) bool {
+	if r.Direction != "" && r.Direction != direction {
+		return false
+	}
+	if r.Path != "" && !strings.HasPrefix(path, r.Path) {
+		return false
+	}
+	if r.Peer == "" {
+		return true
+	}
+	for _, peer := range peers {
+		if peer != "" && peer == r.Peer {
+			return true
+		}
+	}
+	return false
+}
+
+// Injector holds the active rules and decides what happens to each request
+type Injector struct {
+	mu     sync.Mutex
+	rules  []*Rule
+	nextID int
+	seed   int64
+	rng    *rand.Rand  // Rolls each rule's percentage
+	clock  clock.Clock // Times delays
+
+	// resolve maps a peer address to its node ID for outbound requests
+	resolve func(address string) string
+}
+
+// NewInjector creates an injector with no rules. seed fixes which matching
+// requests each rule affects (0 = random); delays run on clk (nil = wall
+// clock).
+func NewInjector(seed int64, clk clock.Clock) *Injector {
+	if seed == 0 {
+		n, _ := crand.Int(crand.Reader, big.NewInt(1<<62))
+		seed = n.Int64() + 1
+	}
+	return &Injector{
+		seed:  seed,
+		rng:   rand.New(rand.NewSource(seed)),
+		clock: clock.OrReal(clk),
+	}
+}
+
+// Seed returns the seed the injector rolls percentages with, so a run can be
+// repeated with the same decisions
+func (inj *Injector) Seed() int64 {
+	return inj.seed
+}
+
+// SetPeerResolver lets outbound rules name peers by node ID: resolve maps
+// the address a request goes to onto the node listening there
+func (inj *Injector) SetPeerResolver(resolve func(address string) string) {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.resolve = resolve
+}
+
+// AddRule validates and installs a rule, returning it with its ID set
+func (inj *Injector) AddRule(rule Rule) (*Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.nextID++
+	rule.ID = fmt.Sprintf("fault-%d", inj.nextID)
+	rule.Matched = 0
+	rule.Applied = 0
+	inj.rules = append(inj.rules, &rule)
+
+	fmt.Printf("💥 Fault injection: %s %s%% of %s requests (peer %q, path %q) [%s]\n",
+		rule.Action, formatPercent(rule.Percent), directionName(rule.Direction), rule.Peer, rule.Path, rule.ID)
+	copied := rule
+	return &copied, nil
+}
+
+// RemoveRule deletes a rule by ID
+func (inj *Injector) RemoveRule(id string) bool {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	for i, rule := range inj.rules {
+		if rule.ID == id {
+			inj.rules = append(inj.rules[:i], inj.rules[i+1:]...)
+			fmt.Printf("🩹 Fault injection: removed %s\n", id)
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes every rule
+func (inj *Injector) Clear() int {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	removed := len(inj.rules)
+	inj.rules = nil
+	if removed > 0 {
+		fmt.Printf("🩹 Fault injection: cleared %d rules\n", removed)
+	}
+	return removed
+}
+
+// Rules returns a snapshot of the active rules with their counters
+func (inj *Injector) Rules() []Rule {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	rules := make([]Rule, len(inj.rules))
+	for i, rule := range inj.rules {
+		rules[i] = *rule
+	}
+	return rules
+}
+
+// Inbound decides what happens to a request this node received
+func (inj *Injector) Inbound(path, peerID string) []Rule {
+	return inj.decide(DirectionInbound, path, peerID)
+}
+
+// Outbound decides what happens to a request this node is about to send
+func (inj *Injector) Outbound(path, address string) []Rule {
+	inj.mu.Lock()
+	resolve := inj.resolve
+	inj.mu.Unlock()
+
+	peerID := ""
+	if resolve != nil {
+		peerID = resolve(address)
+	}
+	return inj.decide(DirectionOutbound, path, peerID, address)
+}
+
+// decide rolls every matching rule and returns the ones that fire, in the
+// order they were added
+func (inj *Injector) decide(direction, path string, peers ...string) []Rule {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	var fired []Rule
+	for _, rule := range inj.rules {
+		if !rule.matches(direction, path, peers...) {
+			continue
+		}
+		rule.Matched++
+		if inj.rng.Float64()*100 >= rule.Percent {
+			continue
+		}
+		rule.Applied++
+		fired = append(fired, *rule)
+	}
+	return fired
+}
+
+func directionName(direction string) string {
+	if direction == "" {
+		return "inbound and outbound"
+	}
+	return direction
+}
+
+func formatPercent(percent float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", percent), "0"), ".")
+}
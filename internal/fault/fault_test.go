@@ -0,0 +1,170 @@
+package fault
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      Rule
+		direction string
+		path      string
+		peers     []string
+		want      bool
+	}{
+		{name: "empty rule matches everything", rule: Rule{},
+			direction: DirectionInbound, path: "/gossip/receive", peers: []string{"node-2"}, want: true},
+		{name: "path prefix", rule: Rule{Path: "/internal/replicate"},
+			direction: DirectionOutbound, path: "/internal/replicate/batch", want: true},
+		{name: "other path", rule: Rule{Path: "/internal/replicate"},
+			direction: DirectionOutbound, path: "/gossip/receive", want: false},
+		{name: "direction", rule: Rule{Direction: DirectionInbound},
+			direction: DirectionInbound, path: "/gossip/receive", want: true},
+		{name: "other direction", rule: Rule{Direction: DirectionInbound},
+			direction: DirectionOutbound, path: "/gossip/receive", want: false},
+		{name: "peer by node ID", rule: Rule{Peer: "node-2"},
+			direction: DirectionOutbound, path: "/gossip/receive", peers: []string{"node-2", "localhost:8002"}, want: true},
+		{name: "peer by address", rule: Rule{Peer: "localhost:8002"},
+			direction: DirectionOutbound, path: "/gossip/receive", peers: []string{"", "localhost:8002"}, want: true},
+		{name: "other peer", rule: Rule{Peer: "node-3"},
+			direction: DirectionInbound, path: "/gossip/receive", peers: []string{"node-2"}, want: false},
+		{name: "unknown peer", rule: Rule{Peer: "node-2"},
+			direction: DirectionInbound, path: "/gossip/receive", peers: []string{""}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.matches(tt.direction, tt.path, tt.peers...); got != tt.want {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{name: "drop", rule: Rule{Action: ActionDrop, Percent: 100}},
+		{name: "delay", rule: Rule{Action: ActionDelay, Percent: 50, DelayMs: 200}},
+		{name: "delay without delay_ms", rule: Rule{Action: ActionDelay, Percent: 50}, wantErr: true},
+		{name: "error", rule: Rule{Action: ActionError, Percent: 10}},
+		{name: "error with 2xx status", rule: Rule{Action: ActionError, Percent: 10, Status: 200}, wantErr: true},
+		{name: "unknown action", rule: Rule{Action: "corrupt", Percent: 10}, wantErr: true},
+		{name: "unknown direction", rule: Rule{Action: ActionDrop, Percent: 10, Direction: "sideways"}, wantErr: true},
+		{name: "zero percent", rule: Rule{Action: ActionDrop}, wantErr: true},
+		{name: "over 100 percent", rule: Rule{Action: ActionDrop, Percent: 150}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	rule := Rule{Action: ActionError, Percent: 10}
+	if rule.Validate(); rule.Status != 503 {
+		t.Errorf("error rule defaulted to status %d, want 503", rule.Status)
+	}
+}
+
+func TestInjectorAppliesPercentOfMatches(t *testing.T) {
+	inj := NewInjector(1, nil)
+
+	half, err := inj.AddRule(Rule{Action: ActionDrop, Percent: 50, Path: "/internal"})
+	if err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	all, err := inj.AddRule(Rule{Action: ActionDelay, Percent: 100, DelayMs: 10})
+	if err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		fired := inj.Inbound("/internal/replicate", "node-2")
+		// Rules fire in the order they were added
+		if last := fired[len(fired)-1]; last.ID != all.ID {
+			t.Fatalf("last fired rule is %s, want %s", last.ID, all.ID)
+		}
+	}
+	inj.Inbound("/gossip/receive", "node-2")
+
+	counts := make(map[string]Rule)
+	for _, rule := range inj.Rules() {
+		counts[rule.ID] = rule
+	}
+	if rule := counts[half.ID]; rule.Matched != 1000 || rule.Applied < 450 || rule.Applied > 550 {
+		t.Errorf("50%% rule matched %d and applied %d, want 1000 and about 500", rule.Matched, rule.Applied)
+	}
+	if rule := counts[all.ID]; rule.Matched != 1001 || rule.Applied != 1001 {
+		t.Errorf("100%% rule matched %d and applied %d, want 1001 of both", rule.Matched, rule.Applied)
+	}
+}
+
+func TestInjectorSeedFixesDecisions(t *testing.T) {
+	decisions := func(seed int64) []int {
+		inj := NewInjector(seed, nil)
+		if _, err := inj.AddRule(Rule{Action: ActionDrop, Percent: 50}); err != nil {
+			t.Fatalf("AddRule: %v", err)
+		}
+		fired := make([]int, 100)
+		for i := range fired {
+			fired[i] = len(inj.Inbound("/internal/replicate", "node-2"))
+		}
+		return fired
+	}
+
+	if first, second := decisions(7), decisions(7); !reflect.DeepEqual(first, second) {
+		t.Errorf("two injectors seeded 7 decided differently")
+	}
+	if reflect.DeepEqual(decisions(7), decisions(8)) {
+		t.Errorf("injectors seeded 7 and 8 decided the same")
+	}
+	if NewInjector(0, nil).Seed() == 0 {
+		t.Errorf("injector without a seed kept seed 0")
+	}
+}
+
+func TestInjectorResolvesOutboundPeers(t *testing.T) {
+	inj := NewInjector(1, nil)
+	if _, err := inj.AddRule(Rule{Action: ActionDrop, Percent: 100, Peer: "node-2"}); err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+
+	if fired := inj.Outbound("/gossip/receive", "localhost:8002"); len(fired) != 0 {
+		t.Errorf("rule naming node-2 fired on an address nothing resolves")
+	}
+
+	inj.SetPeerResolver(func(address string) string {
+		if address == "localhost:8002" {
+			return "node-2"
+		}
+		return ""
+	})
+	if fired := inj.Outbound("/gossip/receive", "localhost:8002"); len(fired) != 1 {
+		t.Errorf("rule naming node-2 didn't fire on its address")
+	}
+	if fired := inj.Outbound("/gossip/receive", "localhost:8003"); len(fired) != 0 {
+		t.Errorf("rule naming node-2 fired on another node")
+	}
+}
+
+func TestInjectorRemoveAndClear(t *testing.T) {
+	inj := NewInjector(1, nil)
+	first, _ := inj.AddRule(Rule{Action: ActionDrop, Percent: 100})
+	inj.AddRule(Rule{Action: ActionDrop, Percent: 100})
+	inj.AddRule(Rule{Action: ActionDrop, Percent: 100})
+
+	if !inj.RemoveRule(first.ID) || inj.RemoveRule(first.ID) {
+		t.Errorf("RemoveRule(%s) should succeed once", first.ID)
+	}
+	if removed := inj.Clear(); removed != 2 || len(inj.Rules()) != 0 {
+		t.Errorf("Clear removed %d, left %v; want 2 removed and none left", removed, inj.Rules())
+	}
+}
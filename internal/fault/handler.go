@@ -0,0 +1,63 @@
+package fault
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the admin API for managing fault rules at runtime
+type Handler struct {
+	injector *Injector
+}
+
+// NewHandler creates the admin API for an injector
+func NewHandler(injector *Injector) *Handler {
+	return &Handler{injector: injector}
+}
+
+// ListRules returns the active rules and their counters
+func (h *Handler) ListRules(c *gin.Context) {
+	rules := h.injector.Rules()
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+		"total": len(rules),
+	})
+}
+
+// AddRule installs a rule
+func (h *Handler) AddRule(c *gin.Context) {
+	var rule Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid fault rule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	added, err := h.injector.AddRule(rule)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid fault rule",
+			"details": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, added)
+}
+
+// RemoveRule deletes one rule
+func (h *Handler) RemoveRule(c *gin.Context) {
+	id := c.Param("id")
+	if !h.injector.RemoveRule(id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No fault rule " + id})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"removed": id})
+}
+
+// ClearRules deletes every rule
+func (h *Handler) ClearRules(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"removed": h.injector.Clear()})
+}
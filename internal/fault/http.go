@@ -0,0 +1,173 @@
+package fault
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// duplicateHeader marks the extra copy of a duplicated request so it isn't
+// interfered with again
+const duplicateHeader = "X-Fault-Duplicate"
+
+// Middleware applies inbound rules to the requests of a router group.
+// Duplicated requests are replayed through engine before the original is
+// handled.
+func (inj *Injector) Middleware(engine http.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(duplicateHeader) != "" {
+			c.Next()
+			return
+		}
+
+		for _, rule := range inj.Inbound(c.Request.URL.Path, c.GetHeader(NodeIDHeader)) {
+			switch rule.Action {
+			case ActionDelay:
+				if err := inj.sleep(c.Request.Context(), rule.Delay()); err != nil {
+					c.Abort()
+					return
+				}
+			case ActionDrop:
+				dropConnection(c)
+				return
+			case ActionError:
+				c.AbortWithStatusJSON(rule.Status, gin.H{
+					"error": "Fault injected",
+					"fault": rule.ID,
+				})
+				return
+			case ActionDuplicate:
+				if err := replay(c, engine); err != nil {
+					fmt.Printf("⚠️ Fault injection: failed to duplicate %s: %v\n", c.Request.URL.Path, err)
+				}
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// Send applies outbound rules to a request and sends it with do
+func (inj *Injector) Send(req *http.Request, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if req.Header.Get(duplicateHeader) != "" {
+		return do(req)
+	}
+
+	for _, rule := range inj.Outbound(req.URL.Path, req.URL.Host) {
+		switch rule.Action {
+		case ActionDelay:
+			if err := inj.sleep(req.Context(), rule.Delay()); err != nil {
+				return nil, err
+			}
+		case ActionDrop:
+			return nil, fmt.Errorf("fault injection: request to %s%s dropped (%s)", req.URL.Host, req.URL.Path, rule.ID)
+		case ActionError:
+			return errorResponse(req, rule), nil
+		case ActionDuplicate:
+			if err := sendCopy(req, do); err != nil {
+				fmt.Printf("⚠️ Fault injection: failed to duplicate request to %s%s: %v\n", req.URL.Host, req.URL.Path, err)
+			}
+		}
+	}
+
+	return do(req)
+}
+
+// dropConnection closes the connection without answering, as if the request
+// had been lost on the way
+func dropConnection(c *gin.Context) {
+	c.Abort()
+
+	hijacker, ok := c.Writer.(http.Hijacker)
+	if !ok {
+		c.Status(http.StatusServiceUnavailable)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		c.Status(http.StatusServiceUnavailable)
+		return
+	}
+	conn.Close()
+}
+
+// replay handles a copy of the request through engine, discarding the
+// response, and leaves the original ready to be handled
+func replay(c *gin.Context, engine http.Handler) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	c.Request.Body.Close()
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	duplicate := c.Request.Clone(c.Request.Context())
+	duplicate.Body = io.NopCloser(bytes.NewReader(body))
+	duplicate.Header.Set(duplicateHeader, "1")
+
+	engine.ServeHTTP(&discardWriter{header: make(http.Header)}, duplicate)
+	return nil
+}
+
+// sendCopy sends an extra copy of req and throws its response away
+func sendCopy(req *http.Request, do func(*http.Request) (*http.Response, error)) error {
+	duplicate := req.Clone(req.Context())
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return fmt.Errorf("request body can't be replayed")
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		duplicate.Body = body
+	}
+	duplicate.Header.Set(duplicateHeader, "1")
+
+	resp, err := do(duplicate)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// errorResponse answers a request locally with the rule's status
+func errorResponse(req *http.Request, rule Rule) *http.Response {
+	body := fmt.Sprintf(`{"error":"Fault injected","fault":%q}`, rule.ID)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rule.Status, http.StatusText(rule.Status)),
+		StatusCode:    rule.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// sleep waits out a delay on the injector's clock, or until ctx is done
+func (inj *Injector) sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-inj.clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// discardWriter is the response writer for duplicated requests
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
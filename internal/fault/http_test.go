@@ -0,0 +1,206 @@
+package fault
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"dynamodb/internal/clock"
+)
+
+// newFaultyServer serves /internal/echo behind the injector's middleware and
+// counts the requests that reach the handler
+func newFaultyServer(t *testing.T, inj *Injector) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	var handled atomic.Int64
+	engine := gin.New()
+	internal := engine.Group("/internal")
+	internal.Use(inj.Middleware(engine))
+	internal.POST("/echo", func(c *gin.Context) {
+		handled.Add(1)
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return server, &handled
+}
+
+func addRule(t *testing.T, inj *Injector, rule Rule) *Rule {
+	t.Helper()
+
+	added, err := inj.AddRule(rule)
+	if err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	return added
+}
+
+func post(t *testing.T, url, body string) (*http.Response, string, error) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url+"/internal/echo", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set(NodeIDHeader, "node-2")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	answer, err := io.ReadAll(resp.Body)
+	return resp, string(answer), err
+}
+
+func TestMiddlewareDropsRequests(t *testing.T) {
+	inj := NewInjector(1, nil)
+	server, handled := newFaultyServer(t, inj)
+	addRule(t, inj, Rule{Action: ActionDrop, Percent: 100, Peer: "node-2"})
+
+	if _, _, err := post(t, server.URL, "v1"); err == nil {
+		t.Errorf("dropped request got an answer")
+	}
+	if handled.Load() != 0 {
+		t.Errorf("dropped request reached the handler")
+	}
+}
+
+func TestMiddlewareDelaysRequests(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(1000, 0))
+	inj := NewInjector(1, clk)
+	server, handled := newFaultyServer(t, inj)
+	addRule(t, inj, Rule{Action: ActionDelay, Percent: 100, DelayMs: 100})
+
+	type answer struct {
+		resp *http.Response
+		body string
+		err  error
+	}
+	answered := make(chan answer, 1)
+	go func() {
+		resp, body, err := post(t, server.URL, "v1")
+		answered <- answer{resp, body, err}
+	}()
+
+	// The delay waits on the injector's clock, not on wall time
+	for clk.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-answered:
+		t.Fatalf("request answered before the clock moved")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	clk.Advance(100 * time.Millisecond)
+	got := <-answered
+	if got.err != nil || got.resp.StatusCode != http.StatusOK || got.body != "v1" {
+		t.Fatalf("delayed request = %v, %q, %v; want it answered", got.resp, got.body, got.err)
+	}
+	if handled.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", handled.Load())
+	}
+}
+
+func TestMiddlewareAnswersErrors(t *testing.T) {
+	inj := NewInjector(1, nil)
+	server, handled := newFaultyServer(t, inj)
+	rule := addRule(t, inj, Rule{Action: ActionError, Percent: 100, Status: http.StatusTooManyRequests})
+
+	resp, body, err := post(t, server.URL, "v1")
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests || !strings.Contains(body, rule.ID) {
+		t.Fatalf("request = %v, %q, %v; want 429 naming %s", resp, body, err, rule.ID)
+	}
+	if handled.Load() != 0 {
+		t.Errorf("request answered with a fault reached the handler")
+	}
+}
+
+func TestMiddlewareDuplicatesRequests(t *testing.T) {
+	inj := NewInjector(1, nil)
+	server, handled := newFaultyServer(t, inj)
+	addRule(t, inj, Rule{Action: ActionDuplicate, Percent: 100})
+
+	resp, body, err := post(t, server.URL, "v1")
+	if err != nil || resp.StatusCode != http.StatusOK || body != "v1" {
+		t.Fatalf("duplicated request = %v, %q, %v; want the original answered with its body", resp, body, err)
+	}
+	if handled.Load() != 2 {
+		t.Errorf("handler ran %d times, want 2", handled.Load())
+	}
+
+	// The copy isn't interfered with again
+	if rules := inj.Rules(); rules[0].Matched != 1 {
+		t.Errorf("rule matched %d requests, want only the original", rules[0].Matched)
+	}
+}
+
+func TestMiddlewareIgnoresOtherPeers(t *testing.T) {
+	inj := NewInjector(1, nil)
+	server, handled := newFaultyServer(t, inj)
+	addRule(t, inj, Rule{Action: ActionDrop, Percent: 100, Peer: "node-3"})
+
+	if resp, _, err := post(t, server.URL, "v1"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("request from node-2 = %v, %v; want it answered", resp, err)
+	}
+	if handled.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", handled.Load())
+	}
+}
+
+func TestSendAppliesOutboundRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       Rule
+		wantErr    bool
+		wantStatus int
+		wantSent   int64
+	}{
+		{name: "drop", rule: Rule{Action: ActionDrop, Percent: 100}, wantErr: true},
+		{name: "error", rule: Rule{Action: ActionError, Percent: 100}, wantStatus: 503},
+		{name: "duplicate", rule: Rule{Action: ActionDuplicate, Percent: 100}, wantStatus: 200, wantSent: 2},
+		{name: "delay", rule: Rule{Action: ActionDelay, Percent: 100, DelayMs: 10}, wantStatus: 200, wantSent: 1},
+		{name: "inbound only", rule: Rule{Action: ActionDrop, Percent: 100, Direction: DirectionInbound},
+			wantStatus: 200, wantSent: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inj := NewInjector(1, nil)
+			addRule(t, inj, tt.rule)
+
+			var sent atomic.Int64
+			do := func(req *http.Request) (*http.Response, error) {
+				sent.Add(1)
+				body, _ := io.ReadAll(req.Body)
+				if string(body) != "v1" {
+					t.Errorf("sent body %q, want v1", body)
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, "http://localhost:8002/internal/replicate", strings.NewReader("v1"))
+			resp, err := inj.Send(req, do)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && resp.StatusCode != tt.wantStatus {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if sent.Load() != tt.wantSent {
+				t.Errorf("sent %d requests, want %d", sent.Load(), tt.wantSent)
+			}
+		})
+	}
+}
@@ -1,118 +1,131 @@
 package gossip
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"strconv"
 	"time"
+
+	"dynamodb/internal/membership"
 )
 
+// send delivers a gossip message to the node at address over the manager's
+// transport. The call is bounded by timeout and by the manager's lifetime.
+func (gm *GossipManager) send(address string, message *GossipMessage, timeout time.Duration) error {
+	ctx, cancel := gm.clock.WithTimeout(gm.ctx, timeout)
+	defer cancel()
+
+	if err := gm.transport.Send(ctx, address, message); err != nil {
+		return err
+	}
+	gm.countTransmits(message)
+	return nil
+}
+
+// sendPacket sends a small, frequent message (probe, ack, heartbeat) over the
+// packet transport if there is one, else like send
+func (gm *GossipManager) sendPacket(address string, message *GossipMessage, timeout time.Duration) error {
+	if gm.packets == nil {
+		return gm.send(address, message, timeout)
+	}
+
+	ctx, cancel := gm.clock.WithTimeout(gm.ctx, timeout)
+	defer cancel()
+
+	message = withoutSettings(message)
+	if sized, ok := gm.packets.(PacketSizer); ok {
+		message = rumorsThatFit(message, sized.PacketSize())
+	}
+	if err := gm.packets.Send(ctx, address, message); err != nil {
+		return err
+	}
+	gm.countTransmits(message)
+	return nil
+}
+
 // sendGossip sends gossip message to a peer
 func (gm *GossipManager) sendGossip(peer *PeerInfo, data map[string]interface{}) {
 	message := GossipMessage{
 		Type:      "heartbeat",
 		FromNode:  gm.currentNode.ID,
 		ToNode:    peer.NodeID,
-		Timestamp: time.Now().Unix(),
+		Timestamp: gm.clock.Now().Unix(),
 		Data:      data,
 		MessageID: generateMessageID(),
 	}
 
-	url := fmt.Sprintf("http://%s/gossip/receive", peer.Address)
-	
-	jsonData, err := json.Marshal(message)
-	if err != nil {
-		fmt.Printf("❌ Failed to marshal gossip message for %s: %v\n", peer.NodeID, err)
-		fmt.Printf("❌ Message data: %+v\n", message)
-		return
-	}
-
-	resp, err := gm.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
+	if err := gm.sendPacket(peer.Address, &message, gm.config.ProbeTimeout); err != nil {
 		fmt.Printf("❌ Failed to send gossip to %s: %v\n", peer.NodeID, err)
 		gm.handleGossipFailure(peer.NodeID)
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("❌ Gossip to %s failed with status %d\n", peer.NodeID, resp.StatusCode)
-		gm.handleGossipFailure(peer.NodeID)
-		return
-	}
 
 	fmt.Printf("🗣️ Gossip sent to %s successfully\n", peer.NodeID)
 }
 
 // HandleGossipMessage processes incoming gossip messages
 func (gm *GossipManager) HandleGossipMessage(message *GossipMessage) error {
+	_, err := gm.HandleGossipRequest(message)
+	return err
+}
+
+// HandleGossipRequest processes an incoming gossip message and returns the
+// reply to requests (seed_discovery). Other messages have no reply.
+func (gm *GossipManager) HandleGossipRequest(message *GossipMessage) (*GossipMessage, error) {
 	gm.mu.Lock()
 	defer gm.mu.Unlock()
 
 	fmt.Printf("📨 Received gossip from %s (type: %s)\n", message.FromNode, message.Type)
 
+	// Any message may carry rumors and settings
+	gm.receiveRumors(message)
+	gm.receiveSettings(message)
+
 	switch message.Type {
 	case "heartbeat":
-		return gm.handleHeartbeat(message)
+		return nil, gm.handleHeartbeat(message)
 	case "join":
-		return gm.handleJoinMessage(message)
+		return nil, gm.handleJoinMessage(message)
 	case "leave":
-		return gm.handleLeaveMessage(message)
+		return nil, gm.handleLeaveMessage(message)
 	case "probe":
-		return gm.handleProbeMessage(message)
+		return nil, gm.handleProbeMessage(message)
 	case "probe_response":
-		return gm.handleProbeResponse(message)
+		return nil, gm.handleProbeResponse(message)
+	case "indirect_probe_request":
+		return nil, gm.handleIndirectProbeRequest(message)
+	case "indirect_probe_response":
+		return nil, gm.handleIndirectProbeResponse(message)
 	case "seed_discovery":
 		return gm.handleSeedDiscovery(message)
+	case "push_pull":
+		return nil, gm.handlePushPull(message)
 	default:
 		fmt.Printf("⚠️ Unknown gossip message type: %s\n", message.Type)
-		return fmt.Errorf("unknown message type: %s", message.Type)
+		return nil, fmt.Errorf("unknown message type: %s", message.Type)
 	}
 }
 
 // handleHeartbeat processes heartbeat messages containing peer information
 func (gm *GossipManager) handleHeartbeat(message *GossipMessage) error {
 	// Extract peer information from the message
-	if peersData, ok := message.Data["peers"]; ok {
-		if peersMap, ok := peersData.(map[string]interface{}); ok {
-			for nodeID, peerData := range peersMap {
-				gm.updatePeerInfo(nodeID, peerData)
-			}
+	// (decoded JSON over HTTP, typed structs over gRPC), in node ID order
+	switch peers := message.Data["peers"].(type) {
+	case map[string]interface{}:
+		for _, nodeID := range sortedKeys(peers) {
+			gm.updatePeerInfo(nodeID, peers[nodeID])
 		}
-	}
-
-	// Extract and process rumors
-	if rumorsData, ok := message.Data["rumors"]; ok {
-		if rumorsMap, ok := rumorsData.(map[string]interface{}); ok {
-			for rumorID, rumorData := range rumorsMap {
-				gm.processRumor(rumorID, rumorData)
-			}
+	case map[string]*PeerInfo:
+		for _, nodeID := range sortedKeys(peers) {
+			gm.mergePeerInfo(nodeID, peers[nodeID])
 		}
 	}
 
-	// Update the sender's last seen time and ensure they're marked as alive
+	// Hearing from the sender only updates its last seen time. Its status
+	// changes through its own entry above, ordered by incarnation, so a
+	// node we declared dead has to come back at a newer one.
 	if peer, exists := gm.peers[message.FromNode]; exists {
-		peer.LastSeen = time.Now()
-		wasAlive := peer.Status == "alive"
-		
-		// Always mark a communicating node as alive
-		if peer.Status != "alive" {
-			fmt.Printf("💚 Node %s is communicating - marking as alive\n", message.FromNode)
-			peer.Status = "alive"
-			
-			// Trigger join callback for any node that becomes alive (recovered or newly active)
-			if gm.onNodeJoin != nil && message.FromNode != gm.currentNode.ID {
-				fmt.Printf("🔄 Triggering join callback for active node %s\n", message.FromNode)
-				gm.onNodeJoin(message.FromNode, peer.Address)
-			}
-		} else if !wasAlive {
-			// Node was not alive before, now it is - trigger callback
-			if gm.onNodeJoin != nil && message.FromNode != gm.currentNode.ID {
-				fmt.Printf("🔄 Triggering join callback for newly active node %s\n", message.FromNode)
-				gm.onNodeJoin(message.FromNode, peer.Address)
-			}
-		}
+		gm.heard(peer)
 	}
 
 	return nil
@@ -130,46 +143,128 @@ func (gm *GossipManager) updatePeerInfo(nodeID string, peerData interface{}) {
 	jsonData, _ := json.Marshal(peerMap)
 	json.Unmarshal(jsonData, &peerInfo)
 
+	gm.mergePeerInfo(nodeID, &peerInfo)
+}
+
+// mergePeerInfo merges a peer's gossiped state into our view of it. Status
+// claims are ordered by incarnation, then by status precedence, so only the
+// node itself can overturn a suspicion (by moving to a newer incarnation).
+func (gm *GossipManager) mergePeerInfo(nodeID string, peerInfo *PeerInfo) {
+	if nodeID == gm.currentNode.ID {
+		gm.refute(peerInfo)
+		return
+	}
+
 	existingPeer, exists := gm.peers[nodeID]
-	
 	if !exists {
 		// New peer discovered
-		gm.peers[nodeID] = &peerInfo
-		fmt.Printf("🆕 Discovered new peer: %s (%s)\n", nodeID, peerInfo.Address)
-		
-		if gm.onNodeJoin != nil && nodeID != gm.currentNode.ID {
-			fmt.Printf("🔄 Triggering join callback for newly discovered node %s\n", nodeID)
-			gm.onNodeJoin(nodeID, peerInfo.Address)
-		}
-	} else {
-		// Update existing peer if the information is newer
-		if peerInfo.HeartbeatSeq > existingPeer.HeartbeatSeq ||
-		   peerInfo.Incarnation > existingPeer.Incarnation {
-			
-			// Update heartbeat, timestamp, and incarnation
-			existingPeer.HeartbeatSeq = peerInfo.HeartbeatSeq
-			existingPeer.LastSeen = time.Now()
-			
-			// Update incarnation if it's higher (important for seed node discovery)
-			if peerInfo.Incarnation > existingPeer.Incarnation {
-				fmt.Printf("🔄 Updating incarnation for %s: %d -> %d\n", 
-					nodeID, existingPeer.Incarnation, peerInfo.Incarnation)
-				existingPeer.Incarnation = peerInfo.Incarnation
-			}
-			
-			// Only change status if we're receiving explicit status changes or if node was dead and now communicating
-			if existingPeer.Status == "dead" && peerInfo.Status == "alive" {
-				fmt.Printf("💚 Node %s recovered: dead -> alive\n", nodeID)
-				existingPeer.Status = "alive"
-				
-				// Trigger the join callback for recovered nodes
-				if gm.onNodeJoin != nil && nodeID != gm.currentNode.ID {
-					fmt.Printf("🔄 Triggering join callback for recovered node %s\n", nodeID)
-					gm.onNodeJoin(nodeID, existingPeer.Address)
-				}
-			}
+		gm.putPeer(peerInfo)
+		fmt.Printf("🆕 Discovered new peer: %s (%s, %s)\n", nodeID, peerInfo.Address, peerInfo.Status)
+
+		// Start tracking it; if it never shows up, its phi grows
+		if peerInfo.Status != "dead" {
+			gm.detector.Heartbeat(nodeID)
 		}
+		return
+	}
+
+	// A newer heartbeat within the same incarnation shows the node is running
+	if peerInfo.Incarnation == existingPeer.Incarnation && peerInfo.HeartbeatSeq > existingPeer.HeartbeatSeq {
+		existingPeer.HeartbeatSeq = peerInfo.HeartbeatSeq
+		gm.heard(existingPeer)
+	}
+
+	if !overrides(peerInfo, existingPeer) {
+		return
+	}
+
+	before := *existingPeer
+	if peerInfo.Incarnation > existingPeer.Incarnation {
+		fmt.Printf("🔄 Updating incarnation for %s: %d -> %d\n",
+			nodeID, existingPeer.Incarnation, peerInfo.Incarnation)
+		existingPeer.Incarnation = peerInfo.Incarnation
+		existingPeer.HeartbeatSeq = peerInfo.HeartbeatSeq
+		gm.heard(existingPeer)
+	}
+
+	gm.applyStatus(existingPeer, peerInfo.Status)
+	if existingPeer.Status == before.Status {
+		gm.announce(&before, existingPeer)
+	}
+}
+
+// overrides reports whether a claim about a node supersedes what we know: a
+// higher incarnation always wins, and within an incarnation suspected beats
+// alive and dead beats both (membership.StatusPrecedence)
+func overrides(claim, known *PeerInfo) bool {
+	if claim.Incarnation != known.Incarnation {
+		return claim.Incarnation > known.Incarnation
+	}
+	return membership.StatusPrecedence(claim.Status) > membership.StatusPrecedence(known.Status)
+}
+
+// applyStatus moves a peer to a status learned from gossip
+func (gm *GossipManager) applyStatus(peer *PeerInfo, status string) {
+	previous := peer.Status
+	if previous == status {
+		return
+	}
+	gm.setStatus(peer, status)
+
+	switch status {
+	case "alive":
+		fmt.Printf("💚 Node %s is alive at incarnation %d (was %s)\n", peer.NodeID, peer.Incarnation, previous)
+	case "suspected":
+		fmt.Printf("🤔 Node %s suspected by peers at incarnation %d\n", peer.NodeID, peer.Incarnation)
+	case "dead":
+		fmt.Printf("💀 Node %s declared dead by peers at incarnation %d\n", peer.NodeID, peer.Incarnation)
+		gm.detector.Remove(peer.NodeID)
+	}
+}
+
+// refute answers a claim that we are suspected or dead. Moving past the
+// claimed incarnation makes our alive entry override the claim wherever it
+// spreads, and an immediate gossip round starts spreading it.
+func (gm *GossipManager) refute(claim *PeerInfo) {
+	self := gm.peers[gm.currentNode.ID]
+	if self == nil || claim.Status == "alive" || claim.Incarnation < self.Incarnation {
+		return
+	}
+
+	self.Incarnation = claim.Incarnation + 1
+	self.Status = "alive"
+	fmt.Printf("🛡️ Refuting %s claim about us at incarnation %d: now alive at incarnation %d\n",
+		claim.Status, claim.Incarnation, self.Incarnation)
+
+	gm.clock.Go(gm.performGossipRound)
+}
+
+// selfIncarnation returns our current incarnation
+func (gm *GossipManager) selfIncarnation() int64 {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+
+	if self := gm.peers[gm.currentNode.ID]; self != nil {
+		return self.Incarnation
+	}
+	return 0
+}
+
+// dataInt64 reads a number from a message payload (a JSON number over HTTP,
+// a string over gRPC)
+func dataInt64(data map[string]interface{}, key string) (int64, bool) {
+	switch value := data[key].(type) {
+	case float64:
+		return int64(value), true
+	case int64:
+		return value, true
+	case int:
+		return int64(value), true
+	case string:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		return parsed, err == nil
 	}
+	return 0, false
 }
 
 // processRumor processes incoming rumors
@@ -183,40 +278,85 @@ func (gm *GossipManager) processRumor(rumorID string, rumorData interface{}) {
 	jsonData, _ := json.Marshal(rumorMap)
 	json.Unmarshal(jsonData, &rumor)
 
+	gm.mergeRumor(rumorID, &rumor)
+}
+
+// mergeRumor stores a gossiped rumor, acting on it the first time we hear it
+func (gm *GossipManager) mergeRumor(rumorID string, rumor *Rumor) {
 	existingRumor, exists := gm.rumors[rumorID]
 	
 	if !exists {
-		// New rumor - add it and prepare to spread
-		gm.rumors[rumorID] = &rumor
+		// New rumor - add it and spread it ourselves, counting our own sends
+		rumor.SpreadCount = 0
+		gm.rumors[rumorID] = rumor
 		fmt.Printf("📢 New rumor received: %s (type: %s)\n", rumorID, rumor.Type)
 		
 		// Process the rumor based on its type
-		gm.processRumorContent(&rumor)
+		gm.processRumorContent(rumor)
 	} else if rumor.Timestamp > existingRumor.Timestamp {
-		// Update with newer information
-		*existingRumor = rumor
+		// Update with newer information, keeping our transmission count
+		spreadCount := existingRumor.SpreadCount
+		*existingRumor = *rumor
+		existingRumor.SpreadCount = spreadCount
 		fmt.Printf("🔄 Rumor updated: %s\n", rumorID)
 	}
 }
 
-// processRumorContent processes the content of a rumor
+// processRumorContent applies a membership rumor the first time we hear it.
+// Rumors are claims like any other: they are ordered by incarnation, and
+// claims about ourselves are refuted.
 func (gm *GossipManager) processRumorContent(rumor *Rumor) {
+	nodeID, ok := rumor.Data["node_id"].(string)
+	if !ok {
+		return
+	}
+	incarnation, hasIncarnation := dataInt64(rumor.Data, "incarnation")
+
 	switch rumor.Type {
 	case "node_join":
-		if nodeID, ok := rumor.Data["node_id"].(string); ok {
-			if address, ok := rumor.Data["address"].(string); ok {
-				fmt.Printf("📢 Rumor: Node %s joined at %s\n", nodeID, address)
-			}
+		address, ok := rumor.Data["address"].(string)
+		if !ok {
+			return
 		}
-	case "node_leave":
-		if nodeID, ok := rumor.Data["node_id"].(string); ok {
+		fmt.Printf("📢 Rumor: Node %s joined at %s\n", nodeID, address)
+
+		gm.mergePeerInfo(nodeID, &PeerInfo{
+			NodeID:      nodeID,
+			Address:     address,
+			Status:      "alive",
+			LastSeen:    gm.clock.Now(),
+			Incarnation: incarnation,
+		})
+	case "node_leave", "node_failure":
+		if rumor.Type == "node_leave" {
 			fmt.Printf("📢 Rumor: Node %s left the cluster\n", nodeID)
-		}
-	case "node_failure":
-		if nodeID, ok := rumor.Data["node_id"].(string); ok {
+		} else {
 			fmt.Printf("📢 Rumor: Node %s failed\n", nodeID)
-			gm.markNodeAsSuspected(nodeID)
 		}
+
+		peer, exists := gm.peers[nodeID]
+		if !exists {
+			return
+		}
+		claim := &PeerInfo{NodeID: nodeID, Status: "dead", Incarnation: peer.Incarnation}
+		if hasIncarnation {
+			claim.Incarnation = incarnation
+		}
+
+		if nodeID == gm.currentNode.ID {
+			gm.refute(claim)
+			return
+		}
+		// Ignore claims about an incarnation the node has since moved past
+		if !overrides(claim, peer) {
+			return
+		}
+
+		if rumor.Type == "node_failure" {
+			gm.applyStatus(peer, "dead")
+			return
+		}
+		gm.markLeft(peer)
 	}
 }
 
@@ -227,30 +367,34 @@ func (gm *GossipManager) handleJoinMessage(message *GossipMessage) error {
 	if address, ok := message.Data["address"].(string); ok {
 		fmt.Printf("🤝 Node %s requesting to join cluster\n", nodeID)
 		
-		// Add the node to our peer list
-		gm.peers[nodeID] = &PeerInfo{
-			NodeID:       nodeID,
-			Address:      address,
-			Status:       "alive",
-			LastSeen:     time.Now(),
-			HeartbeatSeq: 0,
-			Incarnation:  time.Now().Unix(),
-		}
-
-		// Spread the rumor about this new node
-		gm.spreadRumor("node_join", map[string]interface{}{
-			"node_id": nodeID,
-			"address": address,
-		})
-
-		if gm.onNodeJoin != nil {
-			gm.onNodeJoin(nodeID, address)
-		}
+		// Older nodes don't send an incarnation; their heartbeats will carry it
+		incarnation, _ := dataInt64(message.Data, "incarnation")
+		gm.admit(nodeID, address, incarnation)
 	}
 
 	return nil
 }
 
+// admit adds a node that announced itself, at the incarnation it announced,
+// and spreads the rumor that it joined. Callers hold gm.mu.
+func (gm *GossipManager) admit(nodeID, address string, incarnation int64) {
+	gm.putPeer(&PeerInfo{
+		NodeID:       nodeID,
+		Address:      address,
+		Status:       "alive",
+		LastSeen:     gm.clock.Now(),
+		HeartbeatSeq: 0,
+		Incarnation:  incarnation,
+	})
+	gm.detector.Heartbeat(nodeID)
+
+	gm.spreadRumor("node_join", map[string]interface{}{
+		"node_id":     nodeID,
+		"address":     address,
+		"incarnation": incarnation,
+	})
+}
+
 // handleLeaveMessage processes explicit leave messages
 func (gm *GossipManager) handleLeaveMessage(message *GossipMessage) error {
 	nodeID := message.FromNode
@@ -258,147 +402,132 @@ func (gm *GossipManager) handleLeaveMessage(message *GossipMessage) error {
 	fmt.Printf("👋 Node %s leaving cluster\n", nodeID)
 	
 	if peer, exists := gm.peers[nodeID]; exists {
-		peer.Status = "dead"
+		gm.markLeft(peer)
 		
 		// Spread the rumor about this node leaving
 		gm.spreadRumor("node_leave", map[string]interface{}{
-			"node_id": nodeID,
+			"node_id":     nodeID,
+			"incarnation": peer.Incarnation,
 		})
-
-		if gm.onNodeLeave != nil {
-			gm.onNodeLeave(nodeID)
-		}
 	}
 
 	return nil
 }
 
-// handleGossipFailure handles failed gossip attempts
+// handleGossipFailure notes a gossip message a peer didn't take. It only
+// counts as a missed heartbeat: the peer wasn't heard from, so its phi keeps
+// rising, and checkPeers suspects it once phi says the silence is unusual.
+// A single lost message on a flaky link doesn't make a suspicion.
 func (gm *GossipManager) handleGossipFailure(nodeID string) {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
-
-	if peer, exists := gm.peers[nodeID]; exists {
-		if peer.Status == "alive" {
-			peer.Status = "suspected"
-			fmt.Printf("🤔 Node %s marked as suspected due to gossip failure\n", nodeID)
-			
-			// Start suspicion timer
-			go gm.handleSuspectedNode(nodeID)
-		}
-	}
-}
-
-// handleSuspectedNode handles the suspicion timeout for a node
-func (gm *GossipManager) handleSuspectedNode(nodeID string) {
-	time.Sleep(gm.config.SuspicionTimeout)
-	
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
 
-	if peer, exists := gm.peers[nodeID]; exists && peer.Status == "suspected" {
-		peer.Status = "dead"
-		fmt.Printf("💀 Node %s marked as dead after suspicion timeout\n", nodeID)
-		
-		// Spread rumor about node failure
-		gm.spreadRumor("node_failure", map[string]interface{}{
-			"node_id": nodeID,
-		})
-
-		if gm.onNodeFail != nil {
-			gm.onNodeFail(nodeID)
-		}
+	if peer, exists := gm.peers[nodeID]; exists && peer.Status == "alive" {
+		fmt.Printf("📉 Missed heartbeat to %s (φ=%.1f)\n", nodeID, gm.detector.Phi(nodeID))
 	}
 }
 
 // markNodeAsSuspected marks a node as suspected
 func (gm *GossipManager) markNodeAsSuspected(nodeID string) {
 	if peer, exists := gm.peers[nodeID]; exists && peer.Status == "alive" {
-		peer.Status = "suspected"
+		gm.setStatus(peer, "suspected")
 		fmt.Printf("🤔 Node %s marked as suspected\n", nodeID)
 		
-		go gm.handleSuspectedNode(nodeID)
+		gm.clock.Go(func() { gm.indirectProbe(nodeID) })
 	}
 }
 
-// spreadRumor creates and spreads a rumor through the cluster
+// spreadRumor creates a rumor; it rides along on our outgoing messages until
+// it has been sent retransmitLimit times. Callers hold gm.mu.
 func (gm *GossipManager) spreadRumor(rumorType string, data map[string]interface{}) {
-	rumorID := fmt.Sprintf("%s-%s-%d", gm.currentNode.ID, rumorType, time.Now().UnixNano())
+	rumorID := fmt.Sprintf("%s-%s-%d", gm.currentNode.ID, rumorType, gm.clock.Now().UnixNano())
 	
 	rumor := &Rumor{
 		ID:          rumorID,
 		Type:        rumorType,
 		Data:        data,
-		Timestamp:   time.Now().Unix(),
+		Timestamp:   gm.clock.Now().Unix(),
 		Origin:      gm.currentNode.ID,
 		SpreadCount: 0,
-		MaxSpread:   gm.config.RumorSpreadLimit,
+		MaxSpread:   gm.retransmitLimit(),
 	}
 
 	gm.rumors[rumorID] = rumor
 	fmt.Printf("📢 Created rumor: %s (type: %s)\n", rumorID, rumorType)
 }
 
-// handleSeedDiscovery processes seed discovery requests
-func (gm *GossipManager) handleSeedDiscovery(message *GossipMessage) error {
+// handleSeedDiscovery admits a node joining through us and answers with who
+// we are and everything we know, so it needn't know our node ID up front
+func (gm *GossipManager) handleSeedDiscovery(message *GossipMessage) (*GossipMessage, error) {
 	fmt.Printf("🔍 Received seed discovery from %s\n", message.FromNode)
-	
-	// Add the requesting node to our peer list with correct incarnation
-	if requesterAddress, ok := message.Data["requester_address"].(string); ok {
-		gm.peers[message.FromNode] = &PeerInfo{
-			NodeID:       message.FromNode,
-			Address:      requesterAddress,
-			Status:       "alive",
-			LastSeen:     time.Now(),
-			HeartbeatSeq: 0,
-			Incarnation:  time.Now().Unix(), // Use current time as incarnation
-		}
-		
-		fmt.Printf("📝 Added discovering node %s to peer list with incarnation %d\n", 
-			message.FromNode, gm.peers[message.FromNode].Incarnation)
-		
-		// Trigger the join callback
-		if gm.onNodeJoin != nil {
-			fmt.Printf("🔄 Triggering join callback for discovering node %s\n", message.FromNode)
-			gm.onNodeJoin(message.FromNode, requesterAddress)
-		}
-		
-		// Send our current state back to the requester immediately
-		go gm.sendStateToRequester(message.FromNode, requesterAddress)
+
+	requesterAddress, ok := message.Data["requester_address"].(string)
+	if !ok || message.FromNode == "" {
+		return nil, fmt.Errorf("seed discovery without requester node ID and address")
+	}
+
+	// A node reaching itself under another address learns its own ID
+	if message.FromNode != gm.currentNode.ID {
+		incarnation, _ := dataInt64(message.Data, "incarnation")
+		gm.admit(message.FromNode, requesterAddress, incarnation)
+		fmt.Printf("📝 Added discovering node %s to peer list with incarnation %d\n", message.FromNode, incarnation)
+	}
+
+	data := gm.prepareGossipData()
+	gm.attachRumorsLocked(data)
+	data["node_id"] = gm.currentNode.ID
+	data["address"] = gm.currentNode.Address
+	data["incarnation"] = gm.peers[gm.currentNode.ID].Incarnation
+
+	return &GossipMessage{
+		Type:      "seed_discovery_response",
+		FromNode:  gm.currentNode.ID,
+		ToNode:    message.FromNode,
+		Timestamp: gm.clock.Now().Unix(),
+		Data:      data,
+		MessageID: generateMessageID(),
+	}, nil
+}
+
+// handlePushPull merges a peer's full state and answers with ours
+func (gm *GossipManager) handlePushPull(message *GossipMessage) error {
+	fmt.Printf("🔁 Push-pull sync from %s\n", message.FromNode)
+
+	if err := gm.handleHeartbeat(message); err != nil {
+		return err
+	}
+
+	if peer, exists := gm.peers[message.FromNode]; exists {
+		address := peer.Address
+		gm.clock.Go(func() { gm.sendStateToRequester(message.FromNode, address) })
 	}
-	
 	return nil
 }
 
-// sendStateToRequester sends our current state to a node that performed seed discovery
+// sendStateToRequester sends our full state to a node that performed
+// push-pull sync
 func (gm *GossipManager) sendStateToRequester(nodeID, address string) {
-	fmt.Printf("📤 Sending current state to discovering node %s\n", nodeID)
+	fmt.Printf("📤 Sending current state to %s\n", nodeID)
 	
 	// Prepare our current gossip data
+	gm.mu.Lock()
 	gossipData := gm.prepareGossipData()
+	gm.attachRumorsLocked(gossipData)
+	gm.mu.Unlock()
 	
 	stateMessage := GossipMessage{
 		Type:      "heartbeat", // Use heartbeat to send our state
 		FromNode:  gm.currentNode.ID,
 		ToNode:    nodeID,
-		Timestamp: time.Now().Unix(),
+		Timestamp: gm.clock.Now().Unix(),
 		Data:      gossipData,
 		MessageID: generateMessageID(),
 	}
 	
-	url := fmt.Sprintf("http://%s/gossip/receive", address)
-	jsonData, err := json.Marshal(stateMessage)
-	if err != nil {
-		fmt.Printf("❌ Failed to marshal state message for %s: %v\n", nodeID, err)
-		return
-	}
-	
-	resp, err := gm.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
+	if err := gm.send(address, &stateMessage, gm.config.ProbeTimeout); err != nil {
 		fmt.Printf("❌ Failed to send state to %s: %v\n", nodeID, err)
 		return
 	}
-	defer resp.Body.Close()
 	
-	fmt.Printf("✅ State sent to discovering node %s\n", nodeID)
+	fmt.Printf("✅ State sent to %s\n", nodeID)
 }
\ No newline at end of file
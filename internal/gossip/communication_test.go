@@ -0,0 +1,260 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/transport"
+)
+
+// claim is a heartbeat from one node carrying its view of another
+func claim(from, about, status string, incarnation int64) *GossipMessage {
+	return &GossipMessage{
+		Type:     "heartbeat",
+		FromNode: from,
+		Data: map[string]interface{}{
+			"peers": map[string]*PeerInfo{
+				about: {NodeID: about, Address: about + ":9000", Status: status, Incarnation: incarnation},
+			},
+		},
+	}
+}
+
+func setPeerStatus(gm *GossipManager, nodeID, status string) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	gm.setStatus(gm.peers[nodeID], status)
+}
+
+func peerIncarnation(gm *GossipManager, nodeID string) int64 {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+
+	return gm.peers[nodeID].Incarnation
+}
+
+func TestRefuteClaimsAboutSelf(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		offset int64 // Claimed incarnation relative to ours
+		want   int64 // Our incarnation afterwards, relative to before
+	}{
+		{name: "suspected", status: "suspected", want: 1},
+		{name: "dead", status: "dead", want: 1},
+		{name: "suspected at a later incarnation", status: "suspected", offset: 5, want: 6},
+		{name: "suspected at an older incarnation", status: "suspected", offset: -1, want: 0},
+		{name: "alive", status: "alive", offset: 3, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewSimulated(time.Unix(1000, 0))
+			a := newTestManager(t, "a", transport.NewNetwork(1), clk)
+			join(t, a, "b", 1)
+			before := a.selfIncarnation()
+
+			if _, err := a.HandleGossipRequest(claim("b", "a", tt.status, before+tt.offset)); err != nil {
+				t.Fatalf("HandleGossipRequest: %v", err)
+			}
+			if got := a.selfIncarnation() - before; got != tt.want {
+				t.Errorf("incarnation moved by %d, want %d", got, tt.want)
+			}
+			if status := peerStatus(a, "a"); status != "alive" {
+				t.Errorf("a sees itself %s, want alive", status)
+			}
+		})
+	}
+}
+
+func TestRefuteFailureRumor(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(1000, 0))
+	a := newTestManager(t, "a", transport.NewNetwork(1), clk)
+	join(t, a, "b", 1)
+	before := a.selfIncarnation()
+
+	_, err := a.HandleGossipRequest(&GossipMessage{
+		Type:     "heartbeat",
+		FromNode: "b",
+		Data: map[string]interface{}{
+			"rumors": map[string]*Rumor{
+				"b-node_failure-1": {
+					ID:   "b-node_failure-1",
+					Type: "node_failure",
+					Data: map[string]interface{}{"node_id": "a", "incarnation": before},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("HandleGossipRequest: %v", err)
+	}
+	if got := a.selfIncarnation(); got != before+1 {
+		t.Errorf("incarnation %d after a failure rumor about us, want %d", got, before+1)
+	}
+}
+
+// A refutation has to win on nodes that never hear from the suspect itself:
+// only the raised incarnation lets the alive entry override the suspicion
+func TestRefutationSpreadsPastTheSuspicion(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(1000, 0))
+	network := transport.NewNetwork(1)
+	network.SetClock(clk)
+	a := newTestManager(t, "a", network, clk)
+	b := newTestManager(t, "b", network, clk)
+	c := newTestManager(t, "c", network, clk)
+	incarnation := a.selfIncarnation()
+
+	join(t, a, "b", 1)
+	for _, gm := range []*GossipManager{b, c} {
+		join(t, gm, "a", incarnation)
+		setPeerStatus(gm, "a", "suspected")
+	}
+	join(t, b, "c", 1)
+	join(t, c, "b", 1)
+
+	// a learns of the suspicion from b, refutes it and gossips to b
+	if _, err := a.HandleGossipRequest(claim("b", "a", "suspected", incarnation)); err != nil {
+		t.Fatalf("HandleGossipRequest: %v", err)
+	}
+	clk.Advance(time.Second)
+	if status, got := peerStatus(b, "a"), peerIncarnation(b, "a"); status != "alive" || got != incarnation+1 {
+		t.Fatalf("b sees a %s at incarnation %d, want alive at %d", status, got, incarnation+1)
+	}
+
+	// c only hears about it from b
+	clk.Go(b.performGossipRound)
+	clk.Advance(time.Second)
+	if status, got := peerStatus(c, "a"), peerIncarnation(c, "a"); status != "alive" || got != incarnation+1 {
+		t.Errorf("c sees a %s at incarnation %d, want alive at %d", status, got, incarnation+1)
+	}
+}
+
+func TestHeartbeatLeavesStatusToIncarnations(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string // What a believes about b
+		message *GossipMessage
+		want    string
+	}{
+		{name: "dead, no entry", status: "dead", message: claim("b", "c", "alive", 1), want: "dead"},
+		{name: "dead, same incarnation", status: "dead", message: claim("b", "b", "alive", 1), want: "dead"},
+		{name: "dead, newer incarnation", status: "dead", message: claim("b", "b", "alive", 2), want: "alive"},
+		{name: "suspected, same incarnation", status: "suspected", message: claim("b", "b", "alive", 1), want: "suspected"},
+		{name: "suspected, newer incarnation", status: "suspected", message: claim("b", "b", "alive", 2), want: "alive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewSimulated(time.Unix(1000, 0))
+			a := newTestManager(t, "a", transport.NewNetwork(1), clk)
+			join(t, a, "b", 1)
+			join(t, a, "c", 1)
+			setPeerStatus(a, "b", tt.status)
+			clk.Advance(time.Minute)
+
+			if _, err := a.HandleGossipRequest(tt.message); err != nil {
+				t.Fatalf("HandleGossipRequest: %v", err)
+			}
+			if status := peerStatus(a, "b"); status != tt.want {
+				t.Errorf("b is %s after its heartbeat, want %s", status, tt.want)
+			}
+
+			a.mu.RLock()
+			lastSeen := a.peers["b"].LastSeen
+			a.mu.RUnlock()
+			if !lastSeen.Equal(clk.Now()) {
+				t.Errorf("b last seen %v, want the heartbeat's arrival at %v", lastSeen, clk.Now())
+			}
+		})
+	}
+}
+
+func TestSeedDiscovery(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(1000, 0))
+	network := transport.NewNetwork(1)
+	network.SetClock(clk)
+	seed := newTestManager(t, "seed", network, clk)
+	join(t, seed, "c", 7)
+
+	// The newcomer only knows the seed's address
+	clk.Advance(time.Second)
+	n := newTestManager(t, "n", network, clk)
+	var seedID string
+	var err error
+	clk.Go(func() { seedID, err = n.discoverSeed("seed:9000") })
+	clk.Advance(time.Second)
+	if err != nil || seedID != "seed" {
+		t.Fatalf("discoverSeed = %q, %v; want the seed's node ID", seedID, err)
+	}
+
+	// The seed admitted n at the incarnation it announced, and spreads it
+	if status, got := peerStatus(seed, "n"), peerIncarnation(seed, "n"); status != "alive" || got != n.selfIncarnation() {
+		t.Errorf("seed sees n %s at incarnation %d, want alive at %d", status, got, n.selfIncarnation())
+	}
+	seed.mu.RLock()
+	joinRumors := 0
+	for _, rumor := range seed.rumors {
+		if rumor.Type == "node_join" && rumor.Data["node_id"] == "n" {
+			joinRumors++
+		}
+	}
+	seed.mu.RUnlock()
+	if joinRumors != 1 {
+		t.Errorf("seed holds %d join rumors about n, want 1", joinRumors)
+	}
+
+	// n learned the seed and everything the seed knew
+	if status, got := peerStatus(n, "seed"), peerIncarnation(n, "seed"); status != "alive" || got != seed.selfIncarnation() {
+		t.Errorf("n sees the seed %s at incarnation %d, want alive at %d", status, got, seed.selfIncarnation())
+	}
+	if status, got := peerStatus(n, "c"), peerIncarnation(n, "c"); status != "alive" || got != 7 {
+		t.Errorf("n sees c %s at incarnation %d, want alive at 7", status, got)
+	}
+}
+
+func TestHandleSeedDiscovery(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		data    map[string]interface{}
+		wantErr bool
+		admits  bool
+	}{
+		{name: "newcomer", from: "n",
+			data: map[string]interface{}{"requester_address": "n:9000", "incarnation": int64(5)}, admits: true},
+		{name: "no address", from: "n",
+			data: map[string]interface{}{"incarnation": int64(5)}, wantErr: true},
+		{name: "no node ID",
+			data: map[string]interface{}{"requester_address": "n:9000"}, wantErr: true},
+		{name: "ourselves under another address", from: "seed",
+			data: map[string]interface{}{"requester_address": "127.0.0.1:9000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewSimulated(time.Unix(1000, 0))
+			seed := newTestManager(t, "seed", transport.NewNetwork(1), clk)
+
+			reply, err := seed.HandleGossipRequest(&GossipMessage{Type: "seed_discovery", FromNode: tt.from, Data: tt.data})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HandleGossipRequest error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && (reply.FromNode != "seed" || reply.Data["node_id"] != "seed" || reply.Data["address"] != "seed:9000") {
+				t.Errorf("reply from %s with %v, want the seed's identity", reply.FromNode, reply.Data)
+			}
+
+			seed.mu.RLock()
+			known := len(seed.peers)
+			seed.mu.RUnlock()
+			if admitted := known == 2; admitted != tt.admits {
+				t.Errorf("seed knows %d nodes, admitted the requester: %v, want %v", known, admitted, tt.admits)
+			}
+			if tt.admits && peerIncarnation(seed, "n") != 5 {
+				t.Errorf("n admitted at incarnation %d, want 5", peerIncarnation(seed, "n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"dynamodb/internal/clock"
+)
+
+// EventType says what happened to a member
+type EventType string
+
+const (
+	EventJoined          EventType = "joined"           // First heard of, alive
+	EventSuspected       EventType = "suspected"        // Suspected of having failed
+	EventAlive           EventType = "alive"            // Alive again after a suspicion, failure or leave
+	EventLeft            EventType = "left"             // Left the cluster gracefully
+	EventFailed          EventType = "failed"           // Declared dead
+	EventMetadataChanged EventType = "metadata_changed" // New address or incarnation, same status
+)
+
+// Event is a change in cluster membership as seen by this node
+type Event struct {
+	Type        EventType `json:"type"`
+	NodeID      string    `json:"node_id"`
+	Address     string    `json:"address"`
+	Status      string    `json:"status"` // The member's status after the event; "left" for leaves
+	Incarnation int64     `json:"incarnation"`
+	Time        time.Time `json:"time"`
+}
+
+// eventBus fans events out to subscribers. Publishing only queues, so it is
+// safe under gm.mu; each subscriber's queue is drained into its channel on a
+// goroutine of its own, in publish order. A slow subscriber only delays
+// itself.
+type eventBus struct {
+	clock clock.Clock
+
+	mu            sync.Mutex
+	subscriptions []*subscription // In the order they subscribed
+}
+
+type subscription struct {
+	ch   chan Event
+	done chan struct{}
+
+	mu       sync.Mutex
+	queue    []Event
+	draining bool
+	closed   bool
+}
+
+func newEventBus(clk clock.Clock) *eventBus {
+	return &eventBus{clock: clk}
+}
+
+// subscribe adds a subscriber; the returned function removes it and closes
+// its channel
+func (b *eventBus) subscribe() (<-chan Event, func()) {
+	sub := &subscription{
+		ch:   make(chan Event),
+		done: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	b.subscriptions = append(b.subscriptions, sub)
+	b.mu.Unlock()
+
+	return sub.ch, func() {
+		b.mu.Lock()
+		for i, other := range b.subscriptions {
+			if other == sub {
+				b.subscriptions = append(b.subscriptions[:i:i], b.subscriptions[i+1:]...)
+				break
+			}
+		}
+		b.mu.Unlock()
+		sub.close()
+	}
+}
+
+// publish queues an event for every subscriber
+func (b *eventBus) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, sub := range b.subscriptions {
+		if sub.push(event) {
+			b.clock.Go(sub.drain)
+		}
+	}
+}
+
+// close removes every subscriber, closing their channels
+func (b *eventBus) close() {
+	b.mu.Lock()
+	subscriptions := b.subscriptions
+	b.subscriptions = nil
+	b.mu.Unlock()
+
+	for _, sub := range subscriptions {
+		sub.close()
+	}
+}
+
+// push queues an event and reports whether a drain needs to be started
+func (s *subscription) push(event Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.queue = append(s.queue, event)
+	if s.draining {
+		return false
+	}
+	s.draining = true
+	return true
+}
+
+// drain delivers queued events until the queue is empty. Only one drain runs
+// at a time, which keeps events in order.
+func (s *subscription) drain() {
+	for {
+		s.mu.Lock()
+		if s.closed {
+			// close left the channel to us, as we might have been sending
+			s.draining = false
+			close(s.ch)
+			s.mu.Unlock()
+			return
+		}
+		if len(s.queue) == 0 {
+			s.draining = false
+			s.mu.Unlock()
+			return
+		}
+		event := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- event:
+		case <-s.done:
+		}
+	}
+}
+
+func (s *subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.queue = nil
+	close(s.done)
+	if !s.draining {
+		close(s.ch)
+	}
+}
+
+// putPeer stores a peer entry, replacing what we knew about it, and
+// publishes what changed. Callers hold gm.mu.
+func (gm *GossipManager) putPeer(peer *PeerInfo) {
+	var before *PeerInfo
+	if known, exists := gm.peers[peer.NodeID]; exists {
+		copied := *known
+		before = &copied
+	}
+	gm.peers[peer.NodeID] = peer
+	gm.announce(before, peer)
+}
+
+// setStatus moves a peer to a new status and publishes it. Callers hold
+// gm.mu.
+func (gm *GossipManager) setStatus(peer *PeerInfo, status string) {
+	before := *peer
+	peer.Status = status
+	gm.announce(&before, peer)
+}
+
+// markLeft records that a peer left gracefully. Gossip tracks it as dead;
+// subscribers hear it left. Callers hold gm.mu.
+func (gm *GossipManager) markLeft(peer *PeerInfo) {
+	peer.Status = "dead"
+	gm.detector.Remove(peer.NodeID)
+	gm.publish(EventLeft, peer, "left")
+}
+
+// announce publishes the events for a peer going from before (nil if it was
+// unknown) to its current entry. Callers hold gm.mu.
+func (gm *GossipManager) announce(before, peer *PeerInfo) {
+	if before == nil {
+		switch peer.Status {
+		case "dead":
+			gm.publish(EventFailed, peer, peer.Status)
+		case "suspected":
+			gm.publish(EventJoined, peer, "alive")
+			gm.publish(EventSuspected, peer, peer.Status)
+		default:
+			gm.publish(EventJoined, peer, peer.Status)
+		}
+		return
+	}
+
+	if before.Status != peer.Status {
+		switch peer.Status {
+		case "alive":
+			gm.publish(EventAlive, peer, peer.Status)
+		case "suspected":
+			gm.publish(EventSuspected, peer, peer.Status)
+		case "dead":
+			gm.publish(EventFailed, peer, peer.Status)
+		}
+		return
+	}
+
+	if before.Address != peer.Address || before.Incarnation != peer.Incarnation {
+		gm.publish(EventMetadataChanged, peer, peer.Status)
+	}
+}
+
+// publish queues an event about a peer. Events about ourselves aren't
+// published. Callers hold gm.mu.
+func (gm *GossipManager) publish(eventType EventType, peer *PeerInfo, status string) {
+	if peer.NodeID == gm.currentNode.ID {
+		return
+	}
+	gm.events.publish(Event{
+		Type:        eventType,
+		NodeID:      peer.NodeID,
+		Address:     peer.Address,
+		Status:      status,
+		Incarnation: peer.Incarnation,
+		Time:        gm.clock.Now(),
+	})
+}
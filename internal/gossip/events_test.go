@@ -0,0 +1,137 @@
+package gossip
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+)
+
+func numbered(i int) Event {
+	return Event{Type: EventAlive, NodeID: fmt.Sprintf("node-%d", i)}
+}
+
+// receive reads events until ch closes or nothing arrives for a while
+func receive(t *testing.T, ch <-chan Event) (events []Event, closed bool) {
+	t.Helper()
+
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return events, true
+			}
+			events = append(events, event)
+		case <-time.After(200 * time.Millisecond):
+			return events, false
+		}
+	}
+}
+
+func TestEventBusDeliversInOrder(t *testing.T) {
+	bus := newEventBus(clock.Real())
+	ch, unsubscribe := bus.subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < 500; i++ {
+		bus.publish(numbered(i))
+	}
+
+	events, closed := receive(t, ch)
+	if closed {
+		t.Fatal("channel closed while subscribed")
+	}
+	if len(events) != 500 {
+		t.Fatalf("received %d events, want 500", len(events))
+	}
+	for i, event := range events {
+		if event.NodeID != numbered(i).NodeID {
+			t.Fatalf("event %d is %s, want %s", i, event.NodeID, numbered(i).NodeID)
+		}
+	}
+}
+
+func TestEventBusAfterClose(t *testing.T) {
+	tests := []struct {
+		name string
+		// Events published, and read, before the subscription is closed
+		before, read int
+		close        func(bus *eventBus, unsubscribe func())
+	}{
+		{
+			name:   "unsubscribe with nothing queued",
+			before: 3, read: 3,
+			close: func(_ *eventBus, unsubscribe func()) { unsubscribe() },
+		},
+		{
+			name:   "unsubscribe while a send is blocked",
+			before: 5, read: 2,
+			close: func(_ *eventBus, unsubscribe func()) { unsubscribe() },
+		},
+		{
+			name:   "bus closed while a send is blocked",
+			before: 5, read: 2,
+			close: func(bus *eventBus, _ func()) { bus.close() },
+		},
+		{
+			name:   "closed twice",
+			before: 1, read: 0,
+			close: func(bus *eventBus, unsubscribe func()) {
+				bus.close()
+				unsubscribe()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := newEventBus(clock.Real())
+			ch, unsubscribe := bus.subscribe()
+
+			for i := 0; i < tt.before; i++ {
+				bus.publish(numbered(i))
+			}
+			for i := 0; i < tt.read; i++ {
+				if event := <-ch; event.NodeID != numbered(i).NodeID {
+					t.Fatalf("event %d is %s, want %s", i, event.NodeID, numbered(i).NodeID)
+				}
+			}
+
+			tt.close(bus, unsubscribe)
+			bus.publish(numbered(tt.before))
+
+			// Whatever still arrives is in order and from before the close;
+			// then the channel closes
+			events, closed := receive(t, ch)
+			if !closed {
+				t.Fatal("channel still open after close")
+			}
+			for i, event := range events {
+				want := numbered(tt.read + i)
+				if tt.read+i >= tt.before || event.NodeID != want.NodeID {
+					t.Errorf("after close got %s, want only events from before it, in order", event.NodeID)
+				}
+			}
+		})
+	}
+}
+
+func TestEventBusSlowSubscriberOnlyDelaysItself(t *testing.T) {
+	bus := newEventBus(clock.Real())
+	slow, unsubscribeSlow := bus.subscribe()
+	defer unsubscribeSlow()
+	fast, unsubscribeFast := bus.subscribe()
+	defer unsubscribeFast()
+
+	for i := 0; i < 10; i++ {
+		bus.publish(numbered(i))
+	}
+
+	if events, _ := receive(t, fast); len(events) != 10 {
+		t.Errorf("fast subscriber received %d events while the slow one read none, want 10", len(events))
+	}
+	if events, _ := receive(t, slow); len(events) != 10 {
+		t.Errorf("slow subscriber received %d events, want 10", len(events))
+	}
+}
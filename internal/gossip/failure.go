@@ -0,0 +1,70 @@
+package gossip
+
+import (
+	"fmt"
+)
+
+// heard records that a peer showed signs of life: a newer heartbeat, a
+// direct message or an answered probe. Callers hold gm.mu.
+func (gm *GossipManager) heard(peer *PeerInfo) {
+	peer.LastSeen = gm.clock.Now()
+	gm.detector.Heartbeat(peer.NodeID)
+}
+
+// Phi returns the failure detector's suspicion level for a node
+func (gm *GossipManager) Phi(nodeID string) float64 {
+	if nodeID == gm.currentNode.ID {
+		return 0
+	}
+	return gm.detector.Phi(nodeID)
+}
+
+// checkPeers judges every peer by its phi. A peer that has been quiet for
+// unusually long is suspected and probed through others; once its phi
+// reaches the dead threshold it is declared dead. A suspicion is only
+// cleared by hearing from the peer directly or by the peer refuting it.
+func (gm *GossipManager) checkPeers() {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	for _, peer := range gm.sortedPeers() {
+		if peer.NodeID == gm.currentNode.ID || peer.Status == "dead" {
+			continue
+		}
+
+		phi := gm.detector.Phi(peer.NodeID)
+		switch gm.detector.Classify(phi) {
+		case "suspected":
+			if peer.Status == "alive" {
+				gm.setStatus(peer, "suspected")
+				fmt.Printf("🤔 Node %s suspected (φ=%.1f)\n", peer.NodeID, phi)
+
+				nodeID := peer.NodeID
+				gm.clock.Go(func() { gm.indirectProbe(nodeID) })
+			}
+		case "dead":
+			if peer.Status == "suspected" {
+				gm.declareDead(peer, phi)
+			} else {
+				// Went quiet between two checks: suspect first, so the
+				// suspicion gossips out and the node gets a chance to refute
+				gm.setStatus(peer, "suspected")
+				fmt.Printf("🤔 Node %s suspected (φ=%.1f)\n", peer.NodeID, phi)
+			}
+		}
+	}
+}
+
+// declareDead marks a suspected peer dead and spreads the news. Callers
+// hold gm.mu.
+func (gm *GossipManager) declareDead(peer *PeerInfo, phi float64) {
+	gm.setStatus(peer, "dead")
+	gm.detector.Remove(peer.NodeID)
+	fmt.Printf("💀 Node %s marked as dead (φ=%.1f)\n", peer.NodeID, phi)
+
+	// Spread rumor about node failure
+	gm.spreadRumor("node_failure", map[string]interface{}{
+		"node_id":     peer.NodeID,
+		"incarnation": peer.Incarnation,
+	})
+}
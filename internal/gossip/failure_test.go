@@ -0,0 +1,35 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/transport"
+)
+
+func TestGossipFailureIsOnlyAMissedHeartbeat(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(1000, 0))
+	network := transport.NewNetwork(1)
+	network.SetClock(clk)
+	a := newTestManager(t, "a", network, clk)
+	join(t, a, "b", 1)
+
+	// b isn't on the network, so the send fails at once
+	a.mu.RLock()
+	peer := a.peers["b"]
+	a.mu.RUnlock()
+	a.sendGossip(peer, map[string]interface{}{})
+
+	a.checkPeers()
+	if status := peerStatus(a, "b"); status != "alive" {
+		t.Fatalf("b is %s after one failed gossip, want alive", status)
+	}
+
+	// Staying silent is what gets it suspected
+	clk.Advance(10 * a.config.GossipInterval)
+	a.checkPeers()
+	if status := peerStatus(a, "b"); status != "suspected" {
+		t.Errorf("b is %s after 10 silent intervals, want suspected", status)
+	}
+}
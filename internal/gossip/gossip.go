@@ -1,35 +1,44 @@
 package gossip
 
 import (
-	"bytes"
 	"context"
 	"crypto/rand"
-	"encoding/json"
 	"fmt"
 	"math/big"
-	"net/http"
+	mathrand "math/rand"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"dynamodb/internal/clock"
+	"dynamodb/internal/detector"
+	"dynamodb/internal/membership"
 	"dynamodb/internal/node"
 )
 
+const (
+	seedDiscoveryTimeout = 5 * time.Second  // Bound on one seed discovery request
+	seedMinBackoff       = 1 * time.Second  // First wait after no seed answered
+	seedMaxBackoff       = 30 * time.Second // Wait cap between rounds of seed discovery
+)
+
 // GossipMessage represents different types of gossip messages
 type GossipMessage struct {
-	Type        string                 `json:"type"`        // "join", "leave", "heartbeat", "rumor"
-	FromNode    string                 `json:"from_node"`   // Sender node ID
-	ToNode      string                 `json:"to_node"`     // Target node ID (if specific)
-	Timestamp   int64                  `json:"timestamp"`   // Message timestamp
-	Data        map[string]interface{} `json:"data"`        // Message payload
-	TTL         int                    `json:"ttl"`         // Time to live for rumor propagation
-	MessageID   string                 `json:"message_id"`  // Unique message identifier
+	Type      string                 `json:"type"`       // "join", "leave", "heartbeat", "rumor"
+	FromNode  string                 `json:"from_node"`  // Sender node ID
+	ToNode    string                 `json:"to_node"`    // Target node ID (if specific)
+	Timestamp int64                  `json:"timestamp"`  // Message timestamp
+	Data      map[string]interface{} `json:"data"`       // Message payload
+	TTL       int                    `json:"ttl"`        // Time to live for rumor propagation
+	MessageID string                 `json:"message_id"` // Unique message identifier
 }
 
 // PeerInfo represents information about a cluster member
 type PeerInfo struct {
 	NodeID       string    `json:"node_id"`
 	Address      string    `json:"address"`
-	Status       string    `json:"status"`        // "alive", "suspected", "dead"
+	Status       string    `json:"status"` // "alive", "suspected", "dead"
 	LastSeen     time.Time `json:"last_seen"`
 	HeartbeatSeq int64     `json:"heartbeat_seq"` // Heartbeat sequence number
 	Incarnation  int64     `json:"incarnation"`   // Node incarnation number
@@ -38,7 +47,7 @@ type PeerInfo struct {
 // Rumor represents a piece of information being spread through the cluster
 type Rumor struct {
 	ID          string                 `json:"id"`
-	Type        string                 `json:"type"`     // "node_join", "node_leave", "node_failure"
+	Type        string                 `json:"type"` // "node_join", "node_leave", "node_failure"
 	Data        map[string]interface{} `json:"data"`
 	Timestamp   int64                  `json:"timestamp"`
 	Origin      string                 `json:"origin"`
@@ -48,73 +57,114 @@ type Rumor struct {
 
 // GossipConfig holds configuration for the gossip protocol
 type GossipConfig struct {
-	GossipInterval    time.Duration // How often to gossip
-	ProbeInterval     time.Duration // How often to probe nodes
-	ProbeTimeout      time.Duration // Timeout for probe responses
-	SuspicionTimeout  time.Duration // How long to wait before marking suspected nodes as dead
-	GossipNodes       int           // Number of nodes to gossip to each round
-	RumorTTL          int           // Maximum TTL for rumors
-	RumorSpreadLimit  int           // Maximum times to spread a rumor
+	GossipInterval      time.Duration // How often to gossip
+	ProbeInterval       time.Duration // How often to probe nodes
+	ProbeTimeout        time.Duration // Timeout for probe responses
+	PhiSuspect          float64       // Failure detector φ at which a quiet peer is suspected (0 = default)
+	PhiDead             float64       // φ at which a suspected peer is declared dead (0 = default)
+	GossipNodes         int           // Number of nodes to gossip to each round
+	RumorRetransmitMult int           // λ: each node sends a rumor λ·log10(N+1) times, then retires it
+	RumorsPerMessage    int           // Most rumors piggybacked on one message
+	RumorRetention      time.Duration // How long rumors are remembered, so late copies aren't taken for new ones
+	PushPullInterval    time.Duration // How often to sync full state with one peer when gossiping over packets
+	Clock               clock.Clock   // Time source and scheduler (nil = wall clock)
+	Seed                int64         // Seeds peer selection (0 = random per manager)
 }
 
 // DefaultGossipConfig returns sensible defaults for gossip protocol
 func DefaultGossipConfig() *GossipConfig {
 	return &GossipConfig{
-		GossipInterval:    1 * time.Second,
-		ProbeInterval:     3 * time.Second,
-		ProbeTimeout:      1 * time.Second,
-		SuspicionTimeout:  5 * time.Second,
-		GossipNodes:       3,
-		RumorTTL:          10,
-		RumorSpreadLimit:  5,
+		GossipInterval:      1 * time.Second,
+		ProbeInterval:       3 * time.Second,
+		ProbeTimeout:        1 * time.Second,
+		PhiSuspect:          5,
+		PhiDead:             12,
+		GossipNodes:         3,
+		RumorRetransmitMult: 4,
+		RumorsPerMessage:    6,
+		RumorRetention:      5 * time.Minute,
+		PushPullInterval:    30 * time.Second,
 	}
 }
 
 // GossipManager manages the gossip protocol for cluster membership
 type GossipManager struct {
-	mu           sync.RWMutex
-	config       *GossipConfig
-	currentNode  *node.Node
-	peers        map[string]*PeerInfo
-	rumors       map[string]*Rumor
-	httpClient   *http.Client
-	ctx          context.Context
-	cancel       context.CancelFunc
-	
-	// Callbacks
-	onNodeJoin   func(nodeID, address string)
-	onNodeLeave  func(nodeID string)
-	onNodeFail   func(nodeID string)
+	mu          sync.RWMutex
+	config      *GossipConfig
+	currentNode *node.Node
+	peers       map[string]*PeerInfo
+	rumors      map[string]*Rumor
+	settings    map[string]*Setting // Cluster-wide settings, by key
+	transport   Transport
+	packets     Transport // Probes and heartbeats; nil sends them over transport
+	clock       clock.Clock
+	detector    *detector.Detector // Learns each peer's heartbeat rhythm
+	events      *eventBus          // Membership changes, for subscribers
+	rngMu       sync.Mutex
+	rng         *mathrand.Rand
+	ctx         context.Context
+	cancel      context.CancelFunc
+
+	// Indirect probes waiting for a helper's result, by request ID
+	indirectProbes map[string]chan bool
+
+	// Cluster-wide setting changes are queued under mu and handed to the
+	// watchers outside it, one at a time and in order, under settingsDelivery
+	pendingSettings  []settingChange
+	settingsDelivery sync.Mutex
+	settingWatchers  []func(key, value string)
 }
 
 // NewGossipManager creates a new gossip manager
-func NewGossipManager(currentNode *node.Node, config *GossipConfig) *GossipManager {
+func NewGossipManager(currentNode *node.Node, config *GossipConfig, peerTransport Transport) *GossipManager {
 	if config == nil {
 		config = DefaultGossipConfig()
 	}
+	if peerTransport == nil {
+		peerTransport = NewHTTPTransport(nil)
+	}
+
+	seed := config.Seed
+	if seed == 0 {
+		n, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+		seed = n.Int64()
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
+	detectorConfig := detector.DefaultConfig(config.GossipInterval)
+	if config.PhiSuspect > 0 {
+		detectorConfig.SuspectPhi = config.PhiSuspect
+	}
+	if config.PhiDead > 0 {
+		detectorConfig.DeadPhi = config.PhiDead
+	}
+
 	gm := &GossipManager{
-		config:      config,
-		currentNode: currentNode,
-		peers:       make(map[string]*PeerInfo),
-		rumors:      make(map[string]*Rumor),
-		httpClient: &http.Client{
-			Timeout: config.ProbeTimeout,
-		},
-		ctx:    ctx,
-		cancel: cancel,
+		config:         config,
+		currentNode:    currentNode,
+		peers:          make(map[string]*PeerInfo),
+		rumors:         make(map[string]*Rumor),
+		settings:       make(map[string]*Setting),
+		transport:      peerTransport,
+		clock:          clock.OrReal(config.Clock),
+		detector:       detector.New(detectorConfig, config.Clock),
+		events:         newEventBus(clock.OrReal(config.Clock)),
+		rng:            mathrand.New(mathrand.NewSource(seed)),
+		ctx:            ctx,
+		cancel:         cancel,
+		indirectProbes: make(map[string]chan bool),
 	}
 
 	// Add ourselves to the peer list
+	now := gm.clock.Now()
 	gm.peers[currentNode.ID] = &PeerInfo{
 		NodeID:       currentNode.ID,
 		Address:      currentNode.Address,
 		Status:       "alive",
-		LastSeen:     time.Now(),
+		LastSeen:     now,
 		HeartbeatSeq: 0,
-		Incarnation:  time.Now().Unix(),
+		Incarnation:  now.Unix(),
 	}
 
 	return gm
@@ -123,19 +173,27 @@ func NewGossipManager(currentNode *node.Node, config *GossipConfig) *GossipManag
 // Start begins the gossip protocol
 func (gm *GossipManager) Start() {
 	fmt.Printf("🗣️ Starting gossip protocol for node %s\n", gm.currentNode.ID)
-	
+
 	// Start gossip routine
-	go gm.gossipRoutine()
-	
+	gm.clock.Every(gm.ctx, gm.config.GossipInterval, gm.performGossipRound)
+
 	// Start probe routine
-	go gm.probeRoutine()
-	
+	gm.clock.Every(gm.ctx, gm.config.ProbeInterval, gm.performProbeRound)
+
+	// Judge peers by their failure detector phi
+	gm.clock.Every(gm.ctx, gm.config.GossipInterval, gm.checkPeers)
+
+	// Heartbeats over packets carry what fits; sync the rest periodically
+	if gm.packets != nil {
+		gm.clock.Every(gm.ctx, gm.config.PushPullInterval, gm.performPushPull)
+	}
+
 	// Start rumor cleanup routine
-	go gm.rumorCleanupRoutine()
-	
+	gm.clock.Every(gm.ctx, 30*time.Second, gm.cleanupOldRumors)
+
 	// Start self-maintenance routine
-	go gm.selfMaintenanceRoutine()
-	
+	gm.clock.Every(gm.ctx, 5*time.Second, gm.maintainSelf)
+
 	fmt.Printf("✅ Gossip protocol started\n")
 }
 
@@ -143,96 +201,130 @@ func (gm *GossipManager) Start() {
 func (gm *GossipManager) Stop() {
 	fmt.Printf("🛑 Stopping gossip protocol for node %s\n", gm.currentNode.ID)
 	gm.cancel()
+	gm.events.close()
 }
 
-// AddSeedNode adds a seed node for initial cluster discovery
-func (gm *GossipManager) AddSeedNode(nodeID, address string) {
-	if nodeID == gm.currentNode.ID {
-		return // Don't add ourselves
+// AddSeeds joins the cluster through seed nodes known only by address. Each
+// seed answers discovery with its node ID and full membership. Seeds that
+// can't be reached are retried with exponential backoff until one answers
+// or the manager stops. Our own address is skipped, so every node can be
+// given the same list.
+func (gm *GossipManager) AddSeeds(addresses ...string) {
+	var seeds []string
+	for _, address := range addresses {
+		if address != "" && address != gm.currentNode.Address {
+			seeds = append(seeds, address)
+		}
+	}
+	if len(seeds) == 0 {
+		return
 	}
 
-	fmt.Printf("🌱 Adding seed node: %s (%s)\n", nodeID, address)
-	
-	// First, actively discover the seed node's current state
-	go gm.performSeedNodeDiscovery(nodeID, address)
+	fmt.Printf("🌱 Adding seed nodes: %s\n", strings.Join(seeds, ", "))
+	gm.clock.Go(func() { gm.joinSeeds(seeds) })
 }
 
-// SetCallbacks sets the callback functions for node events
-func (gm *GossipManager) SetCallbacks(onJoin func(string, string), onLeave, onFail func(string)) {
-	gm.onNodeJoin = onJoin
-	gm.onNodeLeave = onLeave
-	gm.onNodeFail = onFail
+// SetPacketTransport sends probes, acks and heartbeats over a lightweight
+// packet transport such as UDP, keeping the peer transport for full state
+// sync, joins and leaves. Call it before Start.
+func (gm *GossipManager) SetPacketTransport(packets Transport) {
+	gm.packets = packets
 }
 
-// gossipRoutine runs the main gossip loop
-func (gm *GossipManager) gossipRoutine() {
-	ticker := time.NewTicker(gm.config.GossipInterval)
-	defer ticker.Stop()
+// Subscribe returns a channel of membership events from now on, and a
+// function that unsubscribes and closes the channel. Events are delivered
+// outside the gossip lock, in the order they happened; undelivered events
+// are buffered, so a slow reader only falls behind itself. The channel is
+// also closed when the manager stops.
+func (gm *GossipManager) Subscribe() (<-chan Event, func()) {
+	return gm.events.subscribe()
+}
 
-	for {
-		select {
-		case <-gm.ctx.Done():
-			return
-		case <-ticker.C:
-			gm.performGossipRound()
+// SetMembership makes gossip the membership service's failure detector: it
+// follows our events and reports suspicion levels from our detector
+func (gm *GossipManager) SetMembership(members *membership.Service) {
+	events, _ := gm.Subscribe()
+	members.SetPhiSource(gm.Phi)
+
+	gm.clock.Go(func() {
+		for event := range events {
+			members.Update(event.NodeID, event.Address, event.Status, event.Incarnation)
 		}
-	}
+	})
 }
 
-// probeRoutine runs the failure detection probe loop
-func (gm *GossipManager) probeRoutine() {
-	ticker := time.NewTicker(gm.config.ProbeInterval)
-	defer ticker.Stop()
+// performGossipRound performs one round of gossip
+func (gm *GossipManager) performGossipRound() {
+	gm.mu.Lock()
 
-	for {
-		select {
-		case <-gm.ctx.Done():
-			return
-		case <-ticker.C:
-			gm.performProbeRound()
+	// Select random peers to gossip with
+	peers := gm.selectRandomPeers(gm.config.GossipNodes)
+
+	// Also gossip to one node we believe is dead: if it is in fact running
+	// (e.g. after a partition heals) it learns the claim and refutes it
+	if dead := gm.selectDeadPeer(); dead != nil {
+		peers = append(peers, dead)
+	}
+
+	// Prepare gossip payload; each peer gets its own share of rumors
+	gossipData := gm.prepareGossipData()
+	payloads := make([]map[string]interface{}, len(peers))
+	for i := range peers {
+		payload := make(map[string]interface{}, len(gossipData)+1)
+		for key, value := range gossipData {
+			payload[key] = value
 		}
+		gm.attachRumorsLocked(payload)
+		payloads[i] = payload
 	}
-}
 
-// rumorCleanupRoutine cleans up old rumors
-func (gm *GossipManager) rumorCleanupRoutine() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
+	gm.mu.Unlock()
 
-	for {
-		select {
-		case <-gm.ctx.Done():
-			return
-		case <-ticker.C:
-			gm.cleanupOldRumors()
-		}
+	// Send gossip to selected peers
+	for i, peer := range peers {
+		peer, payload := peer, payloads[i]
+		gm.clock.Go(func() { gm.sendGossip(peer, payload) })
 	}
 }
 
-// performGossipRound performs one round of gossip
-func (gm *GossipManager) performGossipRound() {
-	gm.mu.RLock()
-	
-	// Select random peers to gossip with
-	peers := gm.selectRandomPeers(gm.config.GossipNodes)
-	
-	// Prepare gossip payload
+// performPushPull sends our full state to one random peer over the peer
+// transport; it answers with its own. Heartbeats over packets only carry
+// what fits in a datagram, so this is what guarantees full convergence.
+func (gm *GossipManager) performPushPull() {
+	gm.mu.Lock()
+	peers := gm.selectRandomPeers(1)
+	if len(peers) == 0 {
+		gm.mu.Unlock()
+		return
+	}
+	peer := peers[0]
 	gossipData := gm.prepareGossipData()
-	
-	gm.mu.RUnlock()
+	gm.mu.Unlock()
 
-	// Send gossip to selected peers
-	for _, peer := range peers {
-		go gm.sendGossip(peer, gossipData)
+	message := GossipMessage{
+		Type:      "push_pull",
+		FromNode:  gm.currentNode.ID,
+		ToNode:    peer.NodeID,
+		Timestamp: gm.clock.Now().Unix(),
+		Data:      gossipData,
+		MessageID: generateMessageID(),
+	}
+
+	if err := gm.send(peer.Address, &message, gm.config.ProbeTimeout); err != nil {
+		fmt.Printf("❌ Push-pull sync with %s failed: %v\n", peer.NodeID, err)
+		return
 	}
+	fmt.Printf("🔁 Push-pull sync with %s\n", peer.NodeID)
 }
 
-// selectRandomPeers selects random peers for gossip (excluding ourselves)
+// selectRandomPeers selects random peers for gossip (excluding ourselves).
+// Suspected peers are still members: gossiping to them is how they learn of
+// the suspicion and refute it.
 func (gm *GossipManager) selectRandomPeers(count int) []*PeerInfo {
 	alivePeers := make([]*PeerInfo, 0)
-	
+
 	for nodeID, peer := range gm.peers {
-		if nodeID != gm.currentNode.ID && peer.Status == "alive" {
+		if nodeID != gm.currentNode.ID && peer.Status != "dead" {
 			alivePeers = append(alivePeers, peer)
 		}
 	}
@@ -241,12 +333,14 @@ func (gm *GossipManager) selectRandomPeers(count int) []*PeerInfo {
 		return nil
 	}
 
+	// Start from a fixed order so the seeded shuffle is reproducible
+	sortPeers(alivePeers)
+
 	// Shuffle and select up to 'count' peers
 	selected := make([]*PeerInfo, 0, count)
 	for i := 0; i < count && i < len(alivePeers); i++ {
-		idx, _ := rand.Int(rand.Reader, big.NewInt(int64(len(alivePeers)-i)))
-		selectedIdx := int(idx.Int64()) + i
-		
+		selectedIdx := gm.randomIndex(len(alivePeers)-i) + i
+
 		// Swap and select
 		alivePeers[i], alivePeers[selectedIdx] = alivePeers[selectedIdx], alivePeers[i]
 		selected = append(selected, alivePeers[i])
@@ -255,13 +349,65 @@ func (gm *GossipManager) selectRandomPeers(count int) []*PeerInfo {
 	return selected
 }
 
-// prepareGossipData prepares the data to gossip
+// selectDeadPeer picks a random peer marked dead, or nil if there is none
+func (gm *GossipManager) selectDeadPeer() *PeerInfo {
+	dead := make([]*PeerInfo, 0)
+	for _, peer := range gm.sortedPeers() {
+		if peer.NodeID != gm.currentNode.ID && peer.Status == "dead" {
+			dead = append(dead, peer)
+		}
+	}
+
+	if len(dead) == 0 {
+		return nil
+	}
+	return dead[gm.randomIndex(len(dead))]
+}
+
+// randomIndex returns a random index below n from the manager's seeded source
+func (gm *GossipManager) randomIndex(n int) int {
+	gm.rngMu.Lock()
+	defer gm.rngMu.Unlock()
+
+	return gm.rng.Intn(n)
+}
+
+// sortedPeers returns the known peers ordered by node ID. Callers hold gm.mu.
+func (gm *GossipManager) sortedPeers() []*PeerInfo {
+	peers := make([]*PeerInfo, 0, len(gm.peers))
+	for _, peer := range gm.peers {
+		peers = append(peers, peer)
+	}
+	sortPeers(peers)
+	return peers
+}
+
+// sortPeers orders peers by node ID
+func sortPeers(peers []*PeerInfo) {
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].NodeID < peers[j].NodeID
+	})
+}
+
+// sortedKeys returns a map's keys in order, so merging a message's entries
+// doesn't depend on map iteration order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// prepareGossipData prepares the data to gossip: our heartbeat and the
+// membership table. Rumors are attached per message. Callers hold gm.mu.
 func (gm *GossipManager) prepareGossipData() map[string]interface{} {
 	// Increment our heartbeat
 	ourPeer := gm.peers[gm.currentNode.ID]
 	if ourPeer != nil {
 		ourPeer.HeartbeatSeq++
-		ourPeer.LastSeen = time.Now()
+		ourPeer.LastSeen = gm.clock.Now()
 	}
 
 	// Create safe copies without nil pointers
@@ -273,19 +419,11 @@ func (gm *GossipManager) prepareGossipData() map[string]interface{} {
 		}
 	}
 
-	safeRumors := make(map[string]*Rumor)
-	for k, v := range gm.rumors {
-		if v != nil {
-			rumorCopy := *v
-			safeRumors[k] = &rumorCopy
-		}
-	}
-
 	data := map[string]interface{}{
 		"peers":  safePeers,
-		"rumors": safeRumors,
 		"sender": gm.currentNode.ID,
 	}
+	gm.attachSettingsLocked(data)
 
 	return data
 }
@@ -321,151 +459,121 @@ func (gm *GossipManager) GetAliveNodes() []*PeerInfo {
 	return alive
 }
 
-// selfMaintenanceRoutine ensures the current node always sees itself as alive
-func (gm *GossipManager) selfMaintenanceRoutine() {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+// maintainSelf ensures the current node always sees itself as alive
+func (gm *GossipManager) maintainSelf() {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	if ourPeer, exists := gm.peers[gm.currentNode.ID]; exists {
+		// Ensure we always see ourselves as alive
+		if ourPeer.Status != "alive" {
+			fmt.Printf("🔧 Self-maintenance: Correcting own status from %s to alive\n", ourPeer.Status)
+			ourPeer.Status = "alive"
+			ourPeer.LastSeen = gm.clock.Now()
+		}
+	}
+}
+
+// joinSeeds asks every seed for its membership, going through the list again
+// with backoff until at least one seed has answered
+func (gm *GossipManager) joinSeeds(seeds []string) {
+	backoff := seedMinBackoff
+
+	for attempt := 1; ; attempt++ {
+		joined := 0
+		for _, address := range seeds {
+			seedID, err := gm.discoverSeed(address)
+			if err != nil {
+				fmt.Printf("❌ Seed discovery at %s failed: %v\n", address, err)
+				continue
+			}
+			fmt.Printf("✅ Joined the cluster through seed %s (%s)\n", seedID, address)
+			joined++
+		}
+		if joined > 0 {
+			return
+		}
 
-	for {
+		fmt.Printf("⏳ No seed node answered (attempt %d), retrying in %s\n", attempt, backoff)
 		select {
+		case <-gm.clock.After(backoff):
 		case <-gm.ctx.Done():
 			return
-		case <-ticker.C:
-			gm.mu.Lock()
-			if ourPeer, exists := gm.peers[gm.currentNode.ID]; exists {
-				// Ensure we always see ourselves as alive
-				if ourPeer.Status != "alive" {
-					fmt.Printf("🔧 Self-maintenance: Correcting own status from %s to alive\n", ourPeer.Status)
-					ourPeer.Status = "alive"
-					ourPeer.LastSeen = time.Now()
-				}
-			}
-			gm.mu.Unlock()
+		}
+
+		backoff *= 2
+		if backoff > seedMaxBackoff {
+			backoff = seedMaxBackoff
 		}
 	}
 }
 
-// performSeedNodeDiscovery actively discovers and exchanges state with a seed node
-func (gm *GossipManager) performSeedNodeDiscovery(seedNodeID, seedAddress string) {
-	fmt.Printf("🔍 Performing active discovery of seed node %s\n", seedNodeID)
-	
-	// Step 1: Send a discovery request to get the seed node's current state
+// discoverSeed sends a seed discovery request to address and merges the
+// answer: the seed's identity, its peer table and its rumors. It returns the
+// seed's node ID.
+func (gm *GossipManager) discoverSeed(address string) (string, error) {
+	fmt.Printf("🔍 Performing seed discovery at %s\n", address)
+
 	discoveryMessage := GossipMessage{
 		Type:      "seed_discovery",
 		FromNode:  gm.currentNode.ID,
-		ToNode:    seedNodeID,
-		Timestamp: time.Now().Unix(),
+		Timestamp: gm.clock.Now().Unix(),
 		Data: map[string]interface{}{
 			"requester_address": gm.currentNode.Address,
 			"discovery_id":      generateMessageID(),
+			"incarnation":       gm.selfIncarnation(),
 		},
 		MessageID: generateMessageID(),
 	}
-	
-	response, err := gm.sendDiscoveryRequest(seedAddress, &discoveryMessage)
-	if err != nil {
-		fmt.Printf("❌ Seed discovery failed for %s: %v\n", seedNodeID, err)
-		// Fallback to old behavior with zero incarnation
-		gm.addSeedNodeFallback(seedNodeID, seedAddress)
-		return
-	}
-	
-	// Step 2: Process the discovery response and add the seed node with correct info
-	gm.processSeedDiscoveryResponse(response)
-	
-	// Step 3: Send our own state to the seed node so it learns about us
-	gm.introduceToSeedNode(seedNodeID, seedAddress)
-}
 
-// sendDiscoveryRequest sends a discovery request and waits for response
-func (gm *GossipManager) sendDiscoveryRequest(address string, message *GossipMessage) (*GossipMessage, error) {
-	url := fmt.Sprintf("http://%s/gossip/receive", address)
-	
-	jsonData, err := json.Marshal(message)
-	if err != nil {
-		return nil, err
-	}
-	
-	// Use a longer timeout for discovery requests
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("discovery request failed with status %d", resp.StatusCode)
-	}
-	
-	// For now, we'll rely on the gossip protocol to exchange state through regular heartbeats
-	// The seed node will respond with its state in subsequent gossip messages
-	return nil, nil
-}
+	ctx, cancel := gm.clock.WithTimeout(gm.ctx, seedDiscoveryTimeout)
+	defer cancel()
 
-// processSeedDiscoveryResponse processes the response from seed node discovery
-func (gm *GossipManager) processSeedDiscoveryResponse(response *GossipMessage) {
-	if response == nil {
-		return
+	requester, ok := gm.transport.(Requester)
+	if !ok {
+		// The seed still admits us; its gossip will tell us who it is
+		if err := gm.transport.Send(ctx, address, &discoveryMessage); err != nil {
+			return "", err
+		}
+		return "(unknown)", nil
 	}
-	// Discovery response will be handled through regular gossip message processing
-}
 
-// introduceToSeedNode sends our state to the seed node
-func (gm *GossipManager) introduceToSeedNode(seedNodeID, seedAddress string) {
-	fmt.Printf("🤝 Introducing ourselves to seed node %s\n", seedNodeID)
-	
-	// Send a join message to introduce ourselves
-	joinMessage := GossipMessage{
-		Type:      "join",
-		FromNode:  gm.currentNode.ID,
-		ToNode:    seedNodeID,
-		Timestamp: time.Now().Unix(),
-		Data: map[string]interface{}{
-			"node_id": gm.currentNode.ID,
-			"address": gm.currentNode.Address,
-		},
-		MessageID: generateMessageID(),
-	}
-	
-	url := fmt.Sprintf("http://%s/gossip/receive", seedAddress)
-	jsonData, _ := json.Marshal(joinMessage)
-	
-	resp, err := gm.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	reply, err := requester.Request(ctx, address, &discoveryMessage)
 	if err != nil {
-		fmt.Printf("❌ Failed to introduce to seed node %s: %v\n", seedNodeID, err)
-		return
+		return "", err
+	}
+	if reply == nil || reply.FromNode == "" {
+		return "", fmt.Errorf("no discovery reply")
+	}
+	if reply.FromNode == gm.currentNode.ID {
+		return "", fmt.Errorf("%s is this node", address)
 	}
-	defer resp.Body.Close()
-	
-	fmt.Printf("✅ Successfully introduced to seed node %s\n", seedNodeID)
-}
 
-// addSeedNodeFallback adds a seed node with fallback behavior (zero incarnation)
-func (gm *GossipManager) addSeedNodeFallback(nodeID, address string) {
 	gm.mu.Lock()
 	defer gm.mu.Unlock()
-	
-	fmt.Printf("⚠️ Using fallback method for seed node %s\n", nodeID)
-	
-	gm.peers[nodeID] = &PeerInfo{
-		NodeID:       nodeID,
-		Address:      address,
-		Status:       "alive",
-		LastSeen:     time.Now(),
-		HeartbeatSeq: 0,
-		Incarnation:  0, // Will be updated when we receive gossip from this node
-	}
-	
-	// Trigger callback
-	if gm.onNodeJoin != nil {
-		fmt.Printf("🔄 Triggering join callback for seed node %s\n", nodeID)
-		gm.onNodeJoin(nodeID, address)
+
+	gm.receiveRumors(reply)
+	gm.receiveSettings(reply)
+	gm.handleHeartbeat(reply)
+
+	// The seed's table includes itself; make sure of it in case it didn't
+	if _, exists := gm.peers[reply.FromNode]; !exists {
+		incarnation, _ := dataInt64(reply.Data, "incarnation")
+		gm.putPeer(&PeerInfo{
+			NodeID:      reply.FromNode,
+			Address:     address,
+			Status:      "alive",
+			LastSeen:    gm.clock.Now(),
+			Incarnation: incarnation,
+		})
+		gm.detector.Heartbeat(reply.FromNode)
 	}
+
+	return reply.FromNode, nil
 }
 
 // generateMessageID generates a unique message ID
 func generateMessageID() string {
 	randNum, _ := rand.Int(rand.Reader, big.NewInt(1000000))
 	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), randNum.Int64())
-}
\ No newline at end of file
+}
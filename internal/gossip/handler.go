@@ -1,8 +1,6 @@
 package gossip
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -35,7 +33,7 @@ func (gh *GossipHandler) ReceiveGossip(c *gin.Context) {
 	}
 
 	// Process the gossip message
-	err := gh.gossipManager.HandleGossipMessage(&message)
+	reply, err := gh.gossipManager.HandleGossipRequest(&message)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to process gossip message",
@@ -44,18 +42,32 @@ func (gh *GossipHandler) ReceiveGossip(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"status": "success",
 		"message": "Gossip message processed",
-	})
+	}
+	if reply != nil {
+		response["reply"] = reply
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // GetClusterMembers returns current cluster membership
 func (gh *GossipHandler) GetClusterMembers(c *gin.Context) {
 	members := gh.gossipManager.GetClusterMembers()
+
+	// Each member with this node's failure detector phi for it
+	type memberView struct {
+		*PeerInfo
+		Phi float64 `json:"phi"`
+	}
+	views := make(map[string]memberView, len(members))
+	for nodeID, member := range members {
+		views[nodeID] = memberView{PeerInfo: member, Phi: gh.gossipManager.Phi(nodeID)}
+	}
 	
 	c.JSON(http.StatusOK, gin.H{
-		"cluster_members": members,
+		"cluster_members": views,
 		"total_members": len(members),
 		"alive_members": len(gh.gossipManager.GetAliveNodes()),
 	})
@@ -70,7 +82,11 @@ func (gh *GossipHandler) GetGossipStatus(c *gin.Context) {
 	suspectedCount := 0
 	deadCount := 0
 	
-	for _, peer := range gh.gossipManager.peers {
+	detector := make(map[string]interface{})
+	for nodeID, peer := range gh.gossipManager.peers {
+		if stats, tracked := gh.gossipManager.detector.Stats(nodeID); tracked {
+			detector[nodeID] = stats
+		}
 		switch peer.Status {
 		case "alive":
 			aliveCount++
@@ -90,18 +106,22 @@ func (gh *GossipHandler) GetGossipStatus(c *gin.Context) {
 			"dead_nodes": deadCount,
 			"active_rumors": len(gh.gossipManager.rumors),
 		},
+		"failure_detector": detector,
 		"config": gin.H{
 			"gossip_interval": gh.gossipManager.config.GossipInterval.String(),
 			"probe_interval": gh.gossipManager.config.ProbeInterval.String(),
-			"suspicion_timeout": gh.gossipManager.config.SuspicionTimeout.String(),
+			"phi_suspect": gh.gossipManager.detector.Config().SuspectPhi,
+			"phi_dead": gh.gossipManager.detector.Config().DeadPhi,
+			"push_pull_interval": gh.gossipManager.config.PushPullInterval.String(),
+			"packet_transport": gh.gossipManager.packets != nil,
 		},
 	})
 }
 
-// JoinCluster allows a node to explicitly join via gossip protocol
+// JoinCluster joins the cluster through the node at the given address, which
+// answers with its identity and membership
 func (gh *GossipHandler) JoinCluster(c *gin.Context) {
 	var req struct {
-		NodeID  string `json:"node_id" binding:"required"`
 		Address string `json:"address" binding:"required"`
 	}
 
@@ -110,35 +130,11 @@ func (gh *GossipHandler) JoinCluster(c *gin.Context) {
 		return
 	}
 
-	// Add the node via gossip protocol
-	gh.gossipManager.AddSeedNode(req.NodeID, req.Address)
-
-	// Send a join message to the new node
-	joinMessage := GossipMessage{
-		Type:      "join",
-		FromNode:  gh.gossipManager.currentNode.ID,
-		ToNode:    req.NodeID,
-		Timestamp: time.Now().Unix(),
-		Data: map[string]interface{}{
-			"node_id": gh.gossipManager.currentNode.ID,
-			"address": gh.gossipManager.currentNode.Address,
-		},
-		MessageID: generateMessageID(),
-	}
-
-	// Send the join message
-	go func() {
-		url := fmt.Sprintf("http://%s/gossip/receive", req.Address)
-		jsonData, _ := json.Marshal(joinMessage)
-		
-		resp, err := gh.gossipManager.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
-		if err == nil {
-			resp.Body.Close()
-		}
-	}()
+	// Seed discovery admits us there and tells us who else is in the cluster
+	gh.gossipManager.AddSeeds(req.Address)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("Node %s joining cluster via gossip", req.NodeID),
+		"message": fmt.Sprintf("Joining cluster via gossip through %s", req.Address),
 		"cluster_members": gh.gossipManager.GetClusterMembers(),
 	})
 }
@@ -167,15 +163,10 @@ func (gh *GossipHandler) LeaveCluster(c *gin.Context) {
 				MessageID: generateMessageID(),
 			}
 
-			go func(p *PeerInfo) {
-				url := fmt.Sprintf("http://%s/gossip/receive", p.Address)
-				jsonData, _ := json.Marshal(leaveMessage)
-				
-				resp, err := gh.gossipManager.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
-				if err == nil {
-					resp.Body.Close()
-				}
-			}(peer)
+			address := peer.Address
+			gh.gossipManager.clock.Go(func() {
+				gh.gossipManager.send(address, &leaveMessage, gh.gossipManager.config.ProbeTimeout)
+			})
 		}
 	}
 
@@ -185,11 +176,13 @@ func (gh *GossipHandler) LeaveCluster(c *gin.Context) {
 	})
 }
 
-// GetRumors returns current active rumors
+// GetRumors returns the rumors this node remembers and how far each has
+// been spread from here
 func (gh *GossipHandler) GetRumors(c *gin.Context) {
 	gh.gossipManager.mu.RLock()
 	defer gh.gossipManager.mu.RUnlock()
 
+	limit := gh.gossipManager.retransmitLimit()
 	rumors := make(map[string]interface{})
 	for id, rumor := range gh.gossipManager.rumors {
 		rumors[id] = gin.H{
@@ -198,6 +191,8 @@ func (gh *GossipHandler) GetRumors(c *gin.Context) {
 			"origin": rumor.Origin,
 			"timestamp": rumor.Timestamp,
 			"spread_count": rumor.SpreadCount,
+			"max_spread": limit,
+			"retired": rumor.SpreadCount >= limit,
 		}
 	}
 
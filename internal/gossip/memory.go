@@ -0,0 +1,76 @@
+package gossip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dynamodb/internal/transport"
+)
+
+// memoryService is the service name gossip managers register on an in-memory network
+const memoryService = "gossip"
+
+// RegisterMemory makes this manager reachable on an in-memory network under
+// its node's address
+func (gm *GossipManager) RegisterMemory(network *transport.Network) {
+	network.Register(gm.currentNode.Address, memoryService, gm)
+}
+
+// MemoryTransport delivers gossip to managers registered on an in-memory
+// network. Messages are copied through JSON like on the wire, so nodes never
+// share peer or rumor state.
+type MemoryTransport struct {
+	network *transport.Network
+	from    string
+}
+
+// NewMemoryTransport creates a gossip transport for the node at address
+func NewMemoryTransport(network *transport.Network, address string) *MemoryTransport {
+	return &MemoryTransport{network: network, from: address}
+}
+
+// Send hands the message to the manager at address
+func (t *MemoryTransport) Send(ctx context.Context, address string, message *GossipMessage) error {
+	_, err := t.Request(ctx, address, message)
+	return err
+}
+
+// Request hands the message to the manager at address and returns a copy of
+// its reply
+func (t *MemoryTransport) Request(ctx context.Context, address string, message *GossipMessage) (*GossipMessage, error) {
+	handler, err := t.network.Call(ctx, t.from, address, memoryService)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, ok := handler.(*GossipManager)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s handler at %s", memoryService, address)
+	}
+
+	copied, err := copyMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := manager.HandleGossipRequest(copied)
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	return copyMessage(reply)
+}
+
+// copyMessage copies a message through JSON, as the HTTP transport would
+func copyMessage(message *GossipMessage) (*GossipMessage, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s message: %v", message.Type, err)
+	}
+
+	var copied GossipMessage
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
@@ -0,0 +1,420 @@
+package gossip
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Packet layout. Every datagram starts with a version byte, its kind and a
+// sequence number that ties an ack to the message it answers. Strings are
+// length-prefixed and integers are varints, so a probe with a few rumors
+// takes a couple of hundred bytes.
+const (
+	packetVersion = 1
+
+	packetMessage = 1 // A gossip message; the receiver acks it
+	packetAck     = 2 // Carries the handler's error, empty if it succeeded
+)
+
+// messageTypeCodes are the one-byte codes for message types on the wire
+var messageTypeCodes = map[string]byte{
+	"heartbeat":               1,
+	"join":                    2,
+	"leave":                   3,
+	"probe":                   4,
+	"probe_response":          5,
+	"indirect_probe_request":  6,
+	"indirect_probe_response": 7,
+	"seed_discovery":          8,
+	"push_pull":               9,
+}
+
+// statusCodes are the one-byte codes for peer statuses on the wire
+var statusCodes = map[string]byte{
+	"alive":     1,
+	"suspected": 2,
+	"dead":      3,
+}
+
+// packetAckInfo is a decoded ack
+type packetAckInfo struct {
+	seq uint64
+	err string
+}
+
+// encodeMessagePacket encodes a message into at most maxSize bytes. The
+// sender's own entry, then rumors, then other peers are packed while they fit;
+// whatever doesn't fit is left for later rounds and push-pull sync. Other
+// payload entries travel as strings, as over gRPC. The IDs of the rumors that
+// fit are returned with the packet.
+func encodeMessagePacket(seq uint64, message *GossipMessage, maxSize int) ([]byte, []string, error) {
+	typeCode, ok := messageTypeCodes[message.Type]
+	if !ok {
+		return nil, nil, fmt.Errorf("message type %q can't be sent as a packet", message.Type)
+	}
+
+	buf := []byte{packetVersion, packetMessage}
+	buf = binary.AppendUvarint(buf, seq)
+	buf = append(buf, typeCode)
+	buf = appendString(buf, message.FromNode)
+	buf = appendString(buf, message.ToNode)
+	buf = appendString(buf, message.MessageID)
+	buf = binary.AppendVarint(buf, message.Timestamp)
+	buf = binary.AppendVarint(buf, int64(message.TTL))
+
+	var peers map[string]*PeerInfo
+	var rumors map[string]*Rumor
+	keys := make([]string, 0, len(message.Data))
+	for key, value := range message.Data {
+		switch typed := value.(type) {
+		case map[string]*PeerInfo:
+			peers = typed
+		case map[string]*Rumor:
+			rumors = typed
+		case nil:
+		default:
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	buf = binary.AppendUvarint(buf, uint64(len(keys)))
+	for _, key := range keys {
+		value, ok := message.Data[key].(string)
+		if !ok {
+			value = fmt.Sprint(message.Data[key])
+		}
+		buf = appendString(buf, key)
+		buf = appendString(buf, value)
+	}
+
+	// Two count varints follow; reserve their worst case
+	budget := maxSize - len(buf) - 2*binary.MaxVarintLen16
+	if budget < 0 {
+		return nil, nil, fmt.Errorf("%s message doesn't fit in a %d byte packet", message.Type, maxSize)
+	}
+
+	// The sender's own entry is its heartbeat, so it goes first
+	var packedPeers [][]byte
+	if self, ok := peers[message.FromNode]; ok {
+		packedPeers = packEntries(1, &budget, func(int) []byte { return appendPeer(nil, self) }, nil)
+	}
+
+	rumorIDs := make([]string, 0, len(rumors))
+	for rumorID := range rumors {
+		rumorIDs = append(rumorIDs, rumorID)
+	}
+	sort.Strings(rumorIDs)
+	var sentRumors []string
+	packedRumors := packEntries(len(rumorIDs), &budget, func(i int) []byte {
+		return appendRumor(nil, rumorIDs[i], rumors[rumorIDs[i]])
+	}, func(i int) { sentRumors = append(sentRumors, rumorIDs[i]) })
+
+	// Other peers rotate with map order, so each round carries different ones
+	peerIDs := make([]string, 0, len(peers))
+	for nodeID := range peers {
+		if nodeID != message.FromNode {
+			peerIDs = append(peerIDs, nodeID)
+		}
+	}
+	packedPeers = append(packedPeers, packEntries(len(peerIDs), &budget, func(i int) []byte {
+		return appendPeer(nil, peers[peerIDs[i]])
+	}, nil)...)
+
+	buf = binary.AppendUvarint(buf, uint64(len(packedPeers)))
+	for _, entry := range packedPeers {
+		buf = append(buf, entry...)
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(packedRumors)))
+	for _, entry := range packedRumors {
+		buf = append(buf, entry...)
+	}
+	return buf, sentRumors, nil
+}
+
+// rumorsThatFit returns message with only the rumors that fit in a maxSize
+// packet, so rumors left out aren't counted as sent. Messages that can't be
+// packed are returned as they are for the transport to reject.
+func rumorsThatFit(message *GossipMessage, maxSize int) *GossipMessage {
+	rumors, ok := message.Data["rumors"].(map[string]*Rumor)
+	if !ok {
+		return message
+	}
+	_, sent, err := encodeMessagePacket(0, message, maxSize)
+	if err != nil || len(sent) == len(rumors) {
+		return message
+	}
+
+	fitting := make(map[string]*Rumor, len(sent))
+	for _, rumorID := range sent {
+		fitting[rumorID] = rumors[rumorID]
+	}
+	data := make(map[string]interface{}, len(message.Data))
+	for key, value := range message.Data {
+		data[key] = value
+	}
+	if len(fitting) > 0 {
+		data["rumors"] = fitting
+	} else {
+		delete(data, "rumors")
+	}
+	trimmed := *message
+	trimmed.Data = data
+	return &trimmed
+}
+
+// packEntries encodes entries in order while they fit in budget, calling
+// packed (if set) with the index of each one that does
+func packEntries(count int, budget *int, encode func(i int) []byte, packed func(i int)) [][]byte {
+	var entries [][]byte
+	for i := 0; i < count; i++ {
+		entry := encode(i)
+		if len(entry) > *budget {
+			continue
+		}
+		*budget -= len(entry)
+		entries = append(entries, entry)
+		if packed != nil {
+			packed(i)
+		}
+	}
+	return entries
+}
+
+// encodeAckPacket encodes the answer to message seq
+func encodeAckPacket(seq uint64, handlerErr error) []byte {
+	buf := []byte{packetVersion, packetAck}
+	buf = binary.AppendUvarint(buf, seq)
+	if handlerErr != nil {
+		buf = appendString(buf, handlerErr.Error())
+	} else {
+		buf = appendString(buf, "")
+	}
+	return buf
+}
+
+// decodePacket decodes a datagram into either a message or an ack
+func decodePacket(data []byte) (seq uint64, message *GossipMessage, ack *packetAckInfo, err error) {
+	r := &packetReader{buf: data}
+	if version := r.byte(); r.err == nil && version != packetVersion {
+		return 0, nil, nil, fmt.Errorf("unsupported packet version %d", version)
+	}
+	kind := r.byte()
+	seq = r.uvarint()
+
+	switch kind {
+	case packetAck:
+		ack = &packetAckInfo{seq: seq, err: r.string()}
+	case packetMessage:
+		message = r.message()
+	default:
+		if r.err == nil {
+			r.err = fmt.Errorf("unknown packet kind %d", kind)
+		}
+	}
+
+	if r.err != nil {
+		return 0, nil, nil, r.err
+	}
+	return seq, message, ack, nil
+}
+
+func appendString(buf []byte, value string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+func appendPeer(buf []byte, peer *PeerInfo) []byte {
+	buf = appendString(buf, peer.NodeID)
+	buf = appendString(buf, peer.Address)
+	buf = append(buf, statusCodes[peer.Status])
+	buf = binary.AppendVarint(buf, peer.LastSeen.UnixNano())
+	buf = binary.AppendVarint(buf, peer.HeartbeatSeq)
+	return binary.AppendVarint(buf, peer.Incarnation)
+}
+
+func appendRumor(buf []byte, rumorID string, rumor *Rumor) []byte {
+	buf = appendString(buf, rumorID)
+	buf = appendString(buf, rumor.Type)
+	buf = appendString(buf, rumor.Origin)
+	buf = binary.AppendVarint(buf, rumor.Timestamp)
+	buf = binary.AppendUvarint(buf, uint64(rumor.SpreadCount))
+	buf = binary.AppendUvarint(buf, uint64(rumor.MaxSpread))
+
+	keys := make([]string, 0, len(rumor.Data))
+	for key, value := range rumor.Data {
+		if value != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	buf = binary.AppendUvarint(buf, uint64(len(keys)))
+	for _, key := range keys {
+		value, ok := rumor.Data[key].(string)
+		if !ok {
+			value = fmt.Sprint(rumor.Data[key])
+		}
+		buf = appendString(buf, key)
+		buf = appendString(buf, value)
+	}
+	return buf
+}
+
+var errShortPacket = errors.New("truncated packet")
+
+// packetReader decodes a packet; the first error sticks and later reads
+// return zero values
+type packetReader struct {
+	buf []byte
+	err error
+}
+
+func (r *packetReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) == 0 {
+		r.err = errShortPacket
+		return 0
+	}
+	value := r.buf[0]
+	r.buf = r.buf[1:]
+	return value
+}
+
+func (r *packetReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = errShortPacket
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return value
+}
+
+func (r *packetReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = errShortPacket
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return value
+}
+
+func (r *packetReader) string() string {
+	length := r.uvarint()
+	if r.err != nil {
+		return ""
+	}
+	if uint64(len(r.buf)) < length {
+		r.err = errShortPacket
+		return ""
+	}
+	value := string(r.buf[:length])
+	r.buf = r.buf[length:]
+	return value
+}
+
+// count reads an entry count, rejecting counts the rest of the packet
+// couldn't possibly hold
+func (r *packetReader) count() int {
+	count := r.uvarint()
+	if r.err == nil && count > uint64(len(r.buf)) {
+		r.err = errShortPacket
+		return 0
+	}
+	return int(count)
+}
+
+func (r *packetReader) message() *GossipMessage {
+	typeCode := r.byte()
+	message := &GossipMessage{
+		FromNode:  r.string(),
+		ToNode:    r.string(),
+		MessageID: r.string(),
+		Timestamp: r.varint(),
+		TTL:       int(r.varint()),
+		Data:      make(map[string]interface{}),
+	}
+	for name, code := range messageTypeCodes {
+		if code == typeCode {
+			message.Type = name
+		}
+	}
+	if message.Type == "" && r.err == nil {
+		r.err = fmt.Errorf("unknown message type code %d", typeCode)
+	}
+
+	for i, fields := 0, r.count(); i < fields && r.err == nil; i++ {
+		key := r.string()
+		message.Data[key] = r.string()
+	}
+
+	if peerCount := r.count(); peerCount > 0 {
+		peers := make(map[string]*PeerInfo, peerCount)
+		for i := 0; i < peerCount && r.err == nil; i++ {
+			peer := r.peer()
+			peers[peer.NodeID] = peer
+		}
+		message.Data["peers"] = peers
+	}
+
+	if rumorCount := r.count(); rumorCount > 0 {
+		rumors := make(map[string]*Rumor, rumorCount)
+		for i := 0; i < rumorCount && r.err == nil; i++ {
+			rumor := r.rumor()
+			rumors[rumor.ID] = rumor
+		}
+		message.Data["rumors"] = rumors
+	}
+
+	return message
+}
+
+func (r *packetReader) peer() *PeerInfo {
+	peer := &PeerInfo{
+		NodeID:  r.string(),
+		Address: r.string(),
+	}
+	statusCode := r.byte()
+	for status, code := range statusCodes {
+		if code == statusCode {
+			peer.Status = status
+		}
+	}
+	if peer.Status == "" && r.err == nil {
+		r.err = fmt.Errorf("unknown status code %d for %s", statusCode, peer.NodeID)
+	}
+	peer.LastSeen = time.Unix(0, r.varint())
+	peer.HeartbeatSeq = r.varint()
+	peer.Incarnation = r.varint()
+	return peer
+}
+
+func (r *packetReader) rumor() *Rumor {
+	rumor := &Rumor{
+		ID:          r.string(),
+		Type:        r.string(),
+		Origin:      r.string(),
+		Timestamp:   r.varint(),
+		SpreadCount: int(r.uvarint()),
+		MaxSpread:   int(r.uvarint()),
+		Data:        make(map[string]interface{}),
+	}
+	for i, fields := 0, r.count(); i < fields && r.err == nil; i++ {
+		key := r.string()
+		rumor.Data[key] = r.string()
+	}
+	return rumor
+}
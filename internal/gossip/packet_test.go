@@ -0,0 +1,229 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testPeer(nodeID, status string, incarnation int64) *PeerInfo {
+	return &PeerInfo{
+		NodeID:       nodeID,
+		Address:      "localhost:" + nodeID,
+		Status:       status,
+		LastSeen:     time.Unix(0, 1700000000000000000),
+		HeartbeatSeq: 42,
+		Incarnation:  incarnation,
+	}
+}
+
+func TestMessagePacketRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *GossipMessage
+	}{
+		{
+			name: "probe with fields",
+			message: &GossipMessage{
+				Type:      "probe",
+				FromNode:  "a",
+				ToNode:    "b",
+				Timestamp: 1700000000,
+				MessageID: "m1",
+				Data:      map[string]interface{}{"probe_id": "p1", "incarnation": "3"},
+			},
+		},
+		{
+			name: "heartbeat with peers",
+			message: &GossipMessage{
+				Type:      "heartbeat",
+				FromNode:  "a",
+				ToNode:    "b",
+				Timestamp: 1700000000,
+				MessageID: "m2",
+				Data: map[string]interface{}{
+					"sender": "a",
+					"peers": map[string]*PeerInfo{
+						"a": testPeer("a", "alive", 1),
+						"b": testPeer("b", "suspected", 0),
+						"c": testPeer("c", "dead", 7),
+					},
+				},
+			},
+		},
+		{
+			name: "rumors with a negative TTL",
+			message: &GossipMessage{
+				Type:      "indirect_probe_request",
+				FromNode:  "a",
+				TTL:       -1,
+				MessageID: "m3",
+				Data: map[string]interface{}{
+					"rumors": map[string]*Rumor{
+						"r1": {
+							ID:          "r1",
+							Type:        "node_failure",
+							Origin:      "c",
+							Timestamp:   1700000001,
+							SpreadCount: 2,
+							MaxSpread:   8,
+							Data:        map[string]interface{}{"node_id": "b", "incarnation": "4"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _, err := encodeMessagePacket(9, tt.message, DefaultPacketSize)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+
+			seq, decoded, ack, err := decodePacket(data)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if seq != 9 || ack != nil {
+				t.Errorf("seq = %d, ack = %+v, want 9 and no ack", seq, ack)
+			}
+			if !reflect.DeepEqual(decoded, tt.message) {
+				t.Errorf("decoded %+v, want %+v", decoded, tt.message)
+			}
+		})
+	}
+}
+
+func TestAckPacketRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		handlerErr error
+		want       string
+	}{
+		{name: "success", handlerErr: nil, want: ""},
+		{name: "handler error", handlerErr: errors.New("unknown message type: x"), want: "unknown message type: x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seq, message, ack, err := decodePacket(encodeAckPacket(300, tt.handlerErr))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if seq != 300 || message != nil || ack == nil || ack.seq != 300 || ack.err != tt.want {
+				t.Errorf("decoded seq %d, message %+v, ack %+v, want ack 300 %q", seq, message, ack, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessagePacketFitsMaxSize(t *testing.T) {
+	peers := make(map[string]*PeerInfo)
+	rumors := make(map[string]*Rumor)
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("node-%03d", i)
+		peers[id] = testPeer(id, "alive", int64(i))
+		rumors[id] = &Rumor{ID: id, Type: "node_join", Origin: id, Data: map[string]interface{}{"node_id": id}}
+	}
+	message := &GossipMessage{
+		Type:     "heartbeat",
+		FromNode: "node-100",
+		Data:     map[string]interface{}{"peers": peers, "rumors": rumors},
+	}
+
+	for _, maxSize := range []int{256, 512, DefaultPacketSize} {
+		t.Run(fmt.Sprint(maxSize), func(t *testing.T) {
+			data, _, err := encodeMessagePacket(1, message, maxSize)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			if len(data) > maxSize {
+				t.Errorf("packet is %d bytes, max %d", len(data), maxSize)
+			}
+
+			_, decoded, _, err := decodePacket(data)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			gotPeers, _ := decoded.Data["peers"].(map[string]*PeerInfo)
+			if _, ok := gotPeers["node-100"]; !ok {
+				t.Error("sender's own entry was dropped")
+			}
+			if len(gotPeers) >= len(peers) {
+				t.Errorf("%d peers fit in %d bytes, expected truncation", len(gotPeers), maxSize)
+			}
+			for id, peer := range gotPeers {
+				if !reflect.DeepEqual(peer, peers[id]) {
+					t.Errorf("peer %s decoded as %+v, want %+v", id, peer, peers[id])
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeMessagePacketErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *GossipMessage
+		maxSize int
+	}{
+		{
+			name:    "type without a packet code",
+			message: &GossipMessage{Type: "unknown"},
+			maxSize: DefaultPacketSize,
+		},
+		{
+			name:    "fields larger than the packet",
+			message: &GossipMessage{Type: "probe", Data: map[string]interface{}{"blob": string(make([]byte, 100))}},
+			maxSize: 64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := encodeMessagePacket(1, tt.message, tt.maxSize); err == nil {
+				t.Error("encode succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestDecodePacketRejectsDamagedPackets(t *testing.T) {
+	message := &GossipMessage{
+		Type:     "heartbeat",
+		FromNode: "a",
+		Data:     map[string]interface{}{"peers": map[string]*PeerInfo{"a": testPeer("a", "alive", 1)}},
+	}
+	valid, _, err := encodeMessagePacket(1, message, DefaultPacketSize)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	versioned := append([]byte(nil), valid...)
+	versioned[0] = packetVersion + 1
+	unknownKind := append([]byte(nil), valid...)
+	unknownKind[1] = 9
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "truncated", data: valid[:len(valid)-3]},
+		{name: "header only", data: valid[:3]},
+		{name: "newer version", data: versioned},
+		{name: "unknown kind", data: unknownKind},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := decodePacket(tt.data); err == nil {
+				t.Error("decode succeeded, want an error")
+			}
+		})
+	}
+}
@@ -1,11 +1,7 @@
 package gossip
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"time"
 )
 
 // performProbeRound performs failure detection probing
@@ -14,10 +10,10 @@ func (gm *GossipManager) performProbeRound() {
 	
 	// Select a random alive node to probe
 	var targetPeer *PeerInfo
-	for nodeID, peer := range gm.peers {
-		if nodeID != gm.currentNode.ID && peer.Status == "alive" {
+	for _, peer := range gm.sortedPeers() {
+		if peer.NodeID != gm.currentNode.ID && peer.Status == "alive" {
 			// Check if we haven't heard from this node recently
-			if time.Since(peer.LastSeen) > gm.config.ProbeInterval {
+			if gm.clock.Since(peer.LastSeen) > gm.config.ProbeInterval {
 				targetPeer = peer
 				break
 			}
@@ -27,7 +23,7 @@ func (gm *GossipManager) performProbeRound() {
 	gm.mu.RUnlock()
 
 	if targetPeer != nil {
-		go gm.probeNode(targetPeer)
+		gm.clock.Go(func() { gm.probeNode(targetPeer) })
 	}
 }
 
@@ -39,96 +35,71 @@ func (gm *GossipManager) probeNode(peer *PeerInfo) {
 		Type:      "probe",
 		FromNode:  gm.currentNode.ID,
 		ToNode:    peer.NodeID,
-		Timestamp: time.Now().Unix(),
+		Timestamp: gm.clock.Now().Unix(),
 		Data: map[string]interface{}{
 			"probe_id": generateMessageID(),
 		},
 		MessageID: generateMessageID(),
 	}
+	gm.attachRumors(message.Data)
 
-	url := fmt.Sprintf("http://%s/gossip/receive", peer.Address)
-	
-	jsonData, err := json.Marshal(message)
-	if err != nil {
-		fmt.Printf("❌ Failed to marshal probe message for %s: %v\n", peer.NodeID, err)
-		gm.handleProbeFailure(peer.NodeID)
-		return
-	}
-
-	resp, err := gm.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
+	if err := gm.sendPacket(peer.Address, &message, gm.config.ProbeTimeout); err != nil {
 		fmt.Printf("❌ Probe failed for %s: %v\n", peer.NodeID, err)
 		gm.handleProbeFailure(peer.NodeID)
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("❌ Probe to %s failed with status %d\n", peer.NodeID, resp.StatusCode)
-		gm.handleProbeFailure(peer.NodeID)
-		return
-	}
 
 	fmt.Printf("✅ Probe response received from %s\n", peer.NodeID)
 	
 	// Update last seen time for successful probe
 	gm.mu.Lock()
 	if peerInfo, exists := gm.peers[peer.NodeID]; exists {
-		peerInfo.LastSeen = time.Now()
+		gm.heard(peerInfo)
 		if peerInfo.Status == "suspected" {
-			peerInfo.Status = "alive"
+			gm.setStatus(peerInfo, "alive")
 			fmt.Printf("💚 Node %s recovered from suspicion\n", peer.NodeID)
 		}
 	}
 	gm.mu.Unlock()
 }
 
-// handleProbeMessage processes incoming probe messages
+// handleProbeMessage processes incoming probe messages. Like every message
+// handler it runs with gm.mu held by HandleGossipMessage.
 func (gm *GossipManager) handleProbeMessage(message *GossipMessage) error {
 	fmt.Printf("🔍 Received probe from %s\n", message.FromNode)
 
+	// Find the sender's address. Accepting the probe already answers it, so
+	// a sender we don't know yet (e.g. a helper probing on someone's behalf)
+	// just gets no separate response.
+	senderPeer, exists := gm.peers[message.FromNode]
+	if !exists {
+		return nil
+	}
+
 	// Send probe response
 	response := GossipMessage{
 		Type:      "probe_response",
 		FromNode:  gm.currentNode.ID,
 		ToNode:    message.FromNode,
-		Timestamp: time.Now().Unix(),
+		Timestamp: gm.clock.Now().Unix(),
 		Data: map[string]interface{}{
 			"probe_id":    message.Data["probe_id"],
 			"response_id": generateMessageID(),
 		},
 		MessageID: generateMessageID(),
 	}
+	gm.attachRumorsLocked(response.Data)
 
-	// Find the sender's address
-	gm.mu.RLock()
-	senderPeer, exists := gm.peers[message.FromNode]
-	gm.mu.RUnlock()
-
-	if !exists {
-		return fmt.Errorf("unknown sender: %s", message.FromNode)
-	}
-
-	go gm.sendProbeResponse(senderPeer, &response)
+	gm.clock.Go(func() { gm.sendProbeResponse(senderPeer, &response) })
 	return nil
 }
 
 // sendProbeResponse sends a probe response
 func (gm *GossipManager) sendProbeResponse(peer *PeerInfo, response *GossipMessage) {
-	url := fmt.Sprintf("http://%s/gossip/receive", peer.Address)
-	
-	jsonData, err := json.Marshal(response)
-	if err != nil {
-		fmt.Printf("❌ Failed to marshal probe response for %s: %v\n", peer.NodeID, err)
-		return
-	}
-
-	resp, err := gm.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
+	if err := gm.sendPacket(peer.Address, response, gm.config.ProbeTimeout); err != nil {
 		fmt.Printf("❌ Failed to send probe response to %s: %v\n", peer.NodeID, err)
 		return
 	}
-	defer resp.Body.Close()
 
 	fmt.Printf("📤 Probe response sent to %s\n", peer.NodeID)
 }
@@ -137,14 +108,11 @@ func (gm *GossipManager) sendProbeResponse(peer *PeerInfo, response *GossipMessa
 func (gm *GossipManager) handleProbeResponse(message *GossipMessage) error {
 	fmt.Printf("📨 Received probe response from %s\n", message.FromNode)
 
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
-
 	// Update the sender's status
 	if peer, exists := gm.peers[message.FromNode]; exists {
-		peer.LastSeen = time.Now()
+		gm.heard(peer)
 		if peer.Status == "suspected" {
-			peer.Status = "alive"
+			gm.setStatus(peer, "alive")
 			fmt.Printf("💚 Node %s recovered from suspicion via probe response\n", message.FromNode)
 		}
 	}
@@ -159,11 +127,11 @@ func (gm *GossipManager) handleProbeFailure(nodeID string) {
 
 	if peer, exists := gm.peers[nodeID]; exists {
 		if peer.Status == "alive" {
-			peer.Status = "suspected"
+			gm.setStatus(peer, "suspected")
 			fmt.Printf("🤔 Node %s marked as suspected due to probe failure\n", nodeID)
 			
 			// Start indirect probing before marking as dead
-			go gm.indirectProbe(nodeID)
+			gm.clock.Go(func() { gm.indirectProbe(nodeID) })
 		}
 	}
 }
@@ -176,8 +144,8 @@ func (gm *GossipManager) indirectProbe(targetNodeID string) {
 	
 	// Find other alive nodes to help with indirect probing
 	var helperNodes []*PeerInfo
-	for nodeID, peer := range gm.peers {
-		if nodeID != gm.currentNode.ID && nodeID != targetNodeID && peer.Status == "alive" {
+	for _, peer := range gm.sortedPeers() {
+		if peer.NodeID != gm.currentNode.ID && peer.NodeID != targetNodeID && peer.Status == "alive" {
 			helperNodes = append(helperNodes, peer)
 		}
 	}
@@ -186,8 +154,8 @@ func (gm *GossipManager) indirectProbe(targetNodeID string) {
 	gm.mu.RUnlock()
 
 	if len(helperNodes) == 0 || targetPeer == nil {
-		// No helper nodes available, proceed to suspicion timeout
-		go gm.handleSuspectedNode(targetNodeID)
+		// No helper nodes available; the node stays suspected until the
+		// failure detector declares it dead or it refutes the suspicion
 		return
 	}
 
@@ -200,11 +168,13 @@ func (gm *GossipManager) indirectProbe(targetNodeID string) {
 	}
 
 	for i := 0; i < maxHelpers; i++ {
-		go gm.requestIndirectProbe(helperNodes[i], targetPeer, successChan)
+		helper := helperNodes[i]
+		gm.clock.Go(func() { gm.requestIndirectProbe(helper, targetPeer, successChan) })
 	}
 
-	// Wait for responses or timeout
-	timeout := time.After(gm.config.ProbeTimeout * 2)
+	// Every request reports an outcome within its own timeout; this one only
+	// guards against a helper that never does
+	timeout := gm.clock.After(gm.config.ProbeTimeout * 3)
 	responses := 0
 
 	for responses < maxHelpers {
@@ -216,8 +186,8 @@ func (gm *GossipManager) indirectProbe(targetNodeID string) {
 				
 				gm.mu.Lock()
 				if peer, exists := gm.peers[targetNodeID]; exists && peer.Status == "suspected" {
-					peer.Status = "alive"
-					peer.LastSeen = time.Now()
+					gm.setStatus(peer, "alive")
+					gm.heard(peer)
 					fmt.Printf("💚 Node %s recovered via indirect probe\n", targetNodeID)
 				}
 				gm.mu.Unlock()
@@ -225,60 +195,146 @@ func (gm *GossipManager) indirectProbe(targetNodeID string) {
 			}
 		case <-timeout:
 			fmt.Printf("⏰ Indirect probe timeout for %s\n", targetNodeID)
-			go gm.handleSuspectedNode(targetNodeID)
 			return
 		}
 	}
 
-	// All indirect probes failed
+	// All indirect probes failed; it stays suspected for the failure
+	// detector to judge
 	fmt.Printf("❌ All indirect probes failed for %s\n", targetNodeID)
-	go gm.handleSuspectedNode(targetNodeID)
 }
 
-// requestIndirectProbe requests another node to probe a suspected node
+// requestIndirectProbe asks a helper to probe a suspected node and reports
+// whether the helper reached it. A helper that can't be reached, reports a
+// nack or doesn't answer in time counts as a failed path.
 func (gm *GossipManager) requestIndirectProbe(helper *PeerInfo, target *PeerInfo, result chan bool) {
+	requestID := generateMessageID()
+	response := make(chan bool, 1)
+
+	gm.mu.Lock()
+	gm.indirectProbes[requestID] = response
+	gm.mu.Unlock()
+
+	defer func() {
+		gm.mu.Lock()
+		delete(gm.indirectProbes, requestID)
+		gm.mu.Unlock()
+	}()
+
 	message := GossipMessage{
 		Type:      "indirect_probe_request",
 		FromNode:  gm.currentNode.ID,
 		ToNode:    helper.NodeID,
-		Timestamp: time.Now().Unix(),
+		Timestamp: gm.clock.Now().Unix(),
 		Data: map[string]interface{}{
 			"target_node_id": target.NodeID,
 			"target_address": target.Address,
-			"request_id":     generateMessageID(),
+			"request_id":     requestID,
 		},
 		MessageID: generateMessageID(),
 	}
+	gm.attachRumors(message.Data)
 
-	url := fmt.Sprintf("http://%s/gossip/receive", helper.Address)
-	
-	jsonData, err := json.Marshal(message)
-	if err != nil {
+	if err := gm.sendPacket(helper.Address, &message, gm.config.ProbeTimeout); err != nil {
+		fmt.Printf("❌ Indirect probe request to %s failed: %v\n", helper.NodeID, err)
 		result <- false
 		return
 	}
 
-	resp, err := gm.httpClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
+	// The helper probes with ProbeTimeout, so allow that plus the way back
+	select {
+	case ack := <-response:
+		if !ack {
+			fmt.Printf("❌ %s could not reach %s either\n", helper.NodeID, target.NodeID)
+		}
+		result <- ack
+	case <-gm.clock.After(gm.config.ProbeTimeout * 2):
+		fmt.Printf("⏰ No indirect probe result from %s for %s\n", helper.NodeID, target.NodeID)
+		result <- false
+	case <-gm.ctx.Done():
 		result <- false
-		return
 	}
-	defer resp.Body.Close()
-
-	result <- resp.StatusCode == http.StatusOK
 }
 
-// cleanupOldRumors removes old rumors that have been spread enough
-func (gm *GossipManager) cleanupOldRumors() {
-	gm.mu.Lock()
-	defer gm.mu.Unlock()
+// handleIndirectProbeRequest probes a target on behalf of the requester and
+// reports the outcome back
+func (gm *GossipManager) handleIndirectProbeRequest(message *GossipMessage) error {
+	requestID, _ := message.Data["request_id"].(string)
+	targetNodeID, _ := message.Data["target_node_id"].(string)
+	targetAddress, _ := message.Data["target_address"].(string)
+	if requestID == "" || targetNodeID == "" || targetAddress == "" {
+		return fmt.Errorf("indirect probe request from %s is missing request_id, target_node_id or target_address", message.FromNode)
+	}
+
+	requester, exists := gm.peers[message.FromNode]
+	if !exists {
+		return fmt.Errorf("unknown sender: %s", message.FromNode)
+	}
+	requesterAddress := requester.Address
+
+	fmt.Printf("🔄 Probing %s on behalf of %s\n", targetNodeID, message.FromNode)
+
+	gm.clock.Go(func() {
+		alive := targetNodeID == gm.currentNode.ID || gm.pingNode(targetNodeID, targetAddress)
 
-	now := time.Now().Unix()
-	for rumorID, rumor := range gm.rumors {
-		// Remove rumors older than 5 minutes or that have been spread enough
-		if now-rumor.Timestamp > 300 || rumor.SpreadCount >= rumor.MaxSpread {
-			delete(gm.rumors, rumorID)
-			fmt.Printf("🧹 Cleaned up old rumor: %s\n", rumorID)
+		result := "nack"
+		if alive {
+			result = "ack"
 		}
+		response := GossipMessage{
+			Type:      "indirect_probe_response",
+			FromNode:  gm.currentNode.ID,
+			ToNode:    message.FromNode,
+			Timestamp: gm.clock.Now().Unix(),
+			Data: map[string]interface{}{
+				"request_id":     requestID,
+				"target_node_id": targetNodeID,
+				"result":         result,
+			},
+			MessageID: generateMessageID(),
+		}
+		gm.attachRumors(response.Data)
+
+		if err := gm.sendPacket(requesterAddress, &response, gm.config.ProbeTimeout); err != nil {
+			fmt.Printf("❌ Failed to report indirect probe of %s to %s: %v\n", targetNodeID, message.FromNode, err)
+		}
+	})
+
+	return nil
+}
+
+// handleIndirectProbeResponse hands a helper's outcome to the waiting request
+func (gm *GossipManager) handleIndirectProbeResponse(message *GossipMessage) error {
+	requestID, _ := message.Data["request_id"].(string)
+	result, _ := message.Data["result"].(string)
+
+	response, pending := gm.indirectProbes[requestID]
+	if !pending {
+		// Already timed out
+		return nil
 	}
-}
\ No newline at end of file
+
+	fmt.Printf("📨 Indirect probe of %v via %s: %s\n", message.Data["target_node_id"], message.FromNode, result)
+	select {
+	case response <- result == "ack":
+	default:
+	}
+	return nil
+}
+
+// pingNode sends a probe to a node and reports whether it was accepted
+func (gm *GossipManager) pingNode(nodeID, address string) bool {
+	message := GossipMessage{
+		Type:      "probe",
+		FromNode:  gm.currentNode.ID,
+		ToNode:    nodeID,
+		Timestamp: gm.clock.Now().Unix(),
+		Data: map[string]interface{}{
+			"probe_id": generateMessageID(),
+		},
+		MessageID: generateMessageID(),
+	}
+	gm.attachRumors(message.Data)
+
+	return gm.sendPacket(address, &message, gm.config.ProbeTimeout) == nil
+}
@@ -0,0 +1,156 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/node"
+	"dynamodb/internal/transport"
+)
+
+func newTestManager(t *testing.T, id string, network *transport.Network, clk *clock.Simulated) *GossipManager {
+	t.Helper()
+
+	config := DefaultGossipConfig()
+	config.Clock = clk
+	config.Seed = 1
+	gm := NewGossipManager(node.NewNode(id, id+":9000"), config, NewMemoryTransport(network, id+":9000"))
+	gm.RegisterMemory(network)
+	t.Cleanup(gm.Stop)
+	return gm
+}
+
+// join has gm admit a node the way a join message would
+func join(t *testing.T, gm *GossipManager, nodeID string, incarnation int64) {
+	t.Helper()
+
+	_, err := gm.HandleGossipRequest(&GossipMessage{
+		Type:     "join",
+		FromNode: nodeID,
+		Data:     map[string]interface{}{"address": nodeID + ":9000", "incarnation": incarnation},
+	})
+	if err != nil {
+		t.Fatalf("join %s: %v", nodeID, err)
+	}
+}
+
+func peerStatus(gm *GossipManager, nodeID string) string {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+
+	if peer, exists := gm.peers[nodeID]; exists {
+		return peer.Status
+	}
+	return ""
+}
+
+// newProbeCluster starts a manager per ID on one network, each knowing all
+// the others as alive
+func newProbeCluster(t *testing.T, clk *clock.Simulated, network *transport.Network, ids ...string) map[string]*GossipManager {
+	t.Helper()
+
+	managers := make(map[string]*GossipManager)
+	for _, id := range ids {
+		managers[id] = newTestManager(t, id, network, clk)
+	}
+	for _, id := range ids {
+		for _, other := range ids {
+			if other != id {
+				join(t, managers[id], other, 1)
+			}
+		}
+	}
+	return managers
+}
+
+// probe has gm probe nodeID directly, falling back to indirect probes, and
+// runs the clock until every probe has given up
+func probe(gm *GossipManager, clk *clock.Simulated, nodeID string) {
+	gm.mu.RLock()
+	peer := gm.peers[nodeID]
+	gm.mu.RUnlock()
+
+	clk.Go(func() { gm.probeNode(peer) })
+	for i := 0; i < 30; i++ {
+		clk.Advance(100 * time.Millisecond)
+	}
+}
+
+func TestIndirectProbe(t *testing.T) {
+	tests := []struct {
+		name   string
+		ids    []string
+		cut    func(network *transport.Network)
+		status string
+	}{
+		{
+			name:   "a helper reaches the target",
+			ids:    []string{"a", "b", "c"},
+			cut:    func(n *transport.Network) { n.Block("a:9000", "c:9000") },
+			status: "alive",
+		},
+		{
+			name:   "helpers can't reach the target either",
+			ids:    []string{"a", "b", "c", "d"},
+			cut:    func(n *transport.Network) { n.Partition([]string{"a:9000", "b:9000", "d:9000"}, []string{"c:9000"}) },
+			status: "suspected",
+		},
+		{
+			name:   "the target is down",
+			ids:    []string{"a", "b", "c"},
+			cut:    func(n *transport.Network) { n.Unregister("c:9000") },
+			status: "suspected",
+		},
+		{
+			name: "helpers are unreachable",
+			ids:  []string{"a", "b", "c"},
+			cut: func(n *transport.Network) {
+				n.Block("a:9000", "c:9000")
+				n.Block("a:9000", "b:9000")
+			},
+			status: "suspected",
+		},
+		{
+			name:   "no helpers",
+			ids:    []string{"a", "c"},
+			cut:    func(n *transport.Network) { n.Block("a:9000", "c:9000") },
+			status: "suspected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewSimulated(time.Unix(1000, 0))
+			network := transport.NewNetwork(1)
+			network.SetClock(clk)
+			managers := newProbeCluster(t, clk, network, tt.ids...)
+			tt.cut(network)
+
+			a := managers["a"]
+			probe(a, clk, "c")
+			if status := peerStatus(a, "c"); status != tt.status {
+				t.Errorf("c is %s after the probe, want %s", status, tt.status)
+			}
+
+			a.mu.RLock()
+			pending := len(a.indirectProbes)
+			a.mu.RUnlock()
+			if pending != 0 {
+				t.Errorf("%d indirect probe requests still pending", pending)
+			}
+		})
+	}
+}
+
+func TestDirectProbeKeepsReachablePeerAlive(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(1000, 0))
+	network := transport.NewNetwork(1)
+	network.SetClock(clk)
+	managers := newProbeCluster(t, clk, network, "a", "b")
+
+	probe(managers["a"], clk, "b")
+	if status := peerStatus(managers["a"], "b"); status != "alive" {
+		t.Errorf("b is %s after answering a probe, want alive", status)
+	}
+}
@@ -0,0 +1,199 @@
+package gossip
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dynamodb/internal/rpc"
+	"dynamodb/internal/rpc/internodepb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegisterRPC exposes the gossip service on a gRPC server
+func (gm *GossipManager) RegisterRPC(server *grpc.Server) {
+	internodepb.RegisterGossipServer(server, &gossipServer{manager: gm})
+}
+
+// gossipServer serves the Gossip gRPC service
+type gossipServer struct {
+	internodepb.UnimplementedGossipServer
+	manager *GossipManager
+}
+
+func (s *gossipServer) Exchange(ctx context.Context, message *internodepb.GossipMessage) (*internodepb.GossipAck, error) {
+	reply, err := s.manager.HandleGossipRequest(messageFromProto(message))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to process gossip message: %v", err)
+	}
+
+	ack := &internodepb.GossipAck{NodeId: s.manager.currentNode.ID}
+	if reply != nil {
+		ack.Reply = messageToProto(reply)
+	}
+	return ack, nil
+}
+
+// GRPCTransport sends gossip to the peers' Gossip gRPC service
+type GRPCTransport struct {
+	client *rpc.Client
+}
+
+// NewGRPCTransport creates a gossip transport on a gRPC connection pool
+func NewGRPCTransport(client *rpc.Client) *GRPCTransport {
+	return &GRPCTransport{client: client}
+}
+
+// Send delivers the message to the node at address over gRPC
+func (t *GRPCTransport) Send(ctx context.Context, address string, message *GossipMessage) error {
+	conn, err := t.client.Conn(address)
+	if err != nil {
+		return err
+	}
+
+	_, err = internodepb.NewGossipClient(conn).Exchange(ctx, messageToProto(message))
+	return err
+}
+
+// Request delivers the message to the node at address over gRPC and returns
+// the reply carried in its ack
+func (t *GRPCTransport) Request(ctx context.Context, address string, message *GossipMessage) (*GossipMessage, error) {
+	conn, err := t.client.Conn(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ack, err := internodepb.NewGossipClient(conn).Exchange(ctx, messageToProto(message))
+	if err != nil {
+		return nil, err
+	}
+	if ack.Reply == nil {
+		return nil, nil
+	}
+	return messageFromProto(ack.Reply), nil
+}
+
+// messageToProto converts a gossip message to its wire form. Peer and rumor
+// maps become typed fields; every other payload entry is a string field.
+func messageToProto(message *GossipMessage) *internodepb.GossipMessage {
+	result := &internodepb.GossipMessage{
+		Type:      message.Type,
+		FromNode:  message.FromNode,
+		ToNode:    message.ToNode,
+		Timestamp: message.Timestamp,
+		Ttl:       int32(message.TTL),
+		MessageId: message.MessageID,
+		Fields:    make(map[string]string),
+	}
+
+	for key, value := range message.Data {
+		switch typed := value.(type) {
+		case map[string]*PeerInfo:
+			result.Peers = make(map[string]*internodepb.PeerInfo, len(typed))
+			for nodeID, peer := range typed {
+				result.Peers[nodeID] = peerToProto(peer)
+			}
+		case map[string]*Rumor:
+			result.Rumors = make(map[string]*internodepb.Rumor, len(typed))
+			for rumorID, rumor := range typed {
+				result.Rumors[rumorID] = rumorToProto(rumor)
+			}
+		case string:
+			result.Fields[key] = typed
+		case nil:
+		default:
+			result.Fields[key] = fmt.Sprint(typed)
+		}
+	}
+
+	return result
+}
+
+// messageFromProto converts a wire gossip message back to the form the
+// message handlers expect
+func messageFromProto(message *internodepb.GossipMessage) *GossipMessage {
+	data := make(map[string]interface{}, len(message.Fields)+2)
+	for key, value := range message.Fields {
+		data[key] = value
+	}
+
+	if len(message.Peers) > 0 {
+		peers := make(map[string]*PeerInfo, len(message.Peers))
+		for nodeID, peer := range message.Peers {
+			peers[nodeID] = &PeerInfo{
+				NodeID:       peer.NodeId,
+				Address:      peer.Address,
+				Status:       peer.Status,
+				LastSeen:     time.Unix(0, peer.LastSeenUnixNano),
+				HeartbeatSeq: peer.HeartbeatSeq,
+				Incarnation:  peer.Incarnation,
+			}
+		}
+		data["peers"] = peers
+	}
+
+	if len(message.Rumors) > 0 {
+		rumors := make(map[string]*Rumor, len(message.Rumors))
+		for rumorID, rumor := range message.Rumors {
+			rumorData := make(map[string]interface{}, len(rumor.Data))
+			for key, value := range rumor.Data {
+				rumorData[key] = value
+			}
+			rumors[rumorID] = &Rumor{
+				ID:          rumor.Id,
+				Type:        rumor.Type,
+				Data:        rumorData,
+				Timestamp:   rumor.Timestamp,
+				Origin:      rumor.Origin,
+				SpreadCount: int(rumor.SpreadCount),
+				MaxSpread:   int(rumor.MaxSpread),
+			}
+		}
+		data["rumors"] = rumors
+	}
+
+	return &GossipMessage{
+		Type:      message.Type,
+		FromNode:  message.FromNode,
+		ToNode:    message.ToNode,
+		Timestamp: message.Timestamp,
+		Data:      data,
+		TTL:       int(message.Ttl),
+		MessageID: message.MessageId,
+	}
+}
+
+func peerToProto(peer *PeerInfo) *internodepb.PeerInfo {
+	return &internodepb.PeerInfo{
+		NodeId:           peer.NodeID,
+		Address:          peer.Address,
+		Status:           peer.Status,
+		LastSeenUnixNano: peer.LastSeen.UnixNano(),
+		HeartbeatSeq:     peer.HeartbeatSeq,
+		Incarnation:      peer.Incarnation,
+	}
+}
+
+func rumorToProto(rumor *Rumor) *internodepb.Rumor {
+	data := make(map[string]string, len(rumor.Data))
+	for key, value := range rumor.Data {
+		if text, ok := value.(string); ok {
+			data[key] = text
+		} else if value != nil {
+			data[key] = fmt.Sprint(value)
+		}
+	}
+
+	return &internodepb.Rumor{
+		Id:          rumor.ID,
+		Type:        rumor.Type,
+		Data:        data,
+		Timestamp:   rumor.Timestamp,
+		Origin:      rumor.Origin,
+		SpreadCount: int32(rumor.SpreadCount),
+		MaxSpread:   int32(rumor.MaxSpread),
+	}
+}
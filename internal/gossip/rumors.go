@@ -0,0 +1,133 @@
+package gossip
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// retransmitLimit is how many times each node sends a rumor before retiring
+// it: λ·log(N), which reaches every node with high probability
+func (gm *GossipManager) retransmitLimit() int {
+	members := 0
+	for _, peer := range gm.peers {
+		if peer.Status != "dead" {
+			members++
+		}
+	}
+
+	limit := gm.config.RumorRetransmitMult * int(math.Ceil(math.Log10(float64(members+1))))
+	if limit < 1 {
+		limit = 1
+	}
+	return limit
+}
+
+// piggybackRumors picks the rumors to carry on an outgoing message: up to
+// RumorsPerMessage of the least sent ones that haven't been retired. Picking
+// isn't sending: countTransmits counts the ones that went out. Callers hold
+// gm.mu.
+func (gm *GossipManager) piggybackRumors() map[string]*Rumor {
+	limit := gm.retransmitLimit()
+
+	active := make([]*Rumor, 0, len(gm.rumors))
+	for _, rumor := range gm.rumors {
+		if rumor.SpreadCount < limit {
+			active = append(active, rumor)
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	// Fresh rumors first; ties broken by ID so the choice is reproducible
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].SpreadCount != active[j].SpreadCount {
+			return active[i].SpreadCount < active[j].SpreadCount
+		}
+		return active[i].ID < active[j].ID
+	})
+	if len(active) > gm.config.RumorsPerMessage {
+		active = active[:gm.config.RumorsPerMessage]
+	}
+
+	selected := make(map[string]*Rumor, len(active))
+	for _, rumor := range active {
+		rumorCopy := *rumor
+		selected[rumor.ID] = &rumorCopy
+	}
+	return selected
+}
+
+// countTransmits counts a transmission of each rumor piggybacked on a message
+// that was sent, retiring those that reach the retransmit limit
+func (gm *GossipManager) countTransmits(message *GossipMessage) {
+	sent, ok := message.Data["rumors"].(map[string]*Rumor)
+	if !ok || len(sent) == 0 {
+		return
+	}
+
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	limit := gm.retransmitLimit()
+	for _, rumorID := range sortedKeys(sent) {
+		rumor, ok := gm.rumors[rumorID]
+		if !ok || rumor.SpreadCount >= limit {
+			continue
+		}
+		rumor.SpreadCount++
+		rumor.MaxSpread = limit
+		if rumor.SpreadCount == limit {
+			fmt.Printf("🏁 Rumor %s retired after %d transmissions\n", rumor.ID, limit)
+		}
+	}
+}
+
+// attachRumors adds piggybacked rumors to an outgoing message's payload
+func (gm *GossipManager) attachRumors(data map[string]interface{}) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	gm.attachRumorsLocked(data)
+}
+
+// attachRumorsLocked is attachRumors for callers that hold gm.mu
+func (gm *GossipManager) attachRumorsLocked(data map[string]interface{}) {
+	if rumors := gm.piggybackRumors(); len(rumors) > 0 {
+		data["rumors"] = rumors
+	}
+}
+
+// receiveRumors merges the rumors piggybacked on an incoming message
+// (decoded JSON over HTTP, typed structs over gRPC) in ID order. Callers hold
+// gm.mu.
+func (gm *GossipManager) receiveRumors(message *GossipMessage) {
+	switch rumors := message.Data["rumors"].(type) {
+	case map[string]interface{}:
+		for _, rumorID := range sortedKeys(rumors) {
+			gm.processRumor(rumorID, rumors[rumorID])
+		}
+	case map[string]*Rumor:
+		for _, rumorID := range sortedKeys(rumors) {
+			gm.mergeRumor(rumorID, rumors[rumorID])
+		}
+	}
+}
+
+// cleanupOldRumors forgets rumors older than RumorRetention. Retired rumors
+// are kept until then so copies still arriving from other nodes aren't
+// taken for new ones.
+func (gm *GossipManager) cleanupOldRumors() {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	now := gm.clock.Now().Unix()
+	retention := int64(gm.config.RumorRetention.Seconds())
+	for rumorID, rumor := range gm.rumors {
+		if now-rumor.Timestamp > retention {
+			delete(gm.rumors, rumorID)
+			fmt.Printf("🧹 Cleaned up old rumor: %s\n", rumorID)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package gossip
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/transport"
+)
+
+// sizedTransport is a packet transport limited to size byte packets
+type sizedTransport struct {
+	*MemoryTransport
+	size int
+}
+
+func (t *sizedTransport) PacketSize() int {
+	return t.size
+}
+
+func rumorCount(gm *GossipManager) int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return len(gm.rumors)
+}
+
+// spreadCounts returns how many times gm has sent each of its rumors
+func spreadCounts(gm *GossipManager) map[string]int {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+
+	counts := make(map[string]int, len(gm.rumors))
+	for rumorID, rumor := range gm.rumors {
+		counts[rumorID] = rumor.SpreadCount
+	}
+	return counts
+}
+
+func TestRumorRetiredAfterRetransmitLimit(t *testing.T) {
+	tests := []struct {
+		peers int // Besides the node itself
+		mult  int
+		want  int // mult·ceil(log10(members+1))
+	}{
+		{peers: 2, mult: 4, want: 4},
+		{peers: 9, mult: 4, want: 8},
+		{peers: 9, mult: 1, want: 2},
+		{peers: 99, mult: 3, want: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d peers, mult %d", tt.peers, tt.mult), func(t *testing.T) {
+			clk := clock.NewSimulated(time.Unix(1000, 0))
+			gm := newTestManager(t, "a", transport.NewNetwork(1), clk)
+			gm.config.RumorRetransmitMult = tt.mult
+			for i := 0; i < tt.peers; i++ {
+				join(t, gm, fmt.Sprintf("peer-%02d", i), 1)
+			}
+
+			// Only the rumor under test competes for space on messages
+			gm.mu.Lock()
+			gm.rumors = make(map[string]*Rumor)
+			gm.spreadRumor("node_failure", map[string]interface{}{"node_id": "peer-00"})
+			gm.mu.Unlock()
+
+			sends := 0
+			for i := 0; i < 2*tt.want; i++ {
+				message := &GossipMessage{Type: "heartbeat", Data: map[string]interface{}{}}
+				gm.attachRumors(message.Data)
+				if rumors, ok := message.Data["rumors"].(map[string]*Rumor); ok {
+					sends += len(rumors)
+				}
+				gm.countTransmits(message)
+			}
+			if sends != tt.want {
+				t.Errorf("rumor sent %d times, want %d", sends, tt.want)
+			}
+			if got := rumorCount(gm); got != 1 {
+				t.Errorf("retired rumor forgotten before RumorRetention: %d rumors left", got)
+			}
+		})
+	}
+}
+
+func TestRumorTransmitCountedOnlyWhenSent(t *testing.T) {
+	tests := []struct {
+		name string
+		send func(t *testing.T, gm *GossipManager, network *transport.Network) // Sends a's rumors somewhere
+		sent int                                                               // How many rumors count a transmission
+	}{
+		{
+			name: "delivered",
+			send: func(t *testing.T, gm *GossipManager, network *transport.Network) {
+				sendRumors(t, gm, "b:9000")
+			},
+			sent: 3,
+		},
+		{
+			name: "peer unreachable",
+			send: func(t *testing.T, gm *GossipManager, network *transport.Network) {
+				network.Unregister("b:9000")
+				sendRumors(t, gm, "b:9000")
+			},
+		},
+		{
+			name: "left out for packet size",
+			send: func(t *testing.T, gm *GossipManager, network *transport.Network) {
+				gm.SetPacketTransport(&sizedTransport{NewMemoryTransport(network, "a:9000"), 300})
+				sendRumors(t, gm, "b:9000")
+			},
+			sent: 1,
+		},
+		{
+			name: "probe from an unknown sender",
+			send: func(t *testing.T, gm *GossipManager, network *transport.Network) {
+				gm.mu.Lock()
+				defer gm.mu.Unlock()
+				if err := gm.handleProbeMessage(&GossipMessage{Type: "probe", FromNode: "stranger"}); err != nil {
+					t.Fatalf("probe: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewSimulated(time.Unix(1000, 0))
+			network := transport.NewNetwork(1)
+			gm := newTestManager(t, "a", network, clk)
+			newTestManager(t, "b", network, clk)
+			join(t, gm, "b", 1)
+
+			// Three rumors of about 100 bytes each; one fits in 300
+			gm.mu.Lock()
+			gm.rumors = make(map[string]*Rumor)
+			gm.mu.Unlock()
+			for i := 0; i < 3; i++ {
+				gm.mu.Lock()
+				gm.spreadRumor("node_failure", map[string]interface{}{"node_id": fmt.Sprintf("peer-%02d-%080d", i, 0)})
+				gm.mu.Unlock()
+				clk.Advance(time.Millisecond) // Rumor IDs carry the time
+			}
+			if got := rumorCount(gm); got != 3 {
+				t.Fatalf("%d rumors to send, want 3", got)
+			}
+
+			clk.Go(func() { tt.send(t, gm, network) })
+			clk.Settle()
+
+			sent := 0
+			for rumorID, count := range spreadCounts(gm) {
+				if count > 1 {
+					t.Errorf("rumor %s counted %d transmissions on one message", rumorID, count)
+				}
+				sent += count
+			}
+			if sent != tt.sent {
+				t.Errorf("%d rumor transmissions counted, want %d", sent, tt.sent)
+			}
+		})
+	}
+}
+
+// sendRumors sends gm's rumors to address on a heartbeat
+func sendRumors(t *testing.T, gm *GossipManager, address string) {
+	t.Helper()
+
+	message := &GossipMessage{Type: "heartbeat", FromNode: "a", Data: map[string]interface{}{}}
+	gm.attachRumors(message.Data)
+	gm.sendPacket(address, message, time.Second)
+}
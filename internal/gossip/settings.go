@@ -0,0 +1,198 @@
+package gossip
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// settingsKey is the payload entry that carries cluster-wide settings. It
+// travels as one JSON string, like other scalar payload entries over gRPC.
+const settingsKey = "settings"
+
+// Setting is a cluster-wide configuration entry spread by gossip, such as a
+// namespace's replication override. Every node keeps the entry with the
+// highest version, so all nodes converge on the same value.
+type Setting struct {
+	Value   string `json:"value"`
+	Version int64  `json:"version"` // Writer's clock in Unix nanoseconds
+	Origin  string `json:"origin"`  // Writing node; breaks version ties
+}
+
+// newerThan reports whether s replaces other
+func (s *Setting) newerThan(other *Setting) bool {
+	if s.Version != other.Version {
+		return s.Version > other.Version
+	}
+	return s.Origin > other.Origin
+}
+
+// settingChange is a setting change waiting to be handed to the watchers
+type settingChange struct {
+	key   string
+	value string
+}
+
+// SetSetting stores a cluster-wide setting and starts spreading it. Watchers
+// on this node are called before it returns.
+func (gm *GossipManager) SetSetting(key, value string) {
+	gm.mu.Lock()
+	setting := &Setting{
+		Value:   value,
+		Version: gm.clock.Now().UnixNano(),
+		Origin:  gm.currentNode.ID,
+	}
+	// Never go back behind a version we already hold, even with a skewed clock
+	if existing, exists := gm.settings[key]; exists && !setting.newerThan(existing) {
+		setting.Version = existing.Version + 1
+	}
+	gm.mergeSettingLocked(key, setting)
+	gm.mu.Unlock()
+
+	fmt.Printf("⚙️ Cluster setting %s set to %s\n", key, value)
+	gm.deliverSettings()
+
+	// Don't wait for the next round to start spreading it
+	gm.clock.Go(gm.performPushPull)
+}
+
+// WatchSettings calls fn with every setting this node holds and from then on
+// with every setting that changes, whether set here or learned from a peer.
+// Watchers are called one at a time, in the order the changes happened and
+// without the gossip lock held, so fn may call back into the manager.
+func (gm *GossipManager) WatchSettings(fn func(key, value string)) {
+	gm.settingsDelivery.Lock()
+	defer gm.settingsDelivery.Unlock()
+
+	// Changes queued before fn subscribed go to the earlier watchers only;
+	// fn starts from the settings they led to
+	gm.mu.Lock()
+	pending := gm.takePendingSettingsLocked()
+	current := make([]settingChange, 0, len(gm.settings))
+	for _, key := range sortedKeys(gm.settings) {
+		current = append(current, settingChange{key: key, value: gm.settings[key].Value})
+	}
+	gm.mu.Unlock()
+
+	gm.notifySettingWatchers(pending)
+	gm.settingWatchers = append(gm.settingWatchers, fn)
+	for _, change := range current {
+		fn(change.key, change.value)
+	}
+}
+
+// GetSettings returns a copy of the cluster-wide settings this node holds
+func (gm *GossipManager) GetSettings() map[string]Setting {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+
+	settings := make(map[string]Setting, len(gm.settings))
+	for key, setting := range gm.settings {
+		settings[key] = *setting
+	}
+	return settings
+}
+
+// attachSettingsLocked adds every setting to an outgoing payload. Callers
+// hold gm.mu.
+func (gm *GossipManager) attachSettingsLocked(data map[string]interface{}) {
+	if len(gm.settings) == 0 {
+		return
+	}
+	encoded, err := json.Marshal(gm.settings)
+	if err != nil {
+		return
+	}
+	data[settingsKey] = string(encoded)
+}
+
+// receiveSettings merges the settings a message carries and has the
+// watchers told about the ones that changed. Callers hold gm.mu.
+func (gm *GossipManager) receiveSettings(message *GossipMessage) {
+	encoded, ok := message.Data[settingsKey].(string)
+	if !ok || encoded == "" {
+		return
+	}
+
+	var settings map[string]*Setting
+	if err := json.Unmarshal([]byte(encoded), &settings); err != nil {
+		fmt.Printf("⚠️ Ignoring unreadable settings from %s: %v\n", message.FromNode, err)
+		return
+	}
+
+	changed := false
+	for _, key := range sortedKeys(settings) {
+		if settings[key] != nil && gm.mergeSettingLocked(key, settings[key]) {
+			changed = true
+		}
+	}
+	if changed {
+		gm.clock.Go(gm.deliverSettings)
+	}
+}
+
+// mergeSettingLocked keeps setting if it is newer than ours and queues it
+// for the watchers. It reports whether the setting was kept. Callers hold
+// gm.mu.
+func (gm *GossipManager) mergeSettingLocked(key string, setting *Setting) bool {
+	if existing, exists := gm.settings[key]; exists && !setting.newerThan(existing) {
+		return false
+	}
+
+	settingCopy := *setting
+	gm.settings[key] = &settingCopy
+	if setting.Origin != gm.currentNode.ID {
+		fmt.Printf("⚙️ Cluster setting %s from %s: %s\n", key, setting.Origin, setting.Value)
+	}
+
+	gm.pendingSettings = append(gm.pendingSettings, settingChange{key: key, value: setting.Value})
+	return true
+}
+
+// takePendingSettingsLocked returns the queued setting changes and clears
+// the queue. Callers hold gm.mu and settingsDelivery.
+func (gm *GossipManager) takePendingSettingsLocked() []settingChange {
+	pending := gm.pendingSettings
+	gm.pendingSettings = nil
+	return pending
+}
+
+// deliverSettings hands the queued setting changes to the watchers. Callers
+// must not hold gm.mu.
+func (gm *GossipManager) deliverSettings() {
+	gm.settingsDelivery.Lock()
+	defer gm.settingsDelivery.Unlock()
+
+	gm.mu.Lock()
+	pending := gm.takePendingSettingsLocked()
+	gm.mu.Unlock()
+
+	gm.notifySettingWatchers(pending)
+}
+
+// notifySettingWatchers calls every watcher with each change in order.
+// Callers hold settingsDelivery.
+func (gm *GossipManager) notifySettingWatchers(changes []settingChange) {
+	for _, change := range changes {
+		for _, fn := range gm.settingWatchers {
+			fn(change.key, change.value)
+		}
+	}
+}
+
+// withoutSettings returns message without its settings, for transports that
+// only carry what fits in a packet; push-pull sync delivers them instead
+func withoutSettings(message *GossipMessage) *GossipMessage {
+	if _, ok := message.Data[settingsKey]; !ok {
+		return message
+	}
+
+	data := make(map[string]interface{}, len(message.Data))
+	for key, value := range message.Data {
+		if key != settingsKey {
+			data[key] = value
+		}
+	}
+	stripped := *message
+	stripped.Data = data
+	return &stripped
+}
@@ -0,0 +1,83 @@
+package gossip
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/transport"
+)
+
+func settingsMessage(t *testing.T, from string, settings map[string]*Setting) *GossipMessage {
+	t.Helper()
+
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatalf("marshal settings: %v", err)
+	}
+	return &GossipMessage{
+		Type:     "heartbeat",
+		FromNode: from,
+		Data:     map[string]interface{}{settingsKey: string(encoded)},
+	}
+}
+
+func TestSettingWatchersRunOutsideTheLock(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(1000, 0))
+	gm := newTestManager(t, "a", transport.NewNetwork(1), clk)
+
+	// A watcher reading the settings back would deadlock under gm.mu
+	var seen []string
+	gm.WatchSettings(func(key, value string) {
+		seen = append(seen, key+"="+gm.GetSettings()[key].Value)
+	})
+
+	gm.SetSetting("namespace/user", "one")
+	if want := []string{"namespace/user=one"}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("after SetSetting watcher saw %v, want %v", seen, want)
+	}
+
+	// Settings learned from a peer reach the watchers in key order once the
+	// message has been handled
+	message := settingsMessage(t, "b", map[string]*Setting{
+		"namespace/user":  {Value: "two", Version: clk.Now().UnixNano() + 1, Origin: "b"},
+		"namespace/order": {Value: "three", Version: 1, Origin: "b"},
+		"namespace/stale": nil,
+	})
+	if _, err := gm.HandleGossipRequest(message); err != nil {
+		t.Fatalf("HandleGossipRequest: %v", err)
+	}
+	clk.Settle()
+
+	want := []string{"namespace/user=one", "namespace/order=three", "namespace/user=two"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("watcher saw %v, want %v", seen, want)
+	}
+}
+
+func TestWatchSettingsStartsFromCurrentSettings(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(1000, 0))
+	gm := newTestManager(t, "a", transport.NewNetwork(1), clk)
+
+	var first []string
+	gm.WatchSettings(func(key, value string) { first = append(first, key+"="+value) })
+
+	// Learned but not delivered yet when the second watcher subscribes
+	message := settingsMessage(t, "b", map[string]*Setting{
+		"x": {Value: "1", Version: 1, Origin: "b"},
+	})
+	if _, err := gm.HandleGossipRequest(message); err != nil {
+		t.Fatalf("HandleGossipRequest: %v", err)
+	}
+
+	var second []string
+	gm.WatchSettings(func(key, value string) { second = append(second, key+"="+value) })
+	clk.Settle()
+
+	// Each watcher hears about x exactly once
+	if want := []string{"x=1"}; !reflect.DeepEqual(first, want) || !reflect.DeepEqual(second, want) {
+		t.Errorf("watchers saw %v and %v, want %v each", first, second, want)
+	}
+}
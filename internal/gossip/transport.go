@@ -0,0 +1,93 @@
+package gossip
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"dynamodb/internal/transport"
+)
+
+// Transport delivers gossip messages to other nodes, identified by their
+// address. Every call is bounded by ctx.
+type Transport interface {
+	Send(ctx context.Context, address string, message *GossipMessage) error
+}
+
+// Requester is a Transport that also carries the receiver's reply back, for
+// requests such as seed_discovery. The reply is nil if the receiver had
+// none.
+type Requester interface {
+	Transport
+	Request(ctx context.Context, address string, message *GossipMessage) (*GossipMessage, error)
+}
+
+// PacketSizer is a Transport that sends each message in one packet of at
+// most PacketSize bytes, leaving out what doesn't fit
+type PacketSizer interface {
+	Transport
+	PacketSize() int
+}
+
+// HTTPTransport posts gossip messages as JSON to /gossip/receive
+type HTTPTransport struct {
+	client *transport.Client
+}
+
+// NewHTTPTransport creates a gossip transport on the shared HTTP client
+func NewHTTPTransport(client *transport.Client) *HTTPTransport {
+	if client == nil {
+		client = transport.NewClient(nil)
+	}
+	return &HTTPTransport{client: client}
+}
+
+// Send posts the message to the node at address
+func (t *HTTPTransport) Send(ctx context.Context, address string, message *GossipMessage) error {
+	resp, err := t.post(ctx, address, message)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Request posts the message to the node at address and returns the reply
+// from the response body
+func (t *HTTPTransport) Request(ctx context.Context, address string, message *GossipMessage) (*GossipMessage, error) {
+	resp, err := t.post(ctx, address, message)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Reply *GossipMessage `json:"reply"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode reply to %s: %v", message.Type, err)
+	}
+	return body.Reply, nil
+}
+
+// post sends the message and checks the status; callers close the body
+func (t *HTTPTransport) post(ctx context.Context, address string, message *GossipMessage) (*http.Response, error) {
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s message: %v", message.Type, err)
+	}
+
+	url := fmt.Sprintf("http://%s/gossip/receive", address)
+	resp, err := t.client.Post(ctx, url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
@@ -0,0 +1,208 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/fault"
+)
+
+// DefaultPacketSize keeps datagrams under a typical 1500 byte Ethernet MTU
+// once IP and UDP headers are added
+const DefaultPacketSize = 1400
+
+// UDPFaultPath is the path fault rules match gossip packets by. Rules on
+// /gossip cover both packets and the HTTP gossip endpoints.
+const UDPFaultPath = "/gossip/udp"
+
+// UDPTransport sends gossip messages as single datagrams and waits for the
+// receiver's ack, so a successful Send still means the message was handled.
+// It listens on the node's HTTP port number, over UDP.
+type UDPTransport struct {
+	conn       *net.UDPConn
+	packetSize int
+	faults     *fault.Injector
+	clock      clock.Clock // Runs handlers and fault delays
+
+	mu      sync.Mutex
+	nextSeq uint64
+	pending map[uint64]chan string
+}
+
+// ListenUDP opens a gossip packet transport on address. Incoming messages are
+// handled and fault delays timed on clk (nil = wall clock).
+func ListenUDP(address string, packetSize int, clk clock.Clock) (*UDPTransport, error) {
+	udpAddress, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", udpAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if packetSize <= 0 {
+		packetSize = DefaultPacketSize
+	}
+	return &UDPTransport{
+		conn:       conn,
+		packetSize: packetSize,
+		clock:      clock.OrReal(clk),
+		pending:    make(map[uint64]chan string),
+	}, nil
+}
+
+// SetFaults applies the injector's rules to packets, matched by UDPFaultPath
+func (t *UDPTransport) SetFaults(faults *fault.Injector) {
+	t.faults = faults
+}
+
+// Serve reads packets until the transport is closed, handing messages to
+// handle and delivering acks to the Sends waiting for them
+func (t *UDPTransport) Serve(handle func(*GossipMessage) error) {
+	buf := make([]byte, 64*1024)
+	for {
+		n, from, err := t.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			fmt.Printf("⚠️ Gossip packet read failed: %v\n", err)
+			continue
+		}
+
+		seq, message, ack, err := decodePacket(buf[:n])
+		if err != nil {
+			fmt.Printf("⚠️ Dropping malformed gossip packet from %s: %v\n", from, err)
+			continue
+		}
+
+		if ack != nil {
+			t.deliverAck(ack)
+			continue
+		}
+		t.clock.Go(func() { t.handleMessage(seq, message, from, handle) })
+	}
+}
+
+// handleMessage handles one incoming message and acks it
+func (t *UDPTransport) handleMessage(seq uint64, message *GossipMessage, from *net.UDPAddr, handle func(*GossipMessage) error) {
+	copies := 1
+	if t.faults != nil {
+		for _, rule := range t.faults.Inbound(UDPFaultPath, message.FromNode) {
+			switch rule.Action {
+			case fault.ActionDelay:
+				t.clock.Sleep(rule.Delay())
+			case fault.ActionDrop:
+				return
+			case fault.ActionError:
+				t.reply(encodeAckPacket(seq, fmt.Errorf("fault injected (%s)", rule.ID)), from)
+				return
+			case fault.ActionDuplicate:
+				copies = 2
+			}
+		}
+	}
+
+	var err error
+	for i := 0; i < copies; i++ {
+		err = handle(message)
+	}
+	t.reply(encodeAckPacket(seq, err), from)
+}
+
+func (t *UDPTransport) reply(packet []byte, to *net.UDPAddr) {
+	if _, err := t.conn.WriteToUDP(packet, to); err != nil {
+		fmt.Printf("⚠️ Failed to ack gossip packet from %s: %v\n", to, err)
+	}
+}
+
+func (t *UDPTransport) deliverAck(ack *packetAckInfo) {
+	t.mu.Lock()
+	waiting, ok := t.pending[ack.seq]
+	t.mu.Unlock()
+
+	if ok {
+		select {
+		case waiting <- ack.err:
+		default:
+		}
+	}
+}
+
+// Send delivers the message to the node at address and waits for its ack.
+// A lost packet or ack surfaces as ctx expiring.
+func (t *UDPTransport) Send(ctx context.Context, address string, message *GossipMessage) error {
+	udpAddress, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.nextSeq++
+	seq := t.nextSeq
+	waiting := make(chan string, 1)
+	t.pending[seq] = waiting
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, seq)
+		t.mu.Unlock()
+	}()
+
+	packet, _, err := encodeMessagePacket(seq, message, t.packetSize)
+	if err != nil {
+		return err
+	}
+
+	copies := 1
+	if t.faults != nil {
+		for _, rule := range t.faults.Outbound(UDPFaultPath, address) {
+			switch rule.Action {
+			case fault.ActionDelay:
+				select {
+				case <-t.clock.After(rule.Delay()):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			case fault.ActionDrop:
+				return fmt.Errorf("fault injection: packet to %s dropped (%s)", address, rule.ID)
+			case fault.ActionError:
+				return fmt.Errorf("fault injection: packet to %s failed (%s)", address, rule.ID)
+			case fault.ActionDuplicate:
+				copies = 2
+			}
+		}
+	}
+
+	for i := 0; i < copies; i++ {
+		if _, err := t.conn.WriteToUDP(packet, udpAddress); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case handlerErr := <-waiting:
+		if handlerErr != "" {
+			return fmt.Errorf("%s rejected %s message: %s", address, message.Type, handlerErr)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("no ack from %s: %v", address, ctx.Err())
+	}
+}
+
+// PacketSize is the largest datagram the transport sends
+func (t *UDPTransport) PacketSize() int {
+	return t.packetSize
+}
+
+// Close stops Serve; pending Sends give up when their contexts expire
+func (t *UDPTransport) Close() error {
+	return t.conn.Close()
+}
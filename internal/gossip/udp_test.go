@@ -0,0 +1,82 @@
+package gossip
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/fault"
+)
+
+// listenUDP opens a packet transport on a free local port and serves it
+// with handle
+func listenUDP(t *testing.T, clk clock.Clock, handle func(*GossipMessage) error) *UDPTransport {
+	t.Helper()
+
+	udp, err := ListenUDP("127.0.0.1:0", DefaultPacketSize, clk)
+	if err != nil {
+		t.Fatalf("ListenUDP: %v", err)
+	}
+	t.Cleanup(func() { udp.Close() })
+	go udp.Serve(handle)
+	return udp
+}
+
+// settleUntil settles clk until done reports true, giving packets in flight
+// on the loopback interface up to wait of wall time to arrive
+func settleUntil(clk *clock.Simulated, wait time.Duration, done func() bool) bool {
+	for deadline := time.Now().Add(wait); time.Now().Before(deadline); {
+		clk.Settle()
+		if done() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func TestUDPFaultDelaysRunOnTheClock(t *testing.T) {
+	for _, direction := range []string{fault.DirectionInbound, fault.DirectionOutbound} {
+		t.Run(direction, func(t *testing.T) {
+			clk := clock.NewSimulated(time.Unix(1000, 0))
+			faults := fault.NewInjector(1, clk)
+			if _, err := faults.AddRule(fault.Rule{Path: UDPFaultPath, Direction: direction,
+				Action: fault.ActionDelay, DelayMs: 500, Percent: 100}); err != nil {
+				t.Fatalf("AddRule: %v", err)
+			}
+
+			var handled atomic.Int32
+			sender := listenUDP(t, clk, func(*GossipMessage) error { return nil })
+			receiver := listenUDP(t, clk, func(*GossipMessage) error {
+				handled.Add(1)
+				return nil
+			})
+			sender.SetFaults(faults)
+			receiver.SetFaults(faults)
+
+			var sent atomic.Bool
+			clk.Go(func() {
+				ctx, cancel := clk.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := sender.Send(ctx, receiver.conn.LocalAddr().String(), &GossipMessage{Type: "probe", FromNode: "a"}); err != nil {
+					t.Errorf("Send: %v", err)
+				}
+				sent.Store(true)
+			})
+
+			// Nothing gets through while the clock stands still, however
+			// much wall time passes
+			settleUntil(clk, 100*time.Millisecond, func() bool { return false })
+			if handled.Load() != 0 || sent.Load() {
+				t.Fatalf("message handled %d times, send done %t before the delay passed", handled.Load(), sent.Load())
+			}
+
+			clk.Advance(500 * time.Millisecond)
+			if !settleUntil(clk, time.Second, func() bool { return sent.Load() }) || handled.Load() != 1 {
+				t.Errorf("message handled %d times, send done %t after the delay", handled.Load(), sent.Load())
+			}
+		})
+	}
+}
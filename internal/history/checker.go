@@ -0,0 +1,374 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+
+	"dynamodb/internal/storage"
+)
+
+// Consistency models a history can be checked against
+const (
+	ModelLinearizable  = "linearizable"     // Per-key linearizable register
+	ModelReadYourWrite = "read-your-writes" // A client sees its own acknowledged writes and deletes or later versions
+	ModelMonotonicRead = "monotonic-reads"  // A client never sees a key go back to a causally older version
+	ModelNoLostWrites  = "no-lost-writes"   // Acknowledged writes and deletes are still there at the end
+)
+
+// AllModels lists every model, strongest first
+func AllModels() []string {
+	return []string{ModelLinearizable, ModelReadYourWrite, ModelMonotonicRead, ModelNoLostWrites}
+}
+
+// ParseModels parses a comma separated list of models ("all" for every model)
+func ParseModels(list string) ([]string, error) {
+	if list == "" || list == "all" {
+		return AllModels(), nil
+	}
+
+	known := make(map[string]bool)
+	for _, model := range AllModels() {
+		known[model] = true
+	}
+
+	models := make([]string, 0)
+	for _, model := range strings.Split(list, ",") {
+		model = strings.TrimSpace(model)
+		if !known[model] {
+			return nil, fmt.Errorf("unknown model %q (expected one of %s)", model, strings.Join(AllModels(), ", "))
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// Anomaly is one violation of a model, with the smallest sub-history that
+// still shows it
+type Anomaly struct {
+	Model       string       `json:"model"`
+	Key         string       `json:"key"`
+	Client      int          `json:"client,omitempty"`
+	Description string       `json:"description"`
+	Operations  []*Operation `json:"operations"`
+}
+
+// ModelResult is the outcome of checking one model
+type ModelResult struct {
+	Model     string    `json:"model"`
+	Valid     bool      `json:"valid"`
+	Anomalies []Anomaly `json:"anomalies,omitempty"`
+	// Keys the checker gave up on (search too large)
+	Undecided []string `json:"undecided,omitempty"`
+}
+
+// Report is the outcome of checking a history
+type Report struct {
+	Operations int            `json:"operations"`
+	Keys       int            `json:"keys"`
+	Valid      bool           `json:"valid"`
+	Models     []*ModelResult `json:"models"`
+}
+
+// Check checks the history against each model
+func Check(h *History, models []string) *Report {
+	report := &Report{
+		Operations: len(h.Operations),
+		Keys:       len(h.Keys()),
+		Valid:      true,
+	}
+
+	for _, model := range models {
+		var result *ModelResult
+		switch model {
+		case ModelLinearizable:
+			result = checkLinearizable(h)
+		case ModelReadYourWrite:
+			result = checkReadYourWrites(h)
+		case ModelMonotonicRead:
+			result = checkMonotonicReads(h)
+		case ModelNoLostWrites:
+			result = checkNoLostWrites(h)
+		default:
+			continue
+		}
+
+		result.Valid = len(result.Anomalies) == 0
+		report.Valid = report.Valid && result.Valid
+		report.Models = append(report.Models, result)
+	}
+	return report
+}
+
+// writeIndex maps each written value of a key to its write. Workloads write
+// unique values, so a read's value identifies the write it came from.
+type writeIndex map[string]*Operation
+
+func indexWrites(ops []*Operation) writeIndex {
+	index := make(writeIndex)
+	for _, op := range ops {
+		if op.Kind == KindWrite {
+			index[op.Value] = op
+		}
+	}
+	return index
+}
+
+// covers reports whether the version with clock later is the one with clock
+// earlier or causally follows it
+func covers(later, earlier *storage.VectorClock) bool {
+	switch earlier.Compare(later) {
+	case storage.Before, storage.Equal:
+		return true
+	}
+	return false
+}
+
+// observedClocks returns the clocks of the versions a read returned: the
+// winner's (or the tombstone's) and those of the writes behind its siblings
+func observedClocks(read *Operation, writes writeIndex) []*storage.VectorClock {
+	clocks := make([]*storage.VectorClock, 0, len(read.Values))
+	if read.VectorClock != nil {
+		clocks = append(clocks, read.VectorClock)
+	}
+	for _, value := range read.Values {
+		if source, known := writes[value]; known && source.VectorClock != nil {
+			clocks = append(clocks, source.VectorClock)
+		}
+	}
+	return clocks
+}
+
+// reflects reports whether read observed mutation or a version that causally
+// follows it. A write that lost to a concurrent one under last-write-wins is
+// not reflected by reads of the winner: the store discarded it.
+//
+// Histories recorded without clocks fall back to real time, see
+// reflectsInRealTime.
+func reflects(read, mutation *Operation, writes writeIndex) bool {
+	if mutation.Kind == KindWrite && read.Found {
+		for _, value := range read.Values {
+			if value == mutation.Value {
+				return true
+			}
+		}
+	}
+
+	clocks := observedClocks(read, writes)
+	if mutation.VectorClock == nil || len(clocks) == 0 {
+		return reflectsInRealTime(read, mutation, writes)
+	}
+	for _, clock := range clocks {
+		if covers(clock, mutation.VectorClock) {
+			return true
+		}
+	}
+	return false
+}
+
+// reflectsInRealTime is reflects without clocks: a read that found nothing
+// reflects a delete, and a read that found a value reflects any mutation the
+// value's write doesn't precede
+func reflectsInRealTime(read, mutation *Operation, writes writeIndex) bool {
+	if !read.Found {
+		return mutation.Kind == KindDelete
+	}
+	for _, value := range read.Values {
+		if source, known := writes[value]; known && !source.precedes(mutation) {
+			return true
+		}
+	}
+	return false
+}
+
+// goesBack reports whether a later read returned an older state than an
+// earlier one: the version it returned causally precedes the earlier read's,
+// or the key is gone without a tombstone. Without clocks, every value the
+// later read saw was written before the write of some value the earlier read
+// saw was even invoked.
+func goesBack(earlier, later *Operation, writes writeIndex) bool {
+	if earlier.VectorClock != nil {
+		if later.VectorClock != nil {
+			return later.VectorClock.Compare(earlier.VectorClock) == storage.Before
+		}
+		if !later.Found {
+			return true
+		}
+	}
+
+	if !earlier.Found {
+		return false
+	}
+	if !later.Found {
+		return true
+	}
+
+	for _, laterValue := range later.Values {
+		laterWrite, known := writes[laterValue]
+		if !known {
+			return false
+		}
+		older := false
+		for _, earlierValue := range earlier.Values {
+			if earlierWrite, known := writes[earlierValue]; known && laterWrite.precedes(earlierWrite) {
+				older = true
+				break
+			}
+		}
+		if !older {
+			return false
+		}
+	}
+	return true
+}
+
+// checkReadYourWrites: once a client's write or delete is acknowledged, the
+// client's later reads of that key reflect it
+func checkReadYourWrites(h *History) *ModelResult {
+	result := &ModelResult{Model: ModelReadYourWrite}
+
+	for _, key := range h.Keys() {
+		ops := h.ForKey(key)
+		writes := indexWrites(ops)
+
+		lastWrite := make(map[int]*Operation) // client -> latest acknowledged write or delete
+		for _, op := range byCompletion(ops) {
+			if op.Status != StatusOK || op.Final {
+				continue
+			}
+
+			if op.Mutates() {
+				lastWrite[op.Client] = op
+				continue
+			}
+
+			write := lastWrite[op.Client]
+			if write == nil || !write.precedes(op) || reflects(op, write, writes) {
+				continue
+			}
+			result.Anomalies = append(result.Anomalies, Anomaly{
+				Model:       ModelReadYourWrite,
+				Key:         key,
+				Client:      op.Client,
+				Description: fmt.Sprintf("client %d had its %s acknowledged but then read %s", op.Client, describeMutation(write), describeRead(op)),
+				Operations:  withSources([]*Operation{write, op}, writes),
+			})
+		}
+	}
+	return result
+}
+
+// checkMonotonicReads: a client's successive reads of a key never go back
+func checkMonotonicReads(h *History) *ModelResult {
+	result := &ModelResult{Model: ModelMonotonicRead}
+
+	for _, key := range h.Keys() {
+		ops := h.ForKey(key)
+		writes := indexWrites(ops)
+
+		lastRead := make(map[int]*Operation) // client -> latest completed read
+		for _, op := range byCompletion(ops) {
+			if op.Kind != KindRead || op.Status != StatusOK || op.Final {
+				continue
+			}
+
+			previous := lastRead[op.Client]
+			lastRead[op.Client] = op
+			if previous == nil || !previous.precedes(op) || !goesBack(previous, op, writes) {
+				continue
+			}
+
+			result.Anomalies = append(result.Anomalies, Anomaly{
+				Model:       ModelMonotonicRead,
+				Key:         key,
+				Client:      op.Client,
+				Description: fmt.Sprintf("client %d read %s then %s", op.Client, describeRead(previous), describeRead(op)),
+				Operations:  withSources([]*Operation{previous, op}, writes),
+			})
+		}
+	}
+	return result
+}
+
+// checkNoLostWrites: every acknowledged write and delete is reflected by at
+// least one of the final reads of its key
+func checkNoLostWrites(h *History) *ModelResult {
+	result := &ModelResult{Model: ModelNoLostWrites}
+
+	for _, key := range h.Keys() {
+		ops := h.ForKey(key)
+		writes := indexWrites(ops)
+
+		finals := make([]*Operation, 0)
+		for _, op := range ops {
+			if op.Final && op.Kind == KindRead && op.Status == StatusOK {
+				finals = append(finals, op)
+			}
+		}
+		if len(finals) == 0 {
+			continue
+		}
+
+		for _, write := range ops {
+			if !write.Mutates() || write.Status != StatusOK {
+				continue
+			}
+
+			seen := false
+			for _, read := range finals {
+				if reflects(read, write, writes) {
+					seen = true
+					break
+				}
+			}
+			if seen {
+				continue
+			}
+
+			result.Anomalies = append(result.Anomalies, Anomaly{
+				Model:       ModelNoLostWrites,
+				Key:         key,
+				Client:      write.Client,
+				Description: fmt.Sprintf("acknowledged %s is not reflected by any of %d final reads", describeMutation(write), len(finals)),
+				Operations:  withSources(append([]*Operation{write}, finals...), writes),
+			})
+		}
+	}
+	return result
+}
+
+// withSources adds the writes that produced the values the reads among ops
+// returned, so an anomaly shows where those values came from
+func withSources(ops []*Operation, writes writeIndex) []*Operation {
+	included := make(map[int]bool)
+	for _, op := range ops {
+		included[op.ID] = true
+	}
+
+	result := append([]*Operation(nil), ops...)
+	for _, op := range ops {
+		if op.Kind != KindRead {
+			continue
+		}
+		for _, value := range op.Values {
+			if source, known := writes[value]; known && !included[source.ID] {
+				included[source.ID] = true
+				result = append(result, source)
+			}
+		}
+	}
+	return sortByInvoke(result)
+}
+
+func describeMutation(op *Operation) string {
+	if op.Kind == KindDelete {
+		return "delete of " + op.Key
+	}
+	return fmt.Sprintf("write %s=%s", op.Key, op.Value)
+}
+
+func describeRead(op *Operation) string {
+	if !op.Found {
+		return "not found"
+	}
+	return strings.Join(op.Values, "|")
+}
@@ -0,0 +1,252 @@
+package history
+
+import (
+	"testing"
+
+	"dynamodb/internal/storage"
+)
+
+// write, del and read build operations on key "k". Times are [invoke, complete];
+// complete < 0 leaves a write's outcome unknown.
+func write(id, client int, value string, invoke, complete int64) *Operation {
+	op := &Operation{ID: id, Client: client, Kind: KindWrite, Key: "k", Value: value, Invoke: invoke, Complete: complete, Status: StatusOK}
+	if complete < 0 {
+		op.Status, op.Complete = StatusUnknown, 0
+	}
+	return op
+}
+
+func del(id, client int, invoke, complete int64) *Operation {
+	return &Operation{ID: id, Client: client, Kind: KindDelete, Key: "k", Invoke: invoke, Complete: complete, Status: StatusOK}
+}
+
+func read(id, client int, invoke, complete int64, values ...string) *Operation {
+	op := &Operation{ID: id, Client: client, Kind: KindRead, Key: "k", Invoke: invoke, Complete: complete, Status: StatusOK}
+	if len(values) > 0 {
+		op.Found, op.Value, op.Values = true, values[0], values
+	}
+	return op
+}
+
+func final(op *Operation) *Operation {
+	op.Final = true
+	return op
+}
+
+func withClock(op *Operation, clock map[string]int64) *Operation {
+	op.VectorClock = &storage.VectorClock{Clocks: clock}
+	return op
+}
+
+func TestCheckVerdicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		ops     []*Operation
+		invalid []string // Models the history violates; it satisfies the rest
+	}{
+		{
+			name: "sequential reads of the latest write",
+			ops: []*Operation{
+				write(1, 1, "a", 0, 10),
+				read(2, 1, 20, 30, "a"),
+				write(3, 2, "b", 40, 50),
+				read(4, 1, 60, 70, "b"),
+				final(read(5, 3, 100, 110, "b")),
+			},
+		},
+		{
+			name: "stale read after a completed write",
+			ops: []*Operation{
+				write(1, 1, "a", 0, 10),
+				write(2, 2, "b", 20, 30),
+				read(3, 3, 40, 50, "a"),
+			},
+			invalid: []string{ModelLinearizable},
+		},
+		{
+			name: "client misses its own write",
+			ops: []*Operation{
+				write(1, 2, "a", 0, 10),
+				write(2, 1, "b", 20, 30),
+				read(3, 1, 40, 50, "a"),
+			},
+			invalid: []string{ModelLinearizable, ModelReadYourWrite},
+		},
+		{
+			name: "client's read goes back",
+			ops: []*Operation{
+				write(1, 2, "a", 0, 10),
+				write(2, 2, "b", 20, 30),
+				read(3, 1, 40, 50, "b"),
+				read(4, 1, 60, 70, "a"),
+			},
+			invalid: []string{ModelLinearizable, ModelMonotonicRead},
+		},
+		{
+			name: "acknowledged write missing at the end",
+			ops: []*Operation{
+				write(1, 1, "a", 0, 10),
+				write(2, 2, "b", 20, 30),
+				final(read(3, 3, 100, 110, "a")),
+			},
+			invalid: []string{ModelLinearizable, ModelNoLostWrites},
+		},
+		{
+			name: "unknown write may take effect late",
+			ops: []*Operation{
+				write(1, 1, "a", 0, 10),
+				write(2, 2, "b", 20, -1),
+				read(3, 3, 40, 50, "a"),
+				read(4, 3, 60, 70, "b"),
+			},
+		},
+		{
+			name: "overlapping writes read in either order",
+			ops: []*Operation{
+				write(1, 1, "a", 0, 50),
+				write(2, 2, "b", 10, 40),
+				read(3, 1, 60, 70, "b"),
+				read(4, 2, 80, 90, "b"),
+			},
+		},
+		{
+			// The writes overlapped, so a register may order them either
+			// way, but their clocks are concurrent and last-write-wins
+			// discarded a
+			name: "concurrent write lost to last-write-wins",
+			ops: []*Operation{
+				withClock(write(1, 1, "a", 0, 50), map[string]int64{"n1": 1}),
+				withClock(write(2, 2, "b", 10, 40), map[string]int64{"n2": 1}),
+				withClock(read(3, 1, 60, 70, "b"), map[string]int64{"n2": 1}),
+				withClock(final(read(4, 3, 100, 110, "b")), map[string]int64{"n2": 1}),
+			},
+			invalid: []string{ModelReadYourWrite, ModelNoLostWrites},
+		},
+		{
+			name: "later version by clock reflects an overlapping write",
+			ops: []*Operation{
+				withClock(write(1, 1, "a", 0, 50), map[string]int64{"n1": 1}),
+				withClock(write(2, 2, "b", 10, 40), map[string]int64{"n1": 1, "n2": 1}),
+				withClock(read(3, 1, 60, 70, "b"), map[string]int64{"n1": 1, "n2": 1}),
+				withClock(final(read(4, 3, 100, 110, "b")), map[string]int64{"n1": 1, "n2": 1}),
+			},
+		},
+		{
+			name: "client's read goes back by clock",
+			ops: []*Operation{
+				withClock(write(1, 1, "a", 0, 50), map[string]int64{"n1": 1}),
+				withClock(write(2, 2, "b", 10, 40), map[string]int64{"n1": 1, "n2": 1}),
+				withClock(read(3, 3, 60, 70, "b"), map[string]int64{"n1": 1, "n2": 1}),
+				withClock(read(4, 3, 80, 90, "a"), map[string]int64{"n1": 1}),
+			},
+			invalid: []string{ModelLinearizable, ModelMonotonicRead},
+		},
+		{
+			name: "delete read as not found",
+			ops: []*Operation{
+				withClock(write(1, 1, "a", 0, 10), map[string]int64{"n1": 1}),
+				withClock(del(2, 1, 20, 30), map[string]int64{"n1": 2}),
+				withClock(read(3, 1, 40, 50), map[string]int64{"n1": 2}),
+				withClock(final(read(4, 2, 100, 110)), map[string]int64{"n1": 2}),
+			},
+		},
+		{
+			name: "deleted value comes back",
+			ops: []*Operation{
+				withClock(write(1, 1, "a", 0, 10), map[string]int64{"n1": 1}),
+				withClock(del(2, 1, 20, 30), map[string]int64{"n1": 2}),
+				withClock(read(3, 1, 40, 50, "a"), map[string]int64{"n1": 1}),
+				withClock(final(read(4, 2, 100, 110, "a")), map[string]int64{"n1": 1}),
+			},
+			invalid: []string{ModelLinearizable, ModelReadYourWrite, ModelNoLostWrites},
+		},
+		{
+			name: "deleted key vanishes without a tombstone",
+			ops: []*Operation{
+				withClock(write(1, 1, "a", 0, 10), map[string]int64{"n1": 1}),
+				withClock(read(2, 2, 20, 30, "a"), map[string]int64{"n1": 1}),
+				read(3, 2, 40, 50),
+			},
+			invalid: []string{ModelLinearizable, ModelMonotonicRead},
+		},
+		{
+			name: "read of a key never written",
+			ops: []*Operation{
+				read(1, 1, 0, 10),
+				write(2, 1, "a", 20, 30),
+				read(3, 1, 40, 50),
+			},
+			invalid: []string{ModelLinearizable, ModelReadYourWrite},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Check(&History{Operations: tt.ops}, AllModels())
+
+			invalid := make(map[string]bool)
+			for _, model := range tt.invalid {
+				invalid[model] = true
+			}
+			for _, result := range report.Models {
+				if result.Valid == invalid[result.Model] {
+					t.Errorf("%s: valid = %t, want %t (anomalies %+v)", result.Model, result.Valid, !invalid[result.Model], result.Anomalies)
+				}
+				if len(result.Undecided) > 0 {
+					t.Errorf("%s: undecided for %v", result.Model, result.Undecided)
+				}
+			}
+			if report.Valid != (len(tt.invalid) == 0) {
+				t.Errorf("report valid = %t, want %t", report.Valid, len(tt.invalid) == 0)
+			}
+		})
+	}
+}
+
+func TestLinearizableAnomalyIsMinimal(t *testing.T) {
+	h := &History{Operations: []*Operation{
+		write(1, 1, "a", 0, 10),
+		read(2, 2, 15, 18, "a"),
+		write(3, 1, "b", 20, 30),
+		read(4, 2, 35, 38, "b"),
+		read(5, 3, 40, 50, "a"),
+		read(6, 2, 55, 58, "b"),
+	}}
+
+	result := checkLinearizable(h)
+	if len(result.Anomalies) != 1 {
+		t.Fatalf("anomalies = %+v, want one", result.Anomalies)
+	}
+
+	// The stale read and the writes it needs; the other reads are noise
+	got := make(map[int]bool)
+	for _, op := range result.Anomalies[0].Operations {
+		got[op.ID] = true
+	}
+	if len(got) != 3 || !got[1] || !got[3] || !got[5] {
+		t.Errorf("anomaly operations %v, want #1, #3 and #5", result.Anomalies[0].Operations)
+	}
+}
+
+func TestParseModels(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    int
+		wantErr bool
+	}{
+		{list: "", want: 4},
+		{list: "all", want: 4},
+		{list: "linearizable", want: 1},
+		{list: "no-lost-writes, monotonic-reads", want: 2},
+		{list: "serializable", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			models, err := ParseModels(tt.list)
+			if (err != nil) != tt.wantErr || len(models) != tt.want {
+				t.Errorf("ParseModels(%q) = %v, %v", tt.list, models, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+// Package history records what concurrent clients saw while talking to a
+// cluster and checks the recorded history against consistency models:
+// linearizable register, read-your-writes, monotonic reads and no lost
+// acknowledged writes or deletes. Violations are reported with the smallest
+// set of operations that still shows them.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/storage"
+)
+
+// Operation kinds
+const (
+	KindRead   = "read"
+	KindWrite  = "write"
+	KindDelete = "delete"
+)
+
+// Operation outcomes
+const (
+	StatusOK      = "ok"      // Completed and acknowledged
+	StatusFail    = "fail"    // Definitely had no effect (reads that errored)
+	StatusUnknown = "unknown" // May or may not have taken effect (writes that errored)
+)
+
+// Operation is one client request. Times are Unix nanoseconds on the
+// recorder's clock.
+type Operation struct {
+	ID     int    `json:"id"`
+	Client int    `json:"client"`
+	Node   string `json:"node"` // Node the request went to
+	Kind   string `json:"kind"`
+	Key    string `json:"key"`
+	// Written value, or the value a read returned; empty for deletes
+	Value string `json:"value,omitempty"`
+	// Every value a read returned: the winner and its concurrent siblings
+	Values []string `json:"values,omitempty"`
+	Found  bool     `json:"found,omitempty"`
+	// Version clock the write or delete created, or the clock of the version
+	// a read returned (the tombstone's when it found the key deleted)
+	VectorClock *storage.VectorClock `json:"vector_clock,omitempty"`
+	Invoke      int64                `json:"invoke"`
+	Complete    int64                `json:"complete"`
+	Status      string               `json:"status"`
+	Error       string               `json:"error,omitempty"`
+	// Reads issued after the workload, once the cluster was given time to settle
+	Final bool `json:"final,omitempty"`
+}
+
+// Mutates reports whether the operation changes the key: a write or a delete
+func (op *Operation) Mutates() bool {
+	return op.Kind == KindWrite || op.Kind == KindDelete
+}
+
+// Pending reports whether the operation never completed (an unknown write
+// or delete may take effect at any time after its invocation)
+func (op *Operation) Pending() bool {
+	return op.Status == StatusUnknown
+}
+
+// completedAt returns the completion time, or +inf for unknown outcomes
+func (op *Operation) completedAt() int64 {
+	if op.Pending() {
+		return math.MaxInt64
+	}
+	return op.Complete
+}
+
+// precedes reports whether op completed before other was invoked
+func (op *Operation) precedes(other *Operation) bool {
+	return op.completedAt() < other.Invoke
+}
+
+func (op *Operation) String() string {
+	var body string
+	switch {
+	case op.Kind == KindWrite:
+		body = fmt.Sprintf("write %s=%s", op.Key, op.Value)
+	case op.Kind == KindDelete:
+		body = fmt.Sprintf("delete %s", op.Key)
+	case op.Found:
+		body = fmt.Sprintf("read %s -> %v", op.Key, op.Values)
+	default:
+		body = fmt.Sprintf("read %s -> not found", op.Key)
+	}
+
+	clockText := ""
+	if op.VectorClock != nil {
+		clockText = " " + op.VectorClock.String()
+	}
+	final := ""
+	if op.Final {
+		final = " (final)"
+	}
+
+	complete := "…"
+	if !op.Pending() {
+		complete = fmt.Sprintf("%d", op.Complete)
+	}
+	return fmt.Sprintf("#%d c%d@%s [%d, %s] %s %s%s%s", op.ID, op.Client, op.Node, op.Invoke, complete, body, op.Status, clockText, final)
+}
+
+// History is a recorded set of operations, ordered by invocation
+type History struct {
+	Operations []*Operation `json:"operations"`
+}
+
+// Keys returns the keys the history touched, sorted
+func (h *History) Keys() []string {
+	seen := make(map[string]bool)
+	for _, op := range h.Operations {
+		seen[op.Key] = true
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ForKey returns the operations on one key
+func (h *History) ForKey(key string) []*Operation {
+	ops := make([]*Operation, 0)
+	for _, op := range h.Operations {
+		if op.Key == key {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// Save writes the history as JSON
+func (h *History) Save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Load reads a history written by Save
+func Load(path string) (*History, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var h History
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("invalid history %s: %v", path, err)
+	}
+	return &h, nil
+}
+
+// Recorder collects operations from concurrent clients
+type Recorder struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	ops   []*Operation
+}
+
+// NewRecorder creates a recorder timestamping with clk (nil = wall clock)
+func NewRecorder(clk clock.Clock) *Recorder {
+	return &Recorder{clock: clock.OrReal(clk)}
+}
+
+// Invoke records the start of an operation and returns it for Complete
+func (r *Recorder) Invoke(client int, node, kind, key, value string) *Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op := &Operation{
+		ID:     len(r.ops),
+		Client: client,
+		Node:   node,
+		Kind:   kind,
+		Key:    key,
+		Value:  value,
+		Invoke: r.clock.Now().UnixNano(),
+		Status: StatusUnknown,
+	}
+	r.ops = append(r.ops, op)
+	return op
+}
+
+// markFinal flags a read as part of the final reads
+func (r *Recorder) markFinal(op *Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op.Final = true
+}
+
+// CompleteWrite records the outcome of a write or delete
+func (r *Recorder) CompleteWrite(op *Operation, vectorClock *storage.VectorClock, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op.Complete = r.clock.Now().UnixNano()
+	op.VectorClock = vectorClock
+	if err != nil {
+		op.Status = StatusUnknown
+		op.Error = err.Error()
+		return
+	}
+	op.Status = StatusOK
+}
+
+// CompleteRead records the outcome of a read
+func (r *Recorder) CompleteRead(op *Operation, result *ReadResult, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op.Complete = r.clock.Now().UnixNano()
+	if err != nil {
+		op.Status = StatusFail
+		op.Error = err.Error()
+		return
+	}
+
+	op.Status = StatusOK
+	op.Found = result.Found
+	op.Values = result.Values
+	op.VectorClock = result.VectorClock
+	if len(result.Values) > 0 {
+		op.Value = result.Values[0]
+	}
+}
+
+// History returns a copy of what was recorded so far
+func (r *Recorder) History() *History {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ops := make([]*Operation, len(r.ops))
+	for i, op := range r.ops {
+		copied := *op
+		ops[i] = &copied
+	}
+	return &History{Operations: ops}
+}
@@ -0,0 +1,119 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"dynamodb/internal/storage"
+)
+
+// HTTPClient talks to one node of a running cluster through its public API
+type HTTPClient struct {
+	address string
+	client  *http.Client
+}
+
+// NewHTTPClient creates a client for the node at address (host:port)
+func NewHTTPClient(address string, timeout time.Duration) *HTTPClient {
+	return &HTTPClient{
+		address: address,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Node returns the node's address
+func (c *HTTPClient) Node() string {
+	return c.address
+}
+
+// Put writes through PUT /api/v1/data/{key}. Anything but a write that
+// reached the write quorum (200, durable or sloppy) is an error, so the
+// checker treats the outcome as unknown.
+func (c *HTTPClient) Put(key, value string) (*storage.VectorClock, error) {
+	body, err := json.Marshal(map[string]string{"value": value})
+	if err != nil {
+		return nil, err
+	}
+	return c.mutate(http.MethodPut, key, body)
+}
+
+// Delete deletes through DELETE /api/v1/data/{key}, with the same rules as Put
+func (c *HTTPClient) Delete(key string) (*storage.VectorClock, error) {
+	return c.mutate(http.MethodDelete, key, nil)
+}
+
+// mutate sends a write or delete and returns the clock of the version it
+// created
+func (c *HTTPClient) mutate(method, key string, body []byte) (*storage.VectorClock, error) {
+	endpoint := fmt.Sprintf("http://%s/api/v1/data/%s", c.address, url.PathEscape(key))
+	req, err := http.NewRequestWithContext(context.Background(), method, endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Error  string `json:"error"`
+		Result struct {
+			VectorClock *storage.VectorClock `json:"vector_clock"`
+		} `json:"replication_result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %v", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, response.Error)
+	}
+	return response.Result.VectorClock, nil
+}
+
+// Get reads through GET /api/v1/data/{key}
+func (c *HTTPClient) Get(key string) (*ReadResult, error) {
+	endpoint := fmt.Sprintf("http://%s/api/v1/data/%s", c.address, url.PathEscape(key))
+
+	resp, err := c.client.Get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Error       string                  `json:"error"`
+		Value       string                  `json:"value"`
+		VectorClock *storage.VectorClock    `json:"vector_clock"`
+		Siblings    []*storage.StorageValue `json:"siblings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode read response: %v", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// A deleted key comes with its tombstone's clock
+		return &ReadResult{VectorClock: response.VectorClock}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, response.Error)
+	}
+
+	result := &ReadResult{
+		Found:       true,
+		Values:      []string{response.Value},
+		VectorClock: response.VectorClock,
+	}
+	for _, sibling := range response.Siblings {
+		result.Values = append(result.Values, sibling.Value)
+	}
+	return result, nil
+}
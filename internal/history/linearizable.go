@@ -0,0 +1,253 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// linearizeBudget caps the states explored per search
+	linearizeBudget = 200000
+	// absent is the register state before the first write
+	absent = "\x00absent"
+)
+
+// errUndecided is returned when a search runs out of budget
+var errUndecided = errors.New("search budget exhausted")
+
+// checkLinearizable checks each key as an independent read/write register
+// (Wing & Gong search with memoization). A key that fails is shrunk to a
+// minimal sub-history that still can't be linearized.
+func checkLinearizable(h *History) *ModelResult {
+	result := &ModelResult{Model: ModelLinearizable}
+
+	for _, key := range h.Keys() {
+		ops := registerOps(h.ForKey(key))
+
+		ok, err := linearizable(ops)
+		if err != nil {
+			result.Undecided = append(result.Undecided, key)
+			continue
+		}
+		if ok {
+			continue
+		}
+
+		minimal := shrink(ops)
+		result.Anomalies = append(result.Anomalies, Anomaly{
+			Model:       ModelLinearizable,
+			Key:         key,
+			Description: fmt.Sprintf("no order of these %d operations on %s respects real time and register semantics", len(minimal), key),
+			Operations:  minimal,
+		})
+	}
+	return result
+}
+
+// registerOps keeps the operations that constrain the register: acknowledged
+// reads and every write or delete that may have taken effect
+func registerOps(ops []*Operation) []*Operation {
+	kept := make([]*Operation, 0, len(ops))
+	for _, op := range ops {
+		if op.Kind == KindRead && op.Status != StatusOK {
+			continue
+		}
+		if op.Mutates() && op.Status == StatusFail {
+			continue
+		}
+		kept = append(kept, op)
+	}
+	return sortByInvoke(kept)
+}
+
+// linearizable reports whether the operations can be put in an order that
+// respects real time and in which every read returns the latest write (or
+// nothing after a delete). Writes and deletes with unknown outcomes may be
+// placed anywhere after their invocation or left out.
+func linearizable(ops []*Operation) (bool, error) {
+	search := &linearizeSearch{
+		ops:    ops,
+		memo:   make(map[string]bool),
+		budget: linearizeBudget,
+	}
+
+	required := 0
+	for _, op := range ops {
+		if !op.Pending() {
+			required++
+		}
+	}
+
+	return search.run(make([]bool, len(ops)), absent, required)
+}
+
+type linearizeSearch struct {
+	ops    []*Operation
+	memo   map[string]bool // visited (linearized set, state) pairs that failed
+	budget int
+}
+
+// run tries every operation that may go next. done marks the linearized
+// operations; required counts acknowledged ones still to place.
+func (s *linearizeSearch) run(done []bool, state string, required int) (bool, error) {
+	if required == 0 {
+		return true, nil
+	}
+
+	memoKey := stateKey(done, state)
+	if s.memo[memoKey] {
+		return false, nil
+	}
+	s.budget--
+	if s.budget < 0 {
+		return false, errUndecided
+	}
+
+	// An operation may go next only if it was invoked before every
+	// remaining acknowledged operation completed
+	deadline := int64(math.MaxInt64)
+	for i, op := range s.ops {
+		if !done[i] && op.completedAt() < deadline {
+			deadline = op.completedAt()
+		}
+	}
+
+	for i, op := range s.ops {
+		if done[i] || op.Invoke > deadline {
+			continue
+		}
+
+		next := state
+		switch op.Kind {
+		case KindWrite:
+			next = op.Value
+		case KindDelete:
+			next = absent
+		case KindRead:
+			if !readMatches(op, state) {
+				continue
+			}
+		}
+
+		done[i] = true
+		remaining := required
+		if !op.Pending() {
+			remaining--
+		}
+		ok, err := s.run(done, next, remaining)
+		done[i] = false
+
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	s.memo[memoKey] = true
+	return false, nil
+}
+
+// readMatches reports whether a read could have returned the register state
+func readMatches(read *Operation, state string) bool {
+	if !read.Found {
+		return state == absent
+	}
+	for _, value := range read.Values {
+		if value == state {
+			return true
+		}
+	}
+	return false
+}
+
+func stateKey(done []bool, state string) string {
+	var b strings.Builder
+	b.Grow(len(done) + len(state) + 1)
+	for _, d := range done {
+		if d {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	b.WriteByte('|')
+	b.WriteString(state)
+	return b.String()
+}
+
+// shrink removes operations from a non-linearizable history for as long as
+// what is left still fails (keeping the writes behind every value read), in
+// halving chunks and then one at a time
+func shrink(ops []*Operation) []*Operation {
+	fails := func(candidate []*Operation) bool {
+		if !sourcesPresent(candidate) {
+			return false
+		}
+		ok, err := linearizable(candidate)
+		return err == nil && !ok
+	}
+
+	current := ops
+	for chunk := len(current) / 2; chunk >= 1; chunk /= 2 {
+		for start := 0; start < len(current); {
+			end := start + chunk
+			if end > len(current) {
+				end = len(current)
+			}
+
+			candidate := make([]*Operation, 0, len(current)-(end-start))
+			candidate = append(candidate, current[:start]...)
+			candidate = append(candidate, current[end:]...)
+
+			if len(candidate) > 0 && fails(candidate) {
+				current = candidate
+				continue
+			}
+			start = end
+		}
+	}
+	return current
+}
+
+// sourcesPresent reports whether every value a read returned was written by
+// one of the operations
+func sourcesPresent(ops []*Operation) bool {
+	written := make(map[string]bool)
+	for _, op := range ops {
+		if op.Kind == KindWrite {
+			written[op.Value] = true
+		}
+	}
+	for _, op := range ops {
+		if op.Kind != KindRead {
+			continue
+		}
+		for _, value := range op.Values {
+			if !written[value] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func sortByInvoke(ops []*Operation) []*Operation {
+	sort.SliceStable(ops, func(i, j int) bool {
+		if ops[i].Invoke == ops[j].Invoke {
+			return ops[i].ID < ops[j].ID
+		}
+		return ops[i].Invoke < ops[j].Invoke
+	})
+	return ops
+}
+
+// byCompletion returns the operations ordered by completion, unknown ones last
+func byCompletion(ops []*Operation) []*Operation {
+	sorted := append([]*Operation(nil), ops...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].completedAt() < sorted[j].completedAt()
+	})
+	return sorted
+}
@@ -0,0 +1,132 @@
+package history
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"dynamodb/internal/storage"
+)
+
+// ReadResult is what a client got back from a read
+type ReadResult struct {
+	Found  bool
+	Values []string // Winner first, then concurrent siblings
+	// The winner's clock, or the tombstone's for a deleted key (nil if the
+	// key was never written)
+	VectorClock *storage.VectorClock
+}
+
+// Client issues requests to one node of a cluster
+type Client interface {
+	// Node names the node requests go to
+	Node() string
+	// Put writes a value and returns the vector clock of the version it created
+	Put(key, value string) (*storage.VectorClock, error)
+	// Delete deletes a key and returns the vector clock of its tombstone
+	Delete(key string) (*storage.VectorClock, error)
+	// Get reads a key; a missing key is not an error
+	Get(key string) (*ReadResult, error)
+}
+
+// Workload describes concurrent clients issuing random reads, writes and
+// deletes
+type Workload struct {
+	Clients      int     // Concurrent clients, spread over the nodes round robin
+	Keys         int     // Distinct keys
+	OpsPerClient int     // Operations each client issues
+	ReadRatio    float64 // Fraction of operations that are reads
+	DeleteRatio  float64 // Fraction of the other operations that are deletes rather than writes
+	Seed         int64
+	KeyPrefix    string
+}
+
+// DefaultWorkload returns five clients doing 50 operations each on five
+// keys, one in ten mutations a delete
+func DefaultWorkload() *Workload {
+	return &Workload{
+		Clients:      5,
+		Keys:         5,
+		OpsPerClient: 50,
+		ReadRatio:    0.5,
+		DeleteRatio:  0.1,
+		Seed:         1,
+		KeyPrefix:    "check",
+	}
+}
+
+// KeyNames returns the workload's keys
+func (w *Workload) KeyNames() []string {
+	keys := make([]string, w.Keys)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s-%d", w.KeyPrefix, i)
+	}
+	return keys
+}
+
+// Run drives the clients concurrently and blocks until every client is
+// done. Each client is sequential and writes values unique to it, so reads
+// can be traced back to the write they observed. spawn starts a goroutine
+// (the clock's Go under simulation).
+func (w *Workload) Run(recorder *Recorder, nodes []Client, spawn func(func())) {
+	keys := w.KeyNames()
+
+	var wg sync.WaitGroup
+	wg.Add(w.Clients)
+	for i := 0; i < w.Clients; i++ {
+		client := i
+		rng := rand.New(rand.NewSource(w.Seed*7919 + int64(client)))
+		target := nodes[client%len(nodes)]
+
+		spawn(func() {
+			defer wg.Done()
+
+			for n := 0; n < w.OpsPerClient; n++ {
+				key := keys[rng.Intn(len(keys))]
+				switch roll := rng.Float64(); {
+				case roll < w.ReadRatio:
+					Read(recorder, client, target, key, false)
+				case roll < w.ReadRatio+(1-w.ReadRatio)*w.DeleteRatio:
+					Delete(recorder, client, target, key)
+				default:
+					Write(recorder, client, target, key, fmt.Sprintf("c%d-%d", client, n))
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// FinalReads reads every key once through every node, marking the reads as
+// final for the lost write check
+func (w *Workload) FinalReads(recorder *Recorder, nodes []Client) {
+	for i, node := range nodes {
+		for _, key := range w.KeyNames() {
+			Read(recorder, w.Clients+i, node, key, true)
+		}
+	}
+}
+
+// Write issues and records one write
+func Write(recorder *Recorder, client int, target Client, key, value string) {
+	op := recorder.Invoke(client, target.Node(), KindWrite, key, value)
+	vectorClock, err := target.Put(key, value)
+	recorder.CompleteWrite(op, vectorClock, err)
+}
+
+// Delete issues and records one delete
+func Delete(recorder *Recorder, client int, target Client, key string) {
+	op := recorder.Invoke(client, target.Node(), KindDelete, key, "")
+	vectorClock, err := target.Delete(key)
+	recorder.CompleteWrite(op, vectorClock, err)
+}
+
+// Read issues and records one read
+func Read(recorder *Recorder, client int, target Client, key string, final bool) {
+	op := recorder.Invoke(client, target.Node(), KindRead, key, "")
+	if final {
+		recorder.markFinal(op)
+	}
+	result, err := target.Get(key)
+	recorder.CompleteRead(op, result, err)
+}
@@ -0,0 +1,263 @@
+// Package membership is the one place a node keeps its view of who is in the
+// cluster and whether they are alive. Gossip (or, without gossip, the
+// service's own pings) reports status changes here; the ring, the replicator
+// and the APIs read from it and subscribe to its changes, so they all agree.
+package membership
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/detector"
+	"dynamodb/internal/node"
+)
+
+// Member is one node as seen by this node
+type Member struct {
+	NodeID      string    `json:"node_id"`
+	Address     string    `json:"address"`
+	Status      string    `json:"status"` // "alive", "suspected", "dead" or "left"
+	IsAlive     bool      `json:"is_alive"`
+	Incarnation int64     `json:"incarnation"`
+	Phi         float64   `json:"phi"`   // Suspicion level from the failure detector
+	Since       time.Time `json:"since"` // When the status last changed
+}
+
+// Change is delivered to subscribers when a member's status or address changes
+type Change struct {
+	Member   Member
+	Previous string // Status before the change, "" for a new member
+}
+
+// reachable reports whether a status still counts as alive: a suspected node
+// keeps receiving traffic until it is declared dead
+func reachable(status string) bool {
+	return status == "alive" || status == "suspected"
+}
+
+// StatusPrecedence ranks status claims about the same incarnation of a node:
+// suspected beats alive, and dead (or left) beats both. Gossip orders the
+// claims it merges the same way.
+func StatusPrecedence(status string) int {
+	switch status {
+	case "dead", "left":
+		return 2
+	case "suspected":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Service owns the liveness state of every known member
+type Service struct {
+	self  *node.Node
+	clock clock.Clock
+
+	mu      sync.RWMutex
+	members map[string]*Member
+	phi     func(nodeID string) float64
+
+	// Subscribers run one change at a time, in the order the changes
+	// happened, outside mu
+	subscribers []func(Change)
+	delivery    sync.Mutex
+
+	// Judges the service's own pings when it runs without gossip
+	detector   *detector.Detector
+	monitoring bool
+}
+
+// New creates a membership service containing only the current node. Time
+// comes from clk (nil = wall clock).
+func New(self *node.Node, clk clock.Clock) *Service {
+	s := &Service{
+		self:     self,
+		clock:    clock.OrReal(clk),
+		members:  make(map[string]*Member),
+		detector: detector.New(pingDetectorConfig(), clk),
+	}
+	s.phi = s.detector.Phi
+
+	s.members[self.ID] = &Member{
+		NodeID:  self.ID,
+		Address: self.Address,
+		Status:  "alive",
+		IsAlive: true,
+		Since:   s.clock.Now(),
+	}
+	return s
+}
+
+// SetPhiSource makes the service report suspicion levels from another
+// failure detector, e.g. gossip's
+func (s *Service) SetPhiSource(phi func(nodeID string) float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.phi = phi
+}
+
+// SetPhiThresholds sets the phi levels at which the service's own pings
+// suspect a node and declare it dead
+func (s *Service) SetPhiThresholds(suspectPhi, deadPhi float64) {
+	s.detector.SetThresholds(suspectPhi, deadPhi)
+}
+
+// Subscribe registers f to be called with every change from now on. Changes
+// are delivered one at a time and in order; f must not call Update.
+func (s *Service) Subscribe(f func(Change)) {
+	s.delivery.Lock()
+	defer s.delivery.Unlock()
+
+	s.subscribers = append(s.subscribers, f)
+}
+
+// Join records a node as alive, e.g. one added through the cluster API
+func (s *Service) Join(nodeID, address string) {
+	s.Update(nodeID, address, "alive", 0)
+}
+
+// Update records what the failure detection source knows about a node.
+// Subscribers hear about it if its status or address changed. Claims about
+// the current node are ignored: it is alive as long as it runs.
+//
+// Reports are ordered like gossip's: one about an older incarnation than we
+// hold is stale and ignored, a newer incarnation always applies, and within
+// an incarnation a status applies only if it ranks at least as high
+// (StatusPrecedence) or is alive. Alive reports about the same incarnation
+// are first-hand (an answered probe or ping); gossip only passes on others'
+// alive claims at a newer incarnation.
+func (s *Service) Update(nodeID, address, status string, incarnation int64) {
+	if nodeID == s.self.ID {
+		return
+	}
+
+	s.delivery.Lock()
+	defer s.delivery.Unlock()
+
+	s.mu.Lock()
+	member, exists := s.members[nodeID]
+	previous := ""
+	if exists {
+		previous = member.Status
+		if !supersedes(incarnation, status, member) {
+			s.mu.Unlock()
+			fmt.Printf("👥 Membership: ignoring stale %s report for %s at incarnation %d (have %s at %d)\n",
+				status, nodeID, incarnation, member.Status, member.Incarnation)
+			return
+		}
+	} else {
+		member = &Member{NodeID: nodeID}
+		s.members[nodeID] = member
+	}
+
+	changed := !exists || member.Status != status || (address != "" && member.Address != address)
+	if address != "" {
+		member.Address = address
+	}
+	if incarnation > member.Incarnation {
+		member.Incarnation = incarnation
+	}
+	if member.Status != status {
+		member.Status = status
+		member.IsAlive = reachable(status)
+		member.Since = s.clock.Now()
+	}
+	if s.monitoring {
+		if reachable(status) && !reachable(previous) {
+			s.detector.Heartbeat(nodeID)
+		} else if !reachable(status) {
+			s.detector.Remove(nodeID)
+		}
+	}
+	change := Change{Member: *member, Previous: previous}
+	s.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if previous != status {
+		fmt.Printf("👥 Membership: %s is %s (was %s)\n", nodeID, status, describe(previous))
+	}
+	for _, subscriber := range s.subscribers {
+		subscriber(change)
+	}
+}
+
+// supersedes reports whether a report about a node's incarnation and status
+// replaces what member holds
+func supersedes(incarnation int64, status string, member *Member) bool {
+	if incarnation != member.Incarnation {
+		return incarnation > member.Incarnation
+	}
+	return status == "alive" || StatusPrecedence(status) >= StatusPrecedence(member.Status)
+}
+
+func describe(status string) string {
+	if status == "" {
+		return "unknown"
+	}
+	return status
+}
+
+// IsAlive reports whether a node is alive or only suspected. The current
+// node is always alive.
+func (s *Service) IsAlive(nodeID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	member, exists := s.members[nodeID]
+	return exists && member.IsAlive
+}
+
+// Member returns one member with its current phi
+func (s *Service) Member(nodeID string) (Member, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	member, exists := s.members[nodeID]
+	if !exists {
+		return Member{}, false
+	}
+	return s.view(member), true
+}
+
+// Members returns every known member, including dead ones, in ID order
+func (s *Service) Members() []Member {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := make([]Member, 0, len(s.members))
+	for _, member := range s.members {
+		members = append(members, s.view(member))
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].NodeID < members[j].NodeID
+	})
+	return members
+}
+
+// Summary counts members by status
+func (s *Service) Summary() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summary := map[string]int{"alive": 0, "suspected": 0, "dead": 0, "left": 0}
+	for _, member := range s.members {
+		summary[member.Status]++
+	}
+	return summary
+}
+
+// view copies a member and fills in its phi. Callers hold mu.
+func (s *Service) view(member *Member) Member {
+	view := *member
+	if view.NodeID != s.self.ID && view.IsAlive {
+		view.Phi = s.phi(view.NodeID)
+	}
+	return view
+}
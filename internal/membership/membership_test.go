@@ -0,0 +1,120 @@
+package membership
+
+import (
+	"testing"
+
+	"dynamodb/internal/node"
+	"dynamodb/internal/ring"
+)
+
+type report struct {
+	status      string
+	incarnation int64
+}
+
+func TestUpdateOrdersReportsByIncarnation(t *testing.T) {
+	tests := []struct {
+		name    string
+		reports []report
+		want    report
+	}{
+		{
+			name:    "newer incarnation applies",
+			reports: []report{{"dead", 1}, {"alive", 2}},
+			want:    report{"alive", 2},
+		},
+		{
+			name:    "older incarnation is stale",
+			reports: []report{{"dead", 3}, {"alive", 0}},
+			want:    report{"dead", 3},
+		},
+		{
+			name:    "older suspicion is stale",
+			reports: []report{{"alive", 2}, {"suspected", 1}},
+			want:    report{"alive", 2},
+		},
+		{
+			name:    "suspicion applies within an incarnation",
+			reports: []report{{"alive", 1}, {"suspected", 1}},
+			want:    report{"suspected", 1},
+		},
+		{
+			name:    "dead doesn't fall back to suspected within an incarnation",
+			reports: []report{{"dead", 1}, {"suspected", 1}},
+			want:    report{"dead", 1},
+		},
+		{
+			name:    "first-hand alive clears a suspicion within an incarnation",
+			reports: []report{{"suspected", 1}, {"alive", 1}},
+			want:    report{"alive", 1},
+		},
+		{
+			name:    "leave applies after death within an incarnation",
+			reports: []report{{"dead", 1}, {"left", 1}},
+			want:    report{"left", 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(node.NewNode("self", "localhost:1"), nil)
+			for _, r := range tt.reports {
+				s.Update("peer", "localhost:2", r.status, r.incarnation)
+			}
+
+			member, ok := s.Member("peer")
+			if !ok {
+				t.Fatal("peer not recorded")
+			}
+			if got := (report{member.Status, member.Incarnation}); got != tt.want {
+				t.Errorf("member = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateNotifiesOnlyAppliedReports(t *testing.T) {
+	s := New(node.NewNode("self", "localhost:1"), nil)
+
+	var changes []Change
+	s.Subscribe(func(c Change) { changes = append(changes, c) })
+
+	s.Update("peer", "localhost:2", "dead", 2)
+	s.Join("peer", "localhost:2") // Incarnation 0: stale
+
+	if len(changes) != 1 || changes[0].Member.Status != "dead" {
+		t.Errorf("changes = %+v, want only the dead report", changes)
+	}
+}
+
+func TestFeedRingKeepsFailedMembers(t *testing.T) {
+	s := New(node.NewNode("self", "localhost:1"), nil)
+	hashRing := ring.NewConsistentHashRing()
+	s.FeedRing(hashRing)
+
+	s.Join("peer", "localhost:2")
+	if hashRing.GetNode("peer") == nil {
+		t.Fatal("joined peer not on the ring")
+	}
+
+	for _, status := range []string{"suspected", "dead"} {
+		s.Update("peer", "", status, 0)
+		ringNode := hashRing.GetNode("peer")
+		if ringNode == nil {
+			t.Fatalf("%s peer taken off the ring", status)
+		}
+		if ringNode.IsHealthy() {
+			t.Errorf("%s peer still healthy on the ring", status)
+		}
+	}
+
+	s.Update("peer", "", "alive", 1)
+	if ringNode := hashRing.GetNode("peer"); ringNode == nil || !ringNode.IsHealthy() {
+		t.Error("recovered peer not healthy on the ring")
+	}
+
+	s.Update("peer", "", "left", 1)
+	if hashRing.GetNode("peer") != nil {
+		t.Error("peer that left is still on the ring")
+	}
+}
@@ -0,0 +1,79 @@
+package membership
+
+import (
+	"context"
+	"time"
+
+	"dynamodb/internal/detector"
+	"dynamodb/internal/node"
+)
+
+// PingInterval is how often Monitor pings every member
+const PingInterval = 3 * time.Second
+
+// Pinger checks that a node is up
+type Pinger interface {
+	Ping(ctx context.Context, target *node.Node) error
+}
+
+// pingDetectorConfig tunes the failure detector for our own pings: they are
+// sent on a fixed schedule, so no extra pause needs to be tolerated
+func pingDetectorConfig() detector.Config {
+	config := detector.DefaultConfig(PingInterval)
+	config.AcceptablePause = 0
+	config.MinStdDev = 500 * time.Millisecond
+	return config
+}
+
+// Monitor makes the service detect failures itself, for clusters running
+// without gossip: every member is pinged each PingInterval and judged by a
+// phi accrual detector. Dead members keep being pinged so they can come
+// back. It runs until ctx is done.
+func (s *Service) Monitor(ctx context.Context, pinger Pinger) {
+	s.mu.Lock()
+	s.monitoring = true
+	s.phi = s.detector.Phi
+	for nodeID, member := range s.members {
+		if nodeID != s.self.ID && member.IsAlive {
+			s.detector.Heartbeat(nodeID)
+		}
+	}
+	s.mu.Unlock()
+
+	s.clock.Every(ctx, PingInterval, func() { s.pingMembers(ctx, pinger) })
+}
+
+// pingMembers judges every member on the pings so far, then pings them again
+func (s *Service) pingMembers(ctx context.Context, pinger Pinger) {
+	for _, member := range s.Members() {
+		if member.NodeID == s.self.ID || member.Status == "left" {
+			continue
+		}
+
+		// Only an answered ping clears a suspicion
+		if member.IsAlive {
+			status := s.detector.Status(member.NodeID)
+			if status != "alive" && status != member.Status {
+				s.Update(member.NodeID, "", status, 0)
+			}
+		}
+
+		target := node.NewNode(member.NodeID, member.Address)
+		s.clock.Go(func() { s.ping(ctx, pinger, target) })
+	}
+}
+
+// ping counts an answered ping as a heartbeat
+func (s *Service) ping(ctx context.Context, pinger Pinger, target *node.Node) {
+	pingCtx, cancel := s.clock.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if err := pinger.Ping(pingCtx, target); err != nil {
+		return
+	}
+
+	if s.IsAlive(target.ID) {
+		s.detector.Heartbeat(target.ID)
+	}
+	s.Update(target.ID, "", "alive", 0)
+}
@@ -0,0 +1,39 @@
+package membership
+
+import (
+	"dynamodb/internal/node"
+	"dynamodb/internal/ring"
+)
+
+// FeedRing keeps a hash ring in line with the membership. Every member is on
+// the ring with its status, including failed ones: a dead owner keeps owning
+// its keys, so writes made while it is down go to stand-ins and hints for it
+// instead of to a different owner set. Only a member that left the cluster is
+// taken off.
+func (s *Service) FeedRing(hashRing *ring.ConsistentHashRing) {
+	s.Subscribe(func(change Change) {
+		member := change.Member
+		ringNode := hashRing.GetNode(member.NodeID)
+
+		if member.Status == "left" {
+			if ringNode != nil {
+				ringNode.MarkFailed()
+				hashRing.RemoveNode(member.NodeID)
+			}
+			return
+		}
+
+		if ringNode == nil {
+			ringNode = node.NewNode(member.NodeID, member.Address)
+			hashRing.AddNode(ringNode)
+		}
+		switch member.Status {
+		case "alive":
+			ringNode.MarkAlive()
+		case "suspected":
+			ringNode.MarkSuspected()
+		default:
+			ringNode.MarkFailed()
+		}
+	})
+}
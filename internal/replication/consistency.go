@@ -0,0 +1,120 @@
+package replication
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ConsistencyLevel selects how many replicas must answer a read or write
+type ConsistencyLevel string
+
+const (
+	ConsistencyDefault ConsistencyLevel = ""       // Use the configured R/W
+	ConsistencyOne     ConsistencyLevel = "ONE"    // Any single replica
+	ConsistencyQuorum  ConsistencyLevel = "QUORUM" // A majority of the N replicas
+	ConsistencyAll     ConsistencyLevel = "ALL"    // Every replica
+	ConsistencyLocal   ConsistencyLevel = "LOCAL"  // Only the coordinator's local copy
+)
+
+// ParseConsistencyLevel parses a consistency level name (case-insensitive)
+func ParseConsistencyLevel(level string) (ConsistencyLevel, error) {
+	switch ConsistencyLevel(strings.ToUpper(strings.TrimSpace(level))) {
+	case ConsistencyDefault:
+		return ConsistencyDefault, nil
+	case ConsistencyOne:
+		return ConsistencyOne, nil
+	case ConsistencyQuorum:
+		return ConsistencyQuorum, nil
+	case ConsistencyAll:
+		return ConsistencyAll, nil
+	case ConsistencyLocal:
+		return ConsistencyLocal, nil
+	default:
+		return ConsistencyDefault, fmt.Errorf("unknown consistency level %q (expected ONE, QUORUM, ALL or LOCAL)", level)
+	}
+}
+
+// ReplicationConfig holds the N/R/W settings for a set of keys
+type ReplicationConfig struct {
+	N int `json:"n"` // Replicas per key
+	R int `json:"r"` // Replicas that must answer a read
+	W int `json:"w"` // Replicas that must acknowledge a write
+}
+
+// DefaultReplicationConfig returns the classic N=3, R=2, W=2 setup
+func DefaultReplicationConfig() *ReplicationConfig {
+	return &ReplicationConfig{N: 3, R: 2, W: 2}
+}
+
+// Validate checks that R and W fit within N
+func (c *ReplicationConfig) Validate() error {
+	if c.N < 1 {
+		return fmt.Errorf("replication factor N must be at least 1, got %d", c.N)
+	}
+	if c.R < 1 || c.R > c.N {
+		return fmt.Errorf("read quorum R must be between 1 and N=%d, got %d", c.N, c.R)
+	}
+	if c.W < 1 || c.W > c.N {
+		return fmt.Errorf("write quorum W must be between 1 and N=%d, got %d", c.N, c.W)
+	}
+	return nil
+}
+
+// IsStrong reports whether every read quorum overlaps every write quorum (R+W>N)
+func (c *ReplicationConfig) IsStrong() bool {
+	return c.R+c.W > c.N
+}
+
+// ConsistencyOptions carries per-request consistency settings
+type ConsistencyOptions struct {
+	Level ConsistencyLevel `json:"level,omitempty"`
+	// Strong requests read-your-writes semantics; the request is rejected if
+	// the effective R+W does not exceed N
+	Strong bool `json:"strong,omitempty"`
+	// AcceptPartial keeps a write that reached fewer than W replicas and
+	// reports it as accepted instead of failing it
+	AcceptPartial bool `json:"accept_partial,omitempty"`
+}
+
+// requiredReplicas returns how many replicas must answer for a level, given
+// the configured count used by ConsistencyDefault
+func requiredReplicas(level ConsistencyLevel, n, configured int) int {
+	switch level {
+	case ConsistencyOne, ConsistencyLocal:
+		return 1
+	case ConsistencyQuorum:
+		return n/2 + 1
+	case ConsistencyAll:
+		return n
+	default:
+		return configured
+	}
+}
+
+// NamespaceOf returns the namespace of a key: the part before the first ':'
+// ("user:123" -> "user"). Keys without a ':' belong to no namespace.
+func NamespaceOf(key string) string {
+	if idx := strings.Index(key, ":"); idx > 0 {
+		return key[:idx]
+	}
+	return ""
+}
+
+// LoadNamespaceConfigs reads per-namespace overrides from a JSON file of the form
+// {"user": {"n": 5, "r": 3, "w": 3}, "session": {"w": 1}}. Omitted fields
+// inherit the cluster-wide defaults.
+func LoadNamespaceConfigs(path string) (map[string]*ReplicationConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace config: %v", err)
+	}
+
+	configs := make(map[string]*ReplicationConfig)
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace config: %v", err)
+	}
+
+	return configs, nil
+}
@@ -0,0 +1,81 @@
+package replication
+
+import (
+	"dynamodb/internal/storage"
+)
+
+// maxDeltaEvents caps the events shipped with one replication request. The
+// oldest unacknowledged events go first; the target's clock only covers
+// events it holds, so the rest follow with later requests.
+const maxDeltaEvents = 256
+
+// deltaFor returns the events to ship to a target along with a mutation: the
+// source event plus the events the target hasn't acknowledged yet. Until a
+// target acknowledged a clock, only the source event is sent.
+func (r *Replicator) deltaFor(targetID string, sourceEvent *storage.Event) []*storage.Event {
+	r.ackMutex.RLock()
+	acked := r.ackedClocks[targetID]
+	r.ackMutex.RUnlock()
+
+	events := make([]*storage.Event, 0)
+	if acked != nil {
+		events = r.storage.EventsSince(acked)
+		if len(events) > maxDeltaEvents {
+			events = events[:maxDeltaEvents]
+		}
+	}
+
+	if sourceEvent == nil {
+		return events
+	}
+	for _, event := range events {
+		if event.ID == sourceEvent.ID {
+			return events
+		}
+	}
+	return append(events, sourceEvent)
+}
+
+// recordAck remembers the clock a target reported after applying a request
+func (r *Replicator) recordAck(targetID string, clock *storage.VectorClock) {
+	if clock == nil {
+		return
+	}
+
+	r.ackMutex.Lock()
+	defer r.ackMutex.Unlock()
+
+	if acked, exists := r.ackedClocks[targetID]; exists {
+		acked.Update(clock)
+		return
+	}
+	r.ackedClocks[targetID] = clock.Copy()
+}
+
+// ackedClockSnapshot returns the last acknowledged clock per target
+func (r *Replicator) ackedClockSnapshot() map[string]string {
+	r.ackMutex.RLock()
+	defer r.ackMutex.RUnlock()
+
+	snapshot := make(map[string]string, len(r.ackedClocks))
+	for targetID, clock := range r.ackedClocks {
+		snapshot[targetID] = clock.String()
+	}
+	return snapshot
+}
+
+// mergeDelta adds the events shipped with a request to the local event log.
+// Only those events advance the local clock, never the sender's clock, so
+// the clock acknowledged back doesn't cover events that weren't shipped.
+func (r *Replicator) mergeDelta(req *ReplicationRequest) {
+	events := make([]*storage.Event, 0, len(req.Events)+1)
+	events = append(events, req.Events...)
+	if req.SourceEvent != nil {
+		events = append(events, req.SourceEvent)
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	r.storage.MergeEvents(req.SourceNode, events)
+}
@@ -0,0 +1,39 @@
+package replication
+
+import (
+	"fmt"
+	"testing"
+)
+
+// A delta capped at maxDeltaEvents leaves the rest for later requests
+// instead of having the target acknowledge events it never got
+func TestCappedDeltaCatchesUpOnLaterWrites(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 2, R: 1, W: 2}, "a", "b")
+	a, b := c.nodes["a"], c.nodes["b"]
+	if _, err := c.write("a", "user:0", "v0"); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+
+	// Events b hasn't seen, more than one delta carries
+	for i := 0; i < maxDeltaEvents+40; i++ {
+		a.storage.RecordEvent("put", fmt.Sprintf("unshipped:%d", i), "v")
+	}
+	before := b.storage.CurrentClock().Clocks["a"]
+
+	if _, err := c.write("a", "user:1", "v1"); err != nil {
+		t.Fatalf("capped write: %v", err)
+	}
+	if got, want := b.storage.CurrentClock().Clocks["a"], before+maxDeltaEvents; got != want {
+		t.Errorf("b's clock covers a up to %d after a capped delta, want %d", got, want)
+	}
+
+	if _, err := c.write("a", "user:2", "v2"); err != nil {
+		t.Fatalf("catch-up write: %v", err)
+	}
+	if got, want := b.storage.CurrentClock().Clocks["a"], a.storage.CurrentClock().Clocks["a"]; got != want {
+		t.Errorf("b's clock covers a up to %d after catching up, want %d", got, want)
+	}
+	if missing := len(a.storage.EventsSince(b.storage.CurrentClock())); missing != 0 {
+		t.Errorf("b is missing %d of a's events", missing)
+	}
+}
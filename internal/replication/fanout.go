@@ -0,0 +1,182 @@
+package replication
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"dynamodb/internal/node"
+)
+
+// fanoutResult collects what happened to a mutation sent to a key's replicas
+// by the time the coordinator answered
+type fanoutResult struct {
+	successful []string                 // Owners and stand-ins that applied the mutation; these count towards W
+	failed     []string                 // Owners that missed the mutation
+	hinted     []string                 // Owners the coordinator kept a hint for
+	standIns   map[string]string        // Intended owner -> stand-in that took its copy
+	pending    []string                 // Owners still being replicated to in the background
+	queued     []string                 // Owners the mutation was queued for
+	latencies  map[string]time.Duration // Per-replica round trip
+}
+
+// replicaOutcome is the result of replicating to one owner (or its stand-in)
+type replicaOutcome struct {
+	owner     string
+	ackedBy   string // Owner or stand-in that acknowledged; "" on failure
+	hinted    bool
+	latencies map[string]time.Duration
+}
+
+// record folds one replica outcome into the result. A stand-in's ack counts
+// as a success: the stand-in applied the copy, and quorum reads ask it in
+// place of the owner while the owner is down.
+func (f *fanoutResult) record(outcome replicaOutcome) {
+	for nodeID, latency := range outcome.latencies {
+		f.latencies[nodeID] = latency
+	}
+
+	switch {
+	case outcome.ackedBy == outcome.owner:
+		f.successful = append(f.successful, outcome.owner)
+	case outcome.ackedBy != "":
+		f.successful = append(f.successful, outcome.ackedBy)
+		f.failed = append(f.failed, outcome.owner)
+		f.standIns[outcome.owner] = outcome.ackedBy
+	default:
+		f.failed = append(f.failed, outcome.owner)
+		if outcome.hinted {
+			f.hinted = append(f.hinted, outcome.owner)
+		}
+	}
+}
+
+// replicate sends a mutation to the key's other replicas. Writes that only
+// need one acknowledgement and were applied locally go through the durable
+// async queue; everything else fans out to the replicas directly.
+func (r *Replicator) replicate(key string, plan *quorumPlan, request ReplicationRequest) *fanoutResult {
+	if !r.useQueue(key, plan) {
+		return r.replicateToPreferenceList(key, plan.config.N, plan.required, request)
+	}
+
+	return &fanoutResult{
+		successful: []string{r.currentNode.ID},
+		failed:     []string{},
+		hinted:     []string{},
+		standIns:   make(map[string]string),
+		pending:    []string{},
+		queued:     r.enqueueToOwners(key, plan.config.N, request),
+		latencies:  map[string]time.Duration{r.currentNode.ID: 0},
+	}
+}
+
+// replicateToPreferenceList sends a mutation to the key's owners in
+// parallel; an owner coordinating it has already applied it locally. It
+// returns as soon as required replicas acknowledged (or every replica
+// answered) and lets the remaining replications finish in the background.
+//
+// With sloppy quorum enabled, an owner that is down or fails is replaced by
+// the next healthy node further along the ring, which applies the copy, keeps
+// it tagged with the owner and hands it back when the owner returns. The
+// stand-in's ack counts towards required. If no stand-in takes it, the
+// coordinator keeps a hint itself, which doesn't count.
+func (r *Replicator) replicateToPreferenceList(key string, n, required int, request ReplicationRequest) *fanoutResult {
+	result := &fanoutResult{
+		successful: []string{},
+		failed:     []string{},
+		hinted:     []string{},
+		standIns:   make(map[string]string),
+		pending:    []string{},
+		latencies:  make(map[string]time.Duration),
+	}
+
+	owners, candidates := r.extendedPreferenceList(key, n)
+	pool := newStandInPool(owners, candidates)
+	sloppy := r.IsSloppyQuorum()
+
+	outcomes := make(chan replicaOutcome, len(owners))
+	inFlight := make(map[string]bool)
+	for _, owner := range owners {
+		if owner.ID == r.currentNode.ID {
+			// The coordinator already applied the mutation locally
+			result.successful = append(result.successful, owner.ID)
+			result.latencies[owner.ID] = 0
+			continue
+		}
+
+		inFlight[owner.ID] = true
+		owner := owner
+		r.clock.Go(func() {
+			outcomes <- r.replicateToOwner(owner, pool, sloppy, request)
+		})
+	}
+
+	for len(inFlight) > 0 && len(result.successful) < required {
+		outcome := <-outcomes
+		delete(inFlight, outcome.owner)
+		result.record(outcome)
+	}
+
+	if len(inFlight) > 0 {
+		for owner := range inFlight {
+			result.pending = append(result.pending, owner)
+		}
+		sort.Strings(result.pending)
+
+		// Finish the remaining replications after the client has its answer
+		remaining := len(inFlight)
+		r.clock.Go(func() {
+			for i := 0; i < remaining; i++ {
+				outcome := <-outcomes
+				if outcome.ackedBy == "" {
+					fmt.Printf("⚠️ Background replication of %s to %s failed (hinted: %t)\n", key, outcome.owner, outcome.hinted)
+				}
+			}
+		})
+	}
+
+	return result
+}
+
+// replicateToOwner sends the mutation to one owner, falling back to a
+// stand-in and then to a local hint when the owner can't take it
+func (r *Replicator) replicateToOwner(owner *node.Node, pool *standInPool, sloppy bool, request ReplicationRequest) replicaOutcome {
+	outcome := replicaOutcome{
+		owner:     owner.ID,
+		latencies: make(map[string]time.Duration),
+	}
+
+	// Only replicate to alive nodes
+	if r.isNodeAlive(owner.ID) {
+		start := r.clock.Now()
+		ok := r.replicateToNode(owner, &request)
+		outcome.latencies[owner.ID] = r.clock.Since(start)
+		if ok {
+			outcome.ackedBy = owner.ID
+			return outcome
+		}
+	}
+
+	if sloppy {
+		standIn, latencies := r.writeToStandIn(owner.ID, pool, request)
+		for nodeID, latency := range latencies {
+			outcome.latencies[nodeID] = latency
+		}
+		if standIn != "" {
+			outcome.ackedBy = standIn
+			return outcome
+		}
+	}
+
+	outcome.hinted = r.storeHint(owner.ID, &request)
+	return outcome
+}
+
+// latenciesInMs converts per-replica latencies to milliseconds for WriteResult
+func latenciesInMs(latencies map[string]time.Duration) map[string]float64 {
+	ms := make(map[string]float64, len(latencies))
+	for nodeID, latency := range latencies {
+		ms[nodeID] = float64(latency.Nanoseconds()) / 1000000
+	}
+	return ms
+}
@@ -0,0 +1,91 @@
+package replication
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/node"
+)
+
+// slowTransport holds replication to some nodes back by a delay in
+// simulated time before passing it on
+type slowTransport struct {
+	Transport
+	clock  clock.Clock
+	delays map[string]time.Duration
+}
+
+func (t *slowTransport) Replicate(ctx context.Context, target *node.Node, request *ReplicationRequest) (*ReplicationResponse, error) {
+	if delay := t.delays[target.ID]; delay > 0 {
+		select {
+		case <-t.clock.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return t.Transport.Replicate(ctx, target, request)
+}
+
+func TestFanoutReturnsAtWriteQuorum(t *testing.T) {
+	tests := []struct {
+		name       string
+		level      ConsistencyLevel
+		successful int
+		pending    bool          // Whether the slow owner is still pending on return
+		minElapsed time.Duration // Simulated time the write takes at least
+	}{
+		{name: "default W", level: ConsistencyDefault, successful: 2, pending: true},
+		{name: "ALL", level: ConsistencyAll, successful: 3, minElapsed: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c")
+			owners, _ := c.owners("user:1")
+			coordinator, slow := c.nodes[owners[0]], owners[2]
+			coordinator.transport = &slowTransport{
+				Transport: coordinator.transport,
+				clock:     c.clock,
+				delays:    map[string]time.Duration{slow: time.Second},
+			}
+
+			var result *WriteResult
+			var err error
+			start := c.clock.Now()
+			c.run(func() {
+				result, err = coordinator.WriteWithReplication("user:1", "v1", &ConsistencyOptions{Level: tt.level})
+			})
+			if err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if elapsed := c.clock.Since(start); elapsed < tt.minElapsed || (tt.pending && elapsed >= time.Second) {
+				t.Errorf("write returned after %v of simulated time", elapsed)
+			}
+
+			if len(result.SuccessfulNodes) != tt.successful {
+				t.Errorf("successful nodes = %v, want %d", result.SuccessfulNodes, tt.successful)
+			}
+			if !tt.pending {
+				if len(result.PendingNodes) != 0 {
+					t.Errorf("pending nodes = %v, want none", result.PendingNodes)
+				}
+				return
+			}
+			if want := []string{slow}; !reflect.DeepEqual(result.PendingNodes, want) {
+				t.Errorf("pending nodes = %v, want %v", result.PendingNodes, want)
+			}
+			if _, err := c.nodes[slow].storage.GetVersion("user:1"); err == nil {
+				t.Errorf("slow owner %s had the write before it was sent", slow)
+			}
+
+			// The slow replica still gets its copy after the client has its answer
+			c.run(func() { c.clock.Sleep(2 * time.Second) })
+			if value, err := c.nodes[slow].storage.GetVersion("user:1"); err != nil || value.Value != "v1" {
+				t.Errorf("slow owner %s after the write returned: %v, %v; want v1", slow, value, err)
+			}
+		})
+	}
+}
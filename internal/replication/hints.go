@@ -0,0 +1,315 @@
+package replication
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/node"
+	"dynamodb/internal/storage"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Hint is a mutation that couldn't be delivered to one of its replicas. It is
+// kept by the coordinator and replayed once the target is reachable again.
+type Hint struct {
+	ID         string              `json:"id"`
+	TargetNode string              `json:"target_node"`
+	Request    *ReplicationRequest `json:"request"`
+	CreatedAt  int64               `json:"created_at"` // Unix nanoseconds
+	Attempts   int                 `json:"attempts"`
+}
+
+// HintStats summarizes the hints pending for one target node
+type HintStats struct {
+	Count            int     `json:"count"`
+	OldestAgeSeconds float64 `json:"oldest_age_seconds"`
+	OldestAttempts   int     `json:"oldest_attempts"` // Delivery attempts of the oldest hint
+}
+
+// HintStore persists hints in their own LevelDB so they survive restarts.
+// Keys are "<target>/<created_at>-<seq>" so each target's hints iterate in
+// the order they were written.
+type HintStore struct {
+	db    *leveldb.DB
+	clock clock.Clock // Timestamps hints and ages them in Stats
+	mu    sync.Mutex
+	seq   uint64
+}
+
+// NewHintStore opens (or creates) the hint database at path. clk timestamps
+// the hints (nil = wall clock).
+func NewHintStore(path string, clk clock.Clock) (*HintStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hint store at %s: %v", path, err)
+	}
+
+	fmt.Printf("📮 Hint store initialized at %s\n", path)
+	return &HintStore{db: db, clock: clock.OrReal(clk)}, nil
+}
+
+// Add stores a hint for a mutation that target missed
+func (hs *HintStore) Add(target string, request *ReplicationRequest) (*Hint, error) {
+	hs.mu.Lock()
+	hs.seq++
+	seq := hs.seq
+	hs.mu.Unlock()
+
+	// The event delta is recomputed against the target when the hint is replayed
+	hinted := *request
+	hinted.Events = nil
+
+	now := hs.clock.Now().UnixNano()
+	hint := &Hint{
+		ID:         fmt.Sprintf("%s/%020d-%06d", target, now, seq),
+		TargetNode: target,
+		Request:    &hinted,
+		CreatedAt:  now,
+	}
+
+	if err := hs.put(hint); err != nil {
+		return nil, err
+	}
+	return hint, nil
+}
+
+// ForTarget returns the hints pending for a target, oldest first
+func (hs *HintStore) ForTarget(target string) ([]*Hint, error) {
+	iter := hs.db.NewIterator(util.BytesPrefix([]byte(target+"/")), nil)
+	defer iter.Release()
+
+	hints := make([]*Hint, 0)
+	for iter.Next() {
+		var hint Hint
+		if err := json.Unmarshal(iter.Value(), &hint); err != nil {
+			fmt.Printf("⚠️ Skipping unreadable hint %s: %v\n", string(iter.Key()), err)
+			continue
+		}
+		hints = append(hints, &hint)
+	}
+
+	return hints, iter.Error()
+}
+
+// Remove deletes a delivered hint
+func (hs *HintStore) Remove(hint *Hint) error {
+	return hs.db.Delete([]byte(hint.ID), nil)
+}
+
+// RecordAttempt persists a failed delivery attempt
+func (hs *HintStore) RecordAttempt(hint *Hint) error {
+	hint.Attempts++
+	return hs.put(hint)
+}
+
+// Targets returns the nodes that have pending hints, in ID order
+func (hs *HintStore) Targets() []string {
+	stats := hs.Stats()
+	targets := make([]string, 0, len(stats))
+	for target := range stats {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// Stats returns hint counts and ages per target node
+func (hs *HintStore) Stats() map[string]*HintStats {
+	iter := hs.db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	now := hs.clock.Now().UnixNano()
+	stats := make(map[string]*HintStats)
+	for iter.Next() {
+		target := string(iter.Key())
+		if idx := strings.LastIndex(target, "/"); idx >= 0 {
+			target = target[:idx]
+		}
+
+		var hint Hint
+		if err := json.Unmarshal(iter.Value(), &hint); err != nil {
+			continue
+		}
+
+		targetStats, exists := stats[target]
+		if !exists {
+			// Keys are ordered, so the first hint seen is the oldest
+			targetStats = &HintStats{
+				OldestAgeSeconds: float64(now-hint.CreatedAt) / float64(time.Second),
+				OldestAttempts:   hint.Attempts,
+			}
+			stats[target] = targetStats
+		}
+		targetStats.Count++
+	}
+
+	return stats
+}
+
+// Close closes the hint database
+func (hs *HintStore) Close() error {
+	return hs.db.Close()
+}
+
+func (hs *HintStore) put(hint *Hint) error {
+	data, err := json.Marshal(hint)
+	if err != nil {
+		return err
+	}
+	return hs.db.Put([]byte(hint.ID), data, nil)
+}
+
+// storeHint keeps a mutation for a replica that missed it. Returns false if
+// hinted handoff is disabled or the hint couldn't be written.
+func (r *Replicator) storeHint(targetNode string, request *ReplicationRequest) bool {
+	if r.hints == nil {
+		return false
+	}
+
+	if _, err := r.hints.Add(targetNode, request); err != nil {
+		fmt.Printf("❌ Failed to store hint for %s (key %s): %v\n", targetNode, request.Key, err)
+		return false
+	}
+
+	fmt.Printf("📮 Stored hint for %s: %s %s\n", targetNode, request.Operation, request.Key)
+	return true
+}
+
+// replayHints delivers the hints pending for a node, oldest first. Delivery
+// stops at the first failure so mutations of the same key stay in order.
+// Hints older than TombstoneGracePeriod are dropped: the tombstones that
+// would stop them from bringing deleted keys back may be gone.
+func (r *Replicator) replayHints(nodeID string) {
+	if r.hints == nil {
+		return
+	}
+
+	// Only one replay per target at a time
+	r.hintMutex.Lock()
+	if r.replayingHints[nodeID] {
+		r.hintMutex.Unlock()
+		return
+	}
+	r.replayingHints[nodeID] = true
+	r.hintMutex.Unlock()
+
+	defer func() {
+		r.hintMutex.Lock()
+		delete(r.replayingHints, nodeID)
+		r.hintMutex.Unlock()
+	}()
+
+	targetNode := r.memberNode(nodeID)
+	if targetNode == nil {
+		return
+	}
+
+	hints, err := r.hints.ForTarget(nodeID)
+	if err != nil {
+		fmt.Printf("❌ Failed to load hints for %s: %v\n", nodeID, err)
+		return
+	}
+	if len(hints) == 0 {
+		return
+	}
+
+	fmt.Printf("📬 Replaying %d hints to %s\n", len(hints), nodeID)
+
+	delivered := 0
+	expiredBefore := r.clock.Now().Add(-TombstoneGracePeriod).UnixNano()
+	for _, hint := range hints {
+		if hint.CreatedAt < expiredBefore {
+			fmt.Printf("🗑️ Dropping expired hint for %s: %s %s\n", nodeID, hint.Request.Operation, hint.Request.Key)
+			if err := r.hints.Remove(hint); err != nil {
+				fmt.Printf("⚠️ Failed to remove expired hint %s: %v\n", hint.ID, err)
+			}
+			continue
+		}
+
+		if !r.replicateToNode(targetNode, r.hintRequest(hint)) {
+			if err := r.hints.RecordAttempt(hint); err != nil {
+				fmt.Printf("⚠️ Failed to record hint attempt for %s: %v\n", nodeID, err)
+			}
+			fmt.Printf("⚠️ Hint replay to %s stopped after %d/%d hints\n", nodeID, delivered, len(hints))
+			return
+		}
+
+		if err := r.hints.Remove(hint); err != nil {
+			fmt.Printf("⚠️ Failed to remove delivered hint %s: %v\n", hint.ID, err)
+		}
+		delivered++
+	}
+
+	fmt.Printf("✅ Delivered %d hints to %s\n", delivered, nodeID)
+}
+
+// hintRequest returns what to deliver for a hint: the hinted mutation, or
+// this node's stored version of the key if that supersedes it (e.g. the
+// tombstone of a later delete), so a replay never brings an old value back
+func (r *Replicator) hintRequest(hint *Hint) *ReplicationRequest {
+	request := hint.Request
+	if request.SourceEvent == nil {
+		return request
+	}
+
+	current, err := r.storage.GetVersion(request.Key)
+	if err != nil {
+		return request
+	}
+
+	hinted := &storage.StorageValue{
+		Timestamp: request.SourceEvent.Timestamp,
+		Metadata: map[string]string{
+			"node_id":  request.SourceEvent.NodeID,
+			"event_id": request.SourceEvent.ID,
+		},
+		VectorClock: request.SourceEvent.VectorClock,
+		Deleted:     request.Operation == "delete",
+	}
+	if !storage.Supersedes(current, hinted) {
+		return request
+	}
+
+	fmt.Printf("⏩ Hint for %s on %s superseded, delivering stored version [%s]\n",
+		request.Key, hint.TargetNode, current.GetVectorClock().String())
+	return r.versionRequest(request.Key, current)
+}
+
+// memberNode returns where to reach a node, as the membership service knows
+// it. Unlike the ring, membership remembers nodes that left, so what they
+// missed still reaches them if they come back.
+func (r *Replicator) memberNode(nodeID string) *node.Node {
+	member, exists := r.members.Member(nodeID)
+	if !exists || member.Address == "" {
+		return nil
+	}
+	return node.NewNode(member.NodeID, member.Address)
+}
+
+// GetHintStats returns pending hints per target node
+func (r *Replicator) GetHintStats() map[string]interface{} {
+	if r.hints == nil {
+		return map[string]interface{}{
+			"enabled": false,
+		}
+	}
+
+	stats := r.hints.Stats()
+	total := 0
+	for _, targetStats := range stats {
+		total += targetStats.Count
+	}
+
+	return map[string]interface{}{
+		"enabled":       true,
+		"pending_hints": total,
+		"targets":       stats,
+	}
+}
@@ -0,0 +1,159 @@
+package replication
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/storage"
+)
+
+func hintKeys(hints []*Hint) []string {
+	keys := make([]string, len(hints))
+	for i, hint := range hints {
+		keys[i] = hint.Request.Key
+	}
+	return keys
+}
+
+func TestHintStoreKeepsWriteOrderPerTarget(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(100, 0))
+	hs, err := NewHintStore(filepath.Join(t.TempDir(), "hints"), clk)
+	if err != nil {
+		t.Fatalf("NewHintStore: %v", err)
+	}
+	defer hs.Close()
+
+	// Hints created at the same instant keep the order they were added in
+	for _, key := range []string{"k1", "k2"} {
+		if _, err := hs.Add("b", &ReplicationRequest{Key: key, Operation: "put"}); err != nil {
+			t.Fatalf("Add(b, %s): %v", key, err)
+		}
+	}
+	if _, err := hs.Add("c", &ReplicationRequest{Key: "other", Operation: "put"}); err != nil {
+		t.Fatalf("Add(c): %v", err)
+	}
+	clk.Advance(time.Second)
+	if _, err := hs.Add("b", &ReplicationRequest{Key: "k3", Operation: "delete"}); err != nil {
+		t.Fatalf("Add(b, k3): %v", err)
+	}
+
+	hints, err := hs.ForTarget("b")
+	if err != nil {
+		t.Fatalf("ForTarget(b): %v", err)
+	}
+	if got, want := hintKeys(hints), []string{"k1", "k2", "k3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hints for b = %v, want %v", got, want)
+	}
+
+	if got, want := hs.Targets(), []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Targets() = %v, want %v", got, want)
+	}
+
+	stats := hs.Stats()
+	if stats["b"].Count != 3 || stats["b"].OldestAgeSeconds != 1 {
+		t.Errorf("stats for b = %+v, want 3 hints, oldest 1s old", stats["b"])
+	}
+
+	// A delivered hint is gone; a failed one keeps its place and counts the attempt
+	if err := hs.Remove(hints[0]); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := hs.RecordAttempt(hints[1]); err != nil {
+		t.Fatalf("RecordAttempt: %v", err)
+	}
+	hints, _ = hs.ForTarget("b")
+	if got, want := hintKeys(hints), []string{"k2", "k3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hints for b after delivering k1 = %v, want %v", got, want)
+	}
+	if hints[0].Attempts != 1 {
+		t.Errorf("attempts on k2 = %d, want 1", hints[0].Attempts)
+	}
+	if stats := hs.Stats(); stats["b"].OldestAttempts != 1 {
+		t.Errorf("stats for b = %+v, want the oldest hint (k2) tried once", stats["b"])
+	}
+}
+
+func TestReplayDropsExpiredHints(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 2, R: 1, W: 1}, "a", "b")
+	a := c.nodes["a"]
+
+	expired := &Hint{
+		ID:         "b/expired",
+		TargetNode: "b",
+		Request:    &ReplicationRequest{Key: "old", Value: "v0", Operation: "put", SourceNode: "a"},
+		CreatedAt:  c.clock.Now().Add(-TombstoneGracePeriod - time.Hour).UnixNano(),
+	}
+	if err := a.hints.put(expired); err != nil {
+		t.Fatalf("put expired hint: %v", err)
+	}
+	a.storeHint("b", &ReplicationRequest{Key: "fresh", Value: "v1", Operation: "put", SourceNode: "a"})
+
+	c.run(func() { a.replayHints("b") })
+
+	if _, err := c.nodes["b"].storage.GetVersion("old"); err != storage.ErrKeyNotFound {
+		t.Errorf("expired hint was delivered to b (err %v)", err)
+	}
+	if value, err := c.nodes["b"].storage.GetVersion("fresh"); err != nil || value.Value != "v1" {
+		t.Errorf("fresh hint on b = %v, %v; want v1", value, err)
+	}
+	if targets := a.hints.Targets(); len(targets) != 0 {
+		t.Errorf("hints left for %v after replay, want none", targets)
+	}
+}
+
+func TestHintRequestDeliversSupersedingVersion(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 2, R: 1, W: 1}, "a", "b")
+	a := c.nodes["a"]
+
+	if err := a.storage.Put("k", "v1"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	events := a.storage.GetEventLog().Events
+	put := &ReplicationRequest{Key: "k", Value: "v1", Operation: "put", SourceNode: "a", SourceEvent: events[len(events)-1]}
+	hint := &Hint{TargetNode: "b", Request: put}
+
+	if got := a.hintRequest(hint); got != put {
+		t.Errorf("hint for the stored version delivers %+v, want the hinted put", got)
+	}
+
+	// A later delete supersedes the hinted put: its tombstone goes instead
+	if err := a.storage.Delete("k"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	got := a.hintRequest(hint)
+	if got.Operation != "delete" {
+		t.Fatalf("hint superseded by a delete delivers a %s, want the tombstone", got.Operation)
+	}
+	tombstone, _ := a.storage.GetVersion("k")
+	if got.SourceEvent.VectorClock.Compare(tombstone.GetVectorClock()) != storage.Equal {
+		t.Errorf("delivered clock [%s], want the tombstone's [%s]", got.SourceEvent.VectorClock, tombstone.GetVectorClock())
+	}
+}
+
+func TestHintsReachOwnerThatLeftAndReturned(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 2, R: 1, W: 1}, "a", "b", "c")
+	a := c.nodes["a"]
+
+	c.network.Unregister("c:8000")
+	a.members.Update("c", "c:8000", "left", 0)
+	if a.ring.GetNode("c") != nil {
+		t.Fatalf("c is still on a's ring after leaving")
+	}
+
+	// Hints for an owner that left are kept and addressed through membership
+	a.storeHint("c", &ReplicationRequest{Key: "k", Value: "v1", Operation: "put", SourceNode: "a"})
+	if target := a.memberNode("c"); target == nil || target.Address != "c:8000" {
+		t.Fatalf("memberNode(c) = %v, want c at c:8000", target)
+	}
+
+	c.nodes["c"].RegisterMemory(c.network)
+	a.members.Update("c", "c:8000", "alive", 1)
+	c.run(func() { c.clock.Sleep(hintRetryInterval) })
+
+	if value, err := c.nodes["c"].storage.GetVersion("k"); err != nil || value.Value != "v1" {
+		t.Errorf("c after returning: %v, %v; want v1", value, err)
+	}
+}
@@ -0,0 +1,120 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dynamodb/internal/node"
+	"dynamodb/internal/storage"
+	"dynamodb/internal/transport"
+)
+
+// memoryService is the service name replicators register on an in-memory network
+const memoryService = "replication"
+
+// RegisterMemory makes this replicator reachable on an in-memory network
+// under its node's address
+func (r *Replicator) RegisterMemory(network *transport.Network) {
+	network.Register(r.currentNode.Address, memoryService, r)
+}
+
+// MemoryTransport delivers replication traffic to replicators registered on
+// an in-memory network, so several nodes can run in one process. Requests and
+// responses are copied through JSON like on the wire, so nodes never share
+// clocks or events.
+type MemoryTransport struct {
+	network *transport.Network
+	from    string
+}
+
+// NewMemoryTransport creates a replication transport for the node at address
+func NewMemoryTransport(network *transport.Network, address string) *MemoryTransport {
+	return &MemoryTransport{network: network, from: address}
+}
+
+// replicator looks up target's replicator, applying the network's faults
+func (t *MemoryTransport) replicator(ctx context.Context, target *node.Node) (*Replicator, error) {
+	handler, err := t.network.Call(ctx, t.from, target.Address, memoryService)
+	if err != nil {
+		return nil, err
+	}
+
+	replicator, ok := handler.(*Replicator)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s handler at %s", memoryService, target.Address)
+	}
+	return replicator, nil
+}
+
+// Replicate applies one mutation on the target replicator
+func (t *MemoryTransport) Replicate(ctx context.Context, target *node.Node, request *ReplicationRequest) (*ReplicationResponse, error) {
+	replicator, err := t.replicator(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied ReplicationRequest
+	if err := copyThroughJSON(request, &copied); err != nil {
+		return nil, err
+	}
+
+	var response ReplicationResponse
+	if err := copyThroughJSON(replicator.HandleReplicationRequest(&copied), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ReplicateBatch applies a batch of mutations on the target replicator
+func (t *MemoryTransport) ReplicateBatch(ctx context.Context, target *node.Node, batch *BatchReplicationRequest) (*BatchReplicationResponse, error) {
+	replicator, err := t.replicator(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied BatchReplicationRequest
+	if err := copyThroughJSON(batch, &copied); err != nil {
+		return nil, err
+	}
+
+	var response BatchReplicationResponse
+	if err := copyThroughJSON(replicator.HandleBatchReplication(&copied), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// FetchVersion reads the target replicator's stored version of key
+func (t *MemoryTransport) FetchVersion(ctx context.Context, target *node.Node, key string) (*storage.StorageValue, error) {
+	replicator, err := t.replicator(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := replicator.storage.GetVersion(key)
+	if err != nil {
+		return nil, nil
+	}
+
+	var version storage.StorageValue
+	if err := copyThroughJSON(value, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// Ping succeeds when the target is registered and reachable
+func (t *MemoryTransport) Ping(ctx context.Context, target *node.Node) error {
+	_, err := t.replicator(ctx, target)
+	return err
+}
+
+// copyThroughJSON deep-copies src into dst using the wire encoding
+func copyThroughJSON(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
@@ -0,0 +1,77 @@
+package replication
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// namespaceSettingPrefix marks the cluster settings that carry namespace
+// overrides ("namespace/user")
+const namespaceSettingPrefix = "namespace/"
+
+// ClusterSettings spreads cluster-wide settings to every node. Gossip
+// implements it.
+type ClusterSettings interface {
+	SetSetting(key, value string)
+	WatchSettings(fn func(key, value string))
+}
+
+// SetClusterSettings shares namespace overrides with every node through
+// settings: overrides configured here reach the other nodes, and overrides
+// configured elsewhere apply here, including ones set before this node joined
+func (r *Replicator) SetClusterSettings(settings ClusterSettings) {
+	r.configMutex.Lock()
+	r.settings = settings
+	r.configMutex.Unlock()
+
+	settings.WatchSettings(r.applySetting)
+}
+
+// ConfigureNamespace overrides N/R/W for a namespace on every node, so every
+// coordinator uses the same owners and quorums for its keys. Zero fields are
+// resolved against this node's defaults before the override spreads. It
+// fails if the override is invalid or there is no way to reach the other
+// nodes.
+func (r *Replicator) ConfigureNamespace(namespace string, override *ReplicationConfig) error {
+	r.configMutex.RLock()
+	settings := r.settings
+	config, err := r.resolveNamespaceConfig(namespace, override)
+	r.configMutex.RUnlock()
+
+	if err != nil {
+		return err
+	}
+	if settings == nil {
+		return fmt.Errorf("namespace overrides can't reach the other nodes without gossip; start every node with the same --namespace-config instead")
+	}
+
+	// Spread the complete N/R/W: zero fields are filled in from this node's
+	// defaults once, so nodes started with other defaults still agree
+	value, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	// Applied here by our own watcher, then on every other node as it spreads
+	settings.SetSetting(namespaceSettingPrefix+namespace, string(value))
+	return nil
+}
+
+// applySetting applies a namespace override learned from the cluster
+// settings; other settings are ignored
+func (r *Replicator) applySetting(key, value string) {
+	namespace, ok := strings.CutPrefix(key, namespaceSettingPrefix)
+	if !ok {
+		return
+	}
+
+	var override ReplicationConfig
+	if err := json.Unmarshal([]byte(value), &override); err != nil {
+		fmt.Printf("⚠️ Ignoring unreadable override for namespace %s: %v\n", namespace, err)
+		return
+	}
+	if err := r.SetNamespaceConfig(namespace, &override); err != nil {
+		fmt.Printf("⚠️ Ignoring override for namespace %s: %v\n", namespace, err)
+	}
+}
@@ -0,0 +1,68 @@
+package replication
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// sharedSettings is a ClusterSettings that hands every setting to the
+// watchers of all replicators sharing it at once
+type sharedSettings struct {
+	values   map[string]string
+	watchers []func(key, value string)
+}
+
+func (s *sharedSettings) SetSetting(key, value string) {
+	s.values[key] = value
+	for _, fn := range s.watchers {
+		fn(key, value)
+	}
+}
+
+func (s *sharedSettings) WatchSettings(fn func(key, value string)) {
+	s.watchers = append(s.watchers, fn)
+}
+
+func TestConfigureNamespaceSpreadsResolvedConfig(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b")
+	a, b := c.nodes["a"], c.nodes["b"]
+
+	// b was started with other defaults
+	b.config = &ReplicationConfig{N: 2, R: 1, W: 1}
+
+	settings := &sharedSettings{values: make(map[string]string)}
+	a.SetClusterSettings(settings)
+	b.SetClusterSettings(settings)
+
+	if err := a.ConfigureNamespace("user", &ReplicationConfig{W: 3}); err != nil {
+		t.Fatalf("ConfigureNamespace: %v", err)
+	}
+
+	var spread ReplicationConfig
+	if err := json.Unmarshal([]byte(settings.values["namespace/user"]), &spread); err != nil {
+		t.Fatalf("unreadable setting %q: %v", settings.values["namespace/user"], err)
+	}
+	want := ReplicationConfig{N: 3, R: 2, W: 3}
+	if spread != want {
+		t.Errorf("spread %+v, want %+v resolved on a", spread, want)
+	}
+
+	for id, replicator := range c.nodes {
+		if got := replicator.ConfigForKey("user:1"); got != want {
+			t.Errorf("%s uses %+v for user:1, want %+v", id, got, want)
+		}
+	}
+}
+
+func TestConfigureNamespaceRejectsInvalidOverride(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a")
+	settings := &sharedSettings{values: make(map[string]string)}
+	c.nodes["a"].SetClusterSettings(settings)
+
+	if err := c.nodes["a"].ConfigureNamespace("user", &ReplicationConfig{R: 4}); err == nil {
+		t.Errorf("ConfigureNamespace accepted R=4 with N=3")
+	}
+	if len(settings.values) != 0 {
+		t.Errorf("invalid override was spread: %v", settings.values)
+	}
+}
@@ -0,0 +1,536 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/storage"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	queueBatchSize  = 64                     // Mutations sent to a peer per request
+	queueMinBackoff = 100 * time.Millisecond // First retry delay after a failed batch
+	queueMaxBackoff = 30 * time.Second       // Retry delay cap
+)
+
+// QueueEntry is a mutation waiting to be replicated to one peer
+type QueueEntry struct {
+	Seq        uint64              `json:"seq"`
+	Peer       string              `json:"peer"`
+	Request    *ReplicationRequest `json:"request"`
+	EnqueuedAt int64               `json:"enqueued_at"` // Unix nanoseconds
+}
+
+// PeerQueueStats describes the replication backlog for one peer
+type PeerQueueStats struct {
+	Depth               int     `json:"depth"`
+	LagSeconds          float64 `json:"lag_seconds"` // Age of the oldest queued mutation
+	Delivered           int64   `json:"delivered"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	BackoffMs           int64   `json:"backoff_ms"`
+	LastError           string  `json:"last_error,omitempty"`
+	LastDelivered       int64   `json:"last_delivered,omitempty"`
+}
+
+// deliverFunc sends a batch to a peer and returns how many mutations, from
+// the start of the batch, the peer applied. ctx is cancelled when the queue
+// closes.
+type deliverFunc func(ctx context.Context, peer string, batch []*ReplicationRequest) (int, error)
+
+// ReplicationQueue is a durable per-peer queue of mutations that are
+// replicated in the background. Each peer has one worker that sends its
+// mutations in batches and in enqueue order (so per-key order is kept),
+// retrying with exponential backoff. Entries live in LevelDB and are picked
+// up again after a restart.
+type ReplicationQueue struct {
+	db      *leveldb.DB
+	deliver deliverFunc
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	seq     uint64
+	workers map[string]*queueWorker
+	closed  bool
+
+	// Cancelled by Close; running counts the workers Close waits for
+	ctx     context.Context
+	stop    context.CancelFunc
+	running sync.WaitGroup
+}
+
+// queueWorker delivers one peer's mutations
+type queueWorker struct {
+	peer  string
+	wake  chan struct{} // New mutations were queued
+	kick  chan struct{} // Peer came back; cut the current backoff short
+	stats PeerQueueStats
+}
+
+// NewReplicationQueue opens (or creates) the queue database at path and
+// resumes delivery of any mutations left from a previous run. Workers and
+// their backoff run on clk (nil = wall clock).
+func NewReplicationQueue(path string, deliver deliverFunc, clk clock.Clock) (*ReplicationQueue, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replication queue at %s: %v", path, err)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	q := &ReplicationQueue{
+		db:      db,
+		deliver: deliver,
+		clock:   clock.OrReal(clk),
+		workers: make(map[string]*queueWorker),
+		ctx:     ctx,
+		stop:    stop,
+	}
+
+	// Continue the sequence and restart workers for pending peers
+	pending := make(map[string]int)
+	iter := db.NewIterator(nil, nil)
+	for iter.Next() {
+		peer, seq, ok := parseQueueKey(string(iter.Key()))
+		if !ok {
+			continue
+		}
+		if seq > q.seq {
+			q.seq = seq
+		}
+		pending[peer]++
+	}
+	iter.Release()
+
+	peers := make([]string, 0, len(pending))
+	for peer := range pending {
+		peers = append(peers, peer)
+	}
+	sort.Strings(peers)
+	for _, peer := range peers {
+		fmt.Printf("📦 Resuming %d queued replications to %s\n", pending[peer], peer)
+		q.worker(peer).signal()
+	}
+
+	fmt.Printf("📦 Replication queue initialized at %s\n", path)
+	return q, nil
+}
+
+// Enqueue queues a mutation for a peer
+func (q *ReplicationQueue) Enqueue(peer string, request *ReplicationRequest) error {
+	// The event delta is computed when the batch is sent
+	queued := *request
+	queued.Events = nil
+
+	// Hold the lock across the write so entries become visible to the
+	// worker in sequence order
+	q.mu.Lock()
+	q.seq++
+	entry := &QueueEntry{
+		Seq:        q.seq,
+		Peer:       peer,
+		Request:    &queued,
+		EnqueuedAt: q.clock.Now().UnixNano(),
+	}
+
+	data, err := json.Marshal(entry)
+	if err == nil {
+		err = q.db.Put([]byte(queueKey(peer, entry.Seq)), data, nil)
+	}
+	q.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	q.worker(peer).signal()
+	return nil
+}
+
+// Kick retries a peer's backlog right away, e.g. after it recovered
+func (q *ReplicationQueue) Kick(peer string) {
+	q.mu.Lock()
+	w, exists := q.workers[peer]
+	q.mu.Unlock()
+
+	if exists {
+		select {
+		case w.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Stats returns the backlog per peer
+func (q *ReplicationQueue) Stats() map[string]*PeerQueueStats {
+	now := q.clock.Now().UnixNano()
+
+	// Depth and lag come from the stored entries
+	stats := make(map[string]*PeerQueueStats)
+	iter := q.db.NewIterator(nil, nil)
+	for iter.Next() {
+		peer, _, ok := parseQueueKey(string(iter.Key()))
+		if !ok {
+			continue
+		}
+
+		peerStats, exists := stats[peer]
+		if !exists {
+			peerStats = &PeerQueueStats{}
+			stats[peer] = peerStats
+
+			// Keys are ordered, so the first entry seen is the oldest
+			var entry QueueEntry
+			if err := json.Unmarshal(iter.Value(), &entry); err == nil {
+				peerStats.LagSeconds = float64(now-entry.EnqueuedAt) / float64(time.Second)
+			}
+		}
+		peerStats.Depth++
+	}
+	iter.Release()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for peer, w := range q.workers {
+		peerStats, exists := stats[peer]
+		if !exists {
+			peerStats = &PeerQueueStats{}
+			stats[peer] = peerStats
+		}
+		peerStats.Delivered = w.stats.Delivered
+		peerStats.ConsecutiveFailures = w.stats.ConsecutiveFailures
+		peerStats.BackoffMs = w.stats.BackoffMs
+		peerStats.LastError = w.stats.LastError
+		peerStats.LastDelivered = w.stats.LastDelivered
+	}
+
+	return stats
+}
+
+// Close stops the workers, cancelling their deliveries, and closes the queue
+// database once they have all returned. Undelivered mutations stay queued
+// for the next start.
+func (q *ReplicationQueue) Close() error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	q.stop()
+	q.running.Wait()
+	return q.db.Close()
+}
+
+// worker returns the peer's worker, starting it if needed
+func (q *ReplicationQueue) worker(peer string) *queueWorker {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	w, exists := q.workers[peer]
+	if !exists {
+		w = &queueWorker{
+			peer: peer,
+			wake: make(chan struct{}, 1),
+			kick: make(chan struct{}, 1),
+		}
+		q.workers[peer] = w
+		q.clock.Go(func() { q.start(w) })
+	}
+	return w
+}
+
+// start runs a worker unless the queue closed before it got to start; Close
+// waits for the workers that did
+func (q *ReplicationQueue) start(w *queueWorker) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.running.Add(1)
+	q.mu.Unlock()
+
+	defer q.running.Done()
+	q.run(w)
+}
+
+func (w *queueWorker) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers a peer's queued mutations until the queue is closed
+func (q *ReplicationQueue) run(w *queueWorker) {
+	backoff := time.Duration(0)
+
+	for {
+		if backoff > 0 {
+			select {
+			case <-q.clock.After(backoff):
+			case <-w.kick:
+			case <-q.ctx.Done():
+				return
+			}
+		}
+
+		entries, err := q.peek(w.peer, queueBatchSize)
+		if err != nil {
+			fmt.Printf("❌ Failed to read replication queue for %s: %v\n", w.peer, err)
+		}
+		if len(entries) == 0 {
+			backoff = 0
+			select {
+			case <-w.wake:
+				continue
+			case <-q.ctx.Done():
+				return
+			}
+		}
+
+		batch := make([]*ReplicationRequest, len(entries))
+		for i, entry := range entries {
+			batch[i] = entry.Request
+		}
+
+		applied, err := q.deliver(q.ctx, w.peer, batch)
+		if applied > len(entries) {
+			applied = len(entries)
+		}
+		for _, entry := range entries[:applied] {
+			if delErr := q.db.Delete([]byte(queueKey(entry.Peer, entry.Seq)), nil); delErr != nil {
+				fmt.Printf("⚠️ Failed to remove delivered queue entry %d for %s: %v\n", entry.Seq, w.peer, delErr)
+			}
+		}
+
+		if err == nil && applied < len(entries) {
+			err = fmt.Errorf("peer applied %d of %d mutations", applied, len(entries))
+		}
+
+		if err != nil {
+			backoff = nextBackoff(backoff)
+		} else {
+			backoff = 0
+		}
+
+		q.mu.Lock()
+		w.stats.Delivered += int64(applied)
+		if applied > 0 {
+			w.stats.LastDelivered = q.clock.Now().Unix()
+		}
+		if err != nil {
+			w.stats.ConsecutiveFailures++
+			w.stats.LastError = err.Error()
+		} else {
+			w.stats.ConsecutiveFailures = 0
+			w.stats.LastError = ""
+		}
+		w.stats.BackoffMs = backoff.Milliseconds()
+		q.mu.Unlock()
+
+		if err != nil {
+			fmt.Printf("⚠️ Async replication to %s failed (%v), retrying in %v\n", w.peer, err, backoff)
+		} else {
+			fmt.Printf("📦 Async replication delivered %d mutations to %s\n", applied, w.peer)
+		}
+	}
+}
+
+// peek returns up to limit of the oldest entries queued for a peer
+func (q *ReplicationQueue) peek(peer string, limit int) ([]*QueueEntry, error) {
+	iter := q.db.NewIterator(util.BytesPrefix([]byte(peer+"/")), nil)
+	defer iter.Release()
+
+	entries := make([]*QueueEntry, 0, limit)
+	for len(entries) < limit && iter.Next() {
+		var entry QueueEntry
+		if err := json.Unmarshal(iter.Value(), &entry); err != nil {
+			fmt.Printf("⚠️ Dropping unreadable queue entry %s: %v\n", string(iter.Key()), err)
+			q.db.Delete(iter.Key(), nil)
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, iter.Error()
+}
+
+// nextBackoff doubles the retry delay within [queueMinBackoff, queueMaxBackoff]
+func nextBackoff(current time.Duration) time.Duration {
+	if current < queueMinBackoff {
+		return queueMinBackoff
+	}
+	if current*2 > queueMaxBackoff {
+		return queueMaxBackoff
+	}
+	return current * 2
+}
+
+func queueKey(peer string, seq uint64) string {
+	return fmt.Sprintf("%s/%020d", peer, seq)
+}
+
+func parseQueueKey(key string) (string, uint64, bool) {
+	idx := strings.LastIndex(key, "/")
+	if idx < 0 {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseUint(key[idx+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return key[:idx], seq, true
+}
+
+// BatchReplicationRequest carries queued mutations for one peer, in order
+type BatchReplicationRequest struct {
+	SourceNode string                `json:"source_node"`
+	Requests   []*ReplicationRequest `json:"requests"`
+}
+
+// BatchReplicationResponse reports how many mutations of a batch were applied.
+// Mutations are applied in order and the first failure stops the batch.
+type BatchReplicationResponse struct {
+	Applied      int                  `json:"applied"`
+	NodeID       string               `json:"node_id"`
+	Error        string               `json:"error,omitempty"`
+	UpdatedClock *storage.VectorClock `json:"updated_clock,omitempty"`
+	Timestamp    int64                `json:"timestamp"`
+}
+
+// SetAsyncReplication enables or disables the background queue for writes
+// that only need one acknowledgement
+func (r *Replicator) SetAsyncReplication(enabled bool) {
+	r.configMutex.Lock()
+	defer r.configMutex.Unlock()
+	r.asyncReplication = enabled
+}
+
+// useQueue reports whether a write should replicate through the queue: it
+// only needs one acknowledgement and this node holds a local copy
+func (r *Replicator) useQueue(key string, plan *quorumPlan) bool {
+	r.configMutex.RLock()
+	enabled := r.asyncReplication
+	r.configMutex.RUnlock()
+
+	return enabled && r.queue != nil && plan.required <= 1 && r.IsOwner(key)
+}
+
+// enqueueToOwners queues a mutation (already applied locally) for the key's
+// other owners and returns the peers it was queued for
+func (r *Replicator) enqueueToOwners(key string, n int, request ReplicationRequest) []string {
+	queued := []string{}
+	for _, owner := range r.ring.GetNodesForKey(key, n) {
+		if owner.ID == r.currentNode.ID {
+			continue
+		}
+
+		if err := r.queue.Enqueue(owner.ID, &request); err != nil {
+			fmt.Printf("❌ Failed to queue %s %s for %s: %v\n", request.Operation, key, owner.ID, err)
+			if r.storeHint(owner.ID, &request) {
+				fmt.Printf("📮 Kept a hint for %s instead\n", owner.ID)
+			}
+			continue
+		}
+		queued = append(queued, owner.ID)
+	}
+	return queued
+}
+
+// deliverBatch sends a batch of queued mutations to a peer
+func (r *Replicator) deliverBatch(parent context.Context, peer string, batch []*ReplicationRequest) (int, error) {
+	targetNode := r.memberNode(peer)
+	if targetNode == nil {
+		return 0, fmt.Errorf("%s is not a known member", peer)
+	}
+	if !r.isNodeAlive(peer) {
+		return 0, fmt.Errorf("%s is down", peer)
+	}
+
+	// Ship the unacknowledged event delta once, with the first mutation
+	requests := make([]*ReplicationRequest, len(batch))
+	for i, queued := range batch {
+		request := *queued
+		if i == 0 {
+			request.Events = r.deltaFor(peer, request.SourceEvent)
+		} else if request.SourceEvent != nil {
+			request.Events = []*storage.Event{request.SourceEvent}
+		}
+		requests[i] = &request
+	}
+
+	ctx, cancel := r.clock.WithTimeout(parent, 5*time.Second)
+	defer cancel()
+
+	response, err := r.transport.ReplicateBatch(ctx, targetNode, &BatchReplicationRequest{
+		SourceNode: r.currentNode.ID,
+		Requests:   requests,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.recordAck(peer, response.UpdatedClock)
+
+	if response.Error != "" {
+		return response.Applied, fmt.Errorf("%s", response.Error)
+	}
+	return response.Applied, nil
+}
+
+// HandleBatchReplication applies a batch of queued mutations in order
+func (r *Replicator) HandleBatchReplication(batch *BatchReplicationRequest) *BatchReplicationResponse {
+	response := &BatchReplicationResponse{
+		NodeID:    r.currentNode.ID,
+		Timestamp: r.clock.Now().Unix(),
+	}
+
+	for _, req := range batch.Requests {
+		result := r.HandleReplicationRequest(req)
+		if !result.Success {
+			response.Error = fmt.Sprintf("%s %s: %s", req.Operation, req.Key, result.Error)
+			break
+		}
+		response.Applied++
+	}
+
+	response.UpdatedClock = r.storage.CurrentClock()
+	return response
+}
+
+// GetQueueStats returns the async replication backlog per peer
+func (r *Replicator) GetQueueStats() map[string]interface{} {
+	r.configMutex.RLock()
+	enabled := r.asyncReplication
+	r.configMutex.RUnlock()
+
+	if r.queue == nil {
+		return map[string]interface{}{
+			"enabled": false,
+		}
+	}
+
+	peers := r.queue.Stats()
+	depth := 0
+	maxLag := 0.0
+	for _, peerStats := range peers {
+		depth += peerStats.Depth
+		if peerStats.LagSeconds > maxLag {
+			maxLag = peerStats.LagSeconds
+		}
+	}
+
+	return map[string]interface{}{
+		"enabled":         enabled,
+		"queue_depth":     depth,
+		"max_lag_seconds": maxLag,
+		"peers":           peers,
+	}
+}
@@ -0,0 +1,294 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+)
+
+// recordingPeer is a deliverFunc target that fails a set number of batches
+// and then applies up to limit mutations per batch
+type recordingPeer struct {
+	mu        sync.Mutex
+	failures  int
+	limit     int
+	delivered []string
+	batches   int
+}
+
+func (p *recordingPeer) deliver(ctx context.Context, peer string, batch []*ReplicationRequest) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.batches++
+	if p.failures > 0 {
+		p.failures--
+		return 0, errors.New("peer unreachable")
+	}
+
+	applied := len(batch)
+	if p.limit > 0 && applied > p.limit {
+		applied = p.limit
+	}
+	for _, request := range batch[:applied] {
+		p.delivered = append(p.delivered, request.Key)
+	}
+	return applied, nil
+}
+
+func (p *recordingPeer) keys() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]string(nil), p.delivered...)
+}
+
+func newTestQueue(t *testing.T, path string, peer *recordingPeer, clk *clock.Simulated) *ReplicationQueue {
+	t.Helper()
+
+	q, err := NewReplicationQueue(path, peer.deliver, clk)
+	if err != nil {
+		t.Fatalf("NewReplicationQueue: %v", err)
+	}
+	return q
+}
+
+func enqueueKeys(t *testing.T, q *ReplicationQueue, peer string, keys ...string) {
+	t.Helper()
+
+	for _, key := range keys {
+		if err := q.Enqueue(peer, &ReplicationRequest{Key: key, Value: "v", Operation: "put"}); err != nil {
+			t.Fatalf("Enqueue(%s): %v", key, err)
+		}
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	tests := []struct {
+		current time.Duration
+		want    time.Duration
+	}{
+		{current: 0, want: queueMinBackoff},
+		{current: queueMinBackoff / 2, want: queueMinBackoff},
+		{current: queueMinBackoff, want: 2 * queueMinBackoff},
+		{current: 10 * time.Second, want: 20 * time.Second},
+		{current: 20 * time.Second, want: queueMaxBackoff},
+		{current: queueMaxBackoff, want: queueMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current.String(), func(t *testing.T) {
+			if got := nextBackoff(tt.current); got != tt.want {
+				t.Errorf("nextBackoff(%v) = %v, want %v", tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueueBacksOffUntilDelivered(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	peer := &recordingPeer{failures: 3}
+	q := newTestQueue(t, filepath.Join(t.TempDir(), "queue"), peer, clk)
+	defer q.Close()
+
+	enqueueKeys(t, q, "p1", "k1")
+	clk.Settle()
+
+	// Each failure doubles the wait before the next attempt
+	steps := []struct {
+		wait         time.Duration
+		failures     int
+		backoff      time.Duration
+		depth        int
+		deliveredAll bool
+	}{
+		{wait: 0, failures: 1, backoff: 100 * time.Millisecond, depth: 1},
+		{wait: 100 * time.Millisecond, failures: 2, backoff: 200 * time.Millisecond, depth: 1},
+		{wait: 200 * time.Millisecond, failures: 3, backoff: 400 * time.Millisecond, depth: 1},
+		{wait: 400 * time.Millisecond, failures: 0, backoff: 0, depth: 0, deliveredAll: true},
+	}
+	for i, step := range steps {
+		clk.Advance(step.wait)
+
+		stats := q.Stats()["p1"]
+		if stats == nil {
+			t.Fatalf("step %d: no stats for p1", i)
+		}
+		if stats.ConsecutiveFailures != step.failures || stats.BackoffMs != step.backoff.Milliseconds() || stats.Depth != step.depth {
+			t.Errorf("step %d: failures %d, backoff %dms, depth %d, want %d, %dms, %d",
+				i, stats.ConsecutiveFailures, stats.BackoffMs, stats.Depth, step.failures, step.backoff.Milliseconds(), step.depth)
+		}
+		if step.deliveredAll && stats.Delivered != 1 {
+			t.Errorf("step %d: delivered %d, want 1", i, stats.Delivered)
+		}
+	}
+
+	if peer.batches != 4 {
+		t.Errorf("peer received %d batches, want 4", peer.batches)
+	}
+}
+
+func TestQueueKick(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	peer := &recordingPeer{failures: 5}
+	q := newTestQueue(t, filepath.Join(t.TempDir(), "queue"), peer, clk)
+	defer q.Close()
+
+	enqueueKeys(t, q, "p1", "k1")
+	for i := 0; i < 4; i++ {
+		clk.Advance(nextBackoff(time.Duration(q.Stats()["p1"].BackoffMs) * time.Millisecond))
+	}
+
+	// The peer is back: a kick retries without waiting out the backoff
+	peer.mu.Lock()
+	peer.failures = 0
+	peer.mu.Unlock()
+	q.Kick("p1")
+	clk.Settle()
+
+	if got := peer.keys(); !reflect.DeepEqual(got, []string{"k1"}) {
+		t.Errorf("delivered %v after a kick, want [k1]", got)
+	}
+}
+
+func TestQueueKeepsOrderAcrossPartialBatches(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	peer := &recordingPeer{limit: 2}
+	q := newTestQueue(t, filepath.Join(t.TempDir(), "queue"), peer, clk)
+	defer q.Close()
+
+	enqueueKeys(t, q, "p1", "a", "b", "c", "d", "e")
+	for i := 0; i < 5; i++ {
+		clk.Advance(queueMaxBackoff)
+	}
+
+	if got, want := peer.keys(), []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+	if depth := q.Stats()["p1"].Depth; depth != 0 {
+		t.Errorf("depth = %d, want 0", depth)
+	}
+}
+
+func TestQueueResumesAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue")
+	clk := clock.NewSimulated(time.Unix(0, 0))
+
+	down := &recordingPeer{failures: 1000}
+	q := newTestQueue(t, path, down, clk)
+	enqueueKeys(t, q, "p1", "a", "b")
+	enqueueKeys(t, q, "p2", "c")
+	clk.Settle()
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(down.keys()) != 0 {
+		t.Fatalf("delivered %v to a peer that was down", down.keys())
+	}
+
+	// The reopened queue delivers what was left, without being asked
+	up := &recordingPeer{}
+	q = newTestQueue(t, path, up, clk)
+	defer q.Close()
+	clk.Settle()
+
+	got := up.keys()
+	if len(got) != 3 {
+		t.Fatalf("delivered %v after restart, want a, b and c", got)
+	}
+	order := make(map[string]int)
+	for i, key := range got {
+		order[key] = i
+	}
+	if order["a"] > order["b"] {
+		t.Errorf("delivered %v: p1's mutations out of order", got)
+	}
+
+	// New entries continue the sequence instead of reusing old keys
+	enqueueKeys(t, q, "p1", "d")
+	clk.Settle()
+	if got := up.keys(); got[len(got)-1] != "d" {
+		t.Errorf("delivered %v, want d last", got)
+	}
+	if q.seq != 4 {
+		t.Errorf("sequence = %d after restart and one enqueue, want 4", q.seq)
+	}
+}
+
+// stallingPeer holds every batch until the delivery is cancelled
+type stallingPeer struct {
+	started  chan struct{}
+	returned chan struct{}
+}
+
+func (p *stallingPeer) deliver(ctx context.Context, peer string, batch []*ReplicationRequest) (int, error) {
+	close(p.started)
+	<-ctx.Done()
+	close(p.returned)
+	return 0, ctx.Err()
+}
+
+func TestQueueCloseWaitsForWorkers(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	peer := &stallingPeer{started: make(chan struct{}), returned: make(chan struct{})}
+	q, err := NewReplicationQueue(filepath.Join(t.TempDir(), "queue"), peer.deliver, clk)
+	if err != nil {
+		t.Fatalf("NewReplicationQueue: %v", err)
+	}
+
+	if err := q.Enqueue("p1", &ReplicationRequest{Key: "k1", Value: "v", Operation: "put"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	clk.Settle()
+	<-peer.started
+
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case <-peer.returned:
+	default:
+		t.Fatal("Close returned while a delivery was still running")
+	}
+}
+
+func TestQueueCloseBeforeWorkersStart(t *testing.T) {
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	peer := &recordingPeer{}
+	q := newTestQueue(t, filepath.Join(t.TempDir(), "queue"), peer, clk)
+
+	// The worker is queued on the clock but hasn't started yet
+	enqueueKeys(t, q, "p1", "k1")
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	clk.Settle()
+
+	if got := peer.keys(); len(got) != 0 {
+		t.Errorf("worker delivered %v after Close", got)
+	}
+}
+
+func TestSingleAckWritesQueueOnlyWhenEnabled(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		c := newTestCluster(t, ReplicationConfig{N: 3, R: 1, W: 1}, "a", "b", "c")
+		for _, replicator := range c.nodes {
+			replicator.SetAsyncReplication(enabled)
+		}
+
+		owners, _ := c.owners("user:1")
+		result, err := c.write(owners[0], "user:1", "v1")
+		if err != nil {
+			t.Fatalf("write with the queue enabled: %t: %v", enabled, err)
+		}
+		if queued := len(result.QueuedNodes) > 0; queued != enabled {
+			t.Errorf("queue enabled: %t, write queued for %v", enabled, result.QueuedNodes)
+		}
+	}
+}
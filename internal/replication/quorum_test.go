@@ -0,0 +1,159 @@
+package replication
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"dynamodb/internal/storage"
+)
+
+func TestWriteBelowQuorum(t *testing.T) {
+	tests := []struct {
+		name   string
+		down   func(c *testCluster, id string) // How b and c are unavailable
+		opts   *ConsistencyOptions
+		status WriteStatus
+		fails  bool
+		stored bool // Whether a keeps its copy
+	}{
+		{
+			name:   "known dead",
+			down:   func(c *testCluster, id string) { c.crash(id) },
+			status: WriteStatusRejected,
+			fails:  true,
+		},
+		{
+			name:   "unreachable",
+			down:   func(c *testCluster, id string) { c.network.Unregister(id + ":8000") },
+			status: WriteStatusPartial,
+			fails:  true,
+			stored: true,
+		},
+		{
+			name:   "unreachable, partial writes accepted",
+			down:   func(c *testCluster, id string) { c.network.Unregister(id + ":8000") },
+			opts:   &ConsistencyOptions{AcceptPartial: true},
+			status: WriteStatusAccepted,
+			stored: true,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, operation := range []string{"put", "delete"} {
+			t.Run(tt.name+"/"+operation, func(t *testing.T) {
+				c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c")
+				tt.down(c, "b")
+				tt.down(c, "c")
+
+				var result *WriteResult
+				var err error
+				c.run(func() {
+					if operation == "put" {
+						result, err = c.nodes["a"].WriteWithReplication("user:1", "v1", tt.opts)
+					} else {
+						result, err = c.nodes["a"].DeleteWithReplication("user:1", tt.opts)
+					}
+				})
+
+				var quorumErr *QuorumError
+				if tt.fails && !errors.As(err, &quorumErr) || !tt.fails && err != nil {
+					t.Fatalf("error = %v, want a QuorumError: %t", err, tt.fails)
+				}
+				if quorumErr != nil && quorumErr.Result != result {
+					t.Errorf("QuorumError doesn't carry the write's result")
+				}
+				if result == nil || result.Status != tt.status || result.QuorumAchieved {
+					t.Fatalf("result = %+v, want status %s without quorum", result, tt.status)
+				}
+
+				// A write that reached some replicas isn't rolled back
+				_, readErr := c.nodes["a"].storage.GetVersion("user:1")
+				if stored := readErr == nil; stored != tt.stored {
+					t.Errorf("a holds the write: %t, want %t", stored, tt.stored)
+				}
+			})
+		}
+	}
+}
+
+func TestWriteAtQuorumIsDurable(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c")
+	c.network.Unregister("c:8000")
+
+	result, err := c.write("a", "user:1", "v1")
+	if err != nil || result.Status != WriteStatusDurable || !result.QuorumAchieved {
+		t.Errorf("write with 2 of 3 replicas = %+v, %v; want durable", result, err)
+	}
+	if result != nil && (result.OwnerAcks != 2 || result.StandInAcks != 0) {
+		t.Errorf("%d owner and %d stand-in acks, want 2 owner acks", result.OwnerAcks, result.StandInAcks)
+	}
+}
+
+func TestReadRepairsStaleReplicas(t *testing.T) {
+	tests := []struct {
+		name    string
+		diverge func(c *testCluster) // Leaves b ahead of a and c
+		missing bool                 // Whether the read finds the key deleted
+	}{
+		{name: "newer value", diverge: func(c *testCluster) { c.nodes["b"].storage.Put("user:1", "v2") }},
+		{name: "delete", diverge: func(c *testCluster) { c.nodes["b"].storage.Delete("user:1") }, missing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c")
+			c.run(func() {
+				c.nodes["a"].WriteWithReplication("user:1", "v1", &ConsistencyOptions{Level: ConsistencyAll})
+			})
+			tt.diverge(c)
+			want, _ := c.nodes["b"].storage.GetVersion("user:1")
+
+			var result *ReadResult
+			var err error
+			c.run(func() {
+				result, err = c.nodes["a"].ReadWithQuorum("user:1", &ConsistencyOptions{Level: ConsistencyAll})
+			})
+			if tt.missing && err != storage.ErrKeyNotFound {
+				t.Errorf("read = %+v, %v; want ErrKeyNotFound", result, err)
+			}
+			if !tt.missing && (err != nil || result.Value != want.Value || result.Winner != "b") {
+				t.Errorf("read = %+v, %v; want b's %s", result, err, want.Value)
+			}
+
+			// Repair runs in the background once every replica has answered
+			c.run(func() { c.clock.Sleep(time.Second) })
+			for _, id := range []string{"a", "c"} {
+				got, err := c.nodes[id].storage.GetVersion("user:1")
+				if err != nil || got.Deleted != want.Deleted || got.Value != want.Value ||
+					got.GetVectorClock().Compare(want.GetVectorClock()) != storage.Equal {
+					t.Errorf("%s holds %+v, %v after the read; want b's version %+v", id, got, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadRepairLeavesConcurrentVersions(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c")
+	c.run(func() {
+		c.nodes["a"].WriteWithReplication("user:1", "v1", &ConsistencyOptions{Level: ConsistencyAll})
+	})
+	c.nodes["b"].storage.Put("user:1", "from-b")
+	c.nodes["c"].storage.Put("user:1", "from-c")
+
+	var result *ReadResult
+	c.run(func() {
+		result, _ = c.nodes["a"].ReadWithQuorum("user:1", &ConsistencyOptions{Level: ConsistencyAll})
+	})
+	if result == nil || len(result.Siblings) != 1 {
+		t.Fatalf("read = %+v, want the winner and one sibling", result)
+	}
+
+	c.run(func() { c.clock.Sleep(time.Second) })
+	for id, want := range map[string]string{"a": "v1", "b": "from-b", "c": "from-c"} {
+		if got, _ := c.nodes[id].storage.GetVersion("user:1"); got == nil || got.Value != want {
+			t.Errorf("%s holds %+v after the read, want %s left alone", id, got, want)
+		}
+	}
+}
@@ -1,27 +1,28 @@
 package replication
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"sort"
 	"sync"
 	"time"
 
+	"dynamodb/internal/clock"
+	"dynamodb/internal/membership"
 	"dynamodb/internal/node"
 	"dynamodb/internal/ring"
 	"dynamodb/internal/storage"
 )
 
-// HealthStatus represents the health state of a node
-type HealthStatus struct {
-	NodeID       string        `json:"node_id"`
-	IsAlive      bool          `json:"is_alive"`
-	LastChecked  time.Time     `json:"last_checked"`
-	ResponseTime time.Duration `json:"response_time"`
-	FailureCount int           `json:"failure_count"`
-}
+// hintRetryInterval is how often hints for alive targets are retried
+const hintRetryInterval = 3 * time.Second
+
+// tombstoneGCInterval is how often tombstones past the grace period are purged
+const tombstoneGCInterval = time.Hour
+
+// TombstoneGracePeriod is how long a delete's tombstone is kept. A replica
+// that is down for longer can bring deleted keys back when it returns.
+const TombstoneGracePeriod = 10 * 24 * time.Hour
 
 // ReplicationRequest represents a request to replicate data to another node
 type ReplicationRequest struct {
@@ -30,10 +31,13 @@ type ReplicationRequest struct {
 	Operation  string `json:"operation"` // "put", "delete"
 	SourceNode string `json:"source_node"`
 	Timestamp  int64  `json:"timestamp"`
-	// Vector clock synchronization
-	EventLog    *storage.EventLog    `json:"event_log,omitempty"`
+	// Vector clock synchronization: the coordinator's clock plus the events
+	// the target hasn't acknowledged yet (always including SourceEvent)
+	Events      []*storage.Event     `json:"events,omitempty"`
 	VectorClock *storage.VectorClock `json:"vector_clock,omitempty"`
 	SourceEvent *storage.Event       `json:"source_event,omitempty"`
+	// Set when the receiver is a stand-in holding the data for a dead owner
+	HintedFor string `json:"hinted_for,omitempty"`
 }
 
 // ReplicationResponse represents the response from a replication request
@@ -49,177 +53,299 @@ type ReplicationResponse struct {
 
 // WriteResult represents the result of a distributed write operation
 type WriteResult struct {
-	Key              string   `json:"key"`
-	Value            string   `json:"value"`
-	SuccessfulNodes  []string `json:"successful_nodes"`
-	FailedNodes      []string `json:"failed_nodes"`
-	ReplicationLevel int      `json:"replication_level"`
-	QuorumAchieved   bool     `json:"quorum_achieved"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// Vector clock of the version the write created
+	VectorClock     *storage.VectorClock `json:"vector_clock,omitempty"`
+	SuccessfulNodes []string             `json:"successful_nodes"`
+	FailedNodes     []string             `json:"failed_nodes"`
+	// Failed replicas that were left a hint for later delivery. Hints don't
+	// count towards the write quorum.
+	HintedNodes []string `json:"hinted_nodes,omitempty"`
+	// Dead owners whose copy went to a stand-in further along the ring
+	// (owner -> stand-in). Stand-ins apply the copy and count towards the
+	// write quorum; quorum reads ask them while the owner is down.
+	StandIns map[string]string `json:"stand_ins,omitempty"`
+	// Acknowledgements counted towards the write quorum, split by whether
+	// an owner or a stand-in gave them
+	OwnerAcks   int `json:"owner_acks"`
+	StandInAcks int `json:"stand_in_acks"`
+	// Owners still being replicated to when the write returned; the write
+	// returns once W replicas acknowledged and the rest finish in the background
+	PendingNodes []string `json:"pending_nodes,omitempty"`
+	// Owners the write was queued for when it only needed one acknowledgement;
+	// the durable replication queue delivers it in the background
+	QueuedNodes []string `json:"queued_nodes,omitempty"`
+	// Round trip of each replica contacted before the write returned, in milliseconds
+	ReplicaLatencyMs map[string]float64 `json:"replica_latency_ms,omitempty"`
+	ReplicationLevel int                `json:"replication_level"`
+	QuorumAchieved   bool               `json:"quorum_achieved"`
+	// Consistency requirements the write was held to
+	ConsistencyLevel  ConsistencyLevel `json:"consistency_level"`
+	RequiredAcks      int              `json:"required_acks"`
+	ReplicationFactor int              `json:"replication_factor"`
+	// Durability of the write, see WriteStatus
+	Status WriteStatus `json:"status"`
 }
 
-// ReadResult represents the result of a read operation across replicas
-type ReadResult struct {
-	Key       string                           `json:"key"`
-	Value     string                           `json:"value"`
-	Responses map[string]*storage.StorageValue `json:"responses"`
-	NodeID    string                           `json:"node_id"`
+// WriteStatus tells clients how durable a write is
+type WriteStatus string
+
+const (
+	WriteStatusDurable  WriteStatus = "durable"  // At least W owners acknowledged
+	WriteStatusSloppy   WriteStatus = "sloppy"   // At least W acknowledgements, but fewer than W from owners; stand-ins hold the rest until the owners return
+	WriteStatusAccepted WriteStatus = "accepted" // Below W, kept because the client asked for it; may be lost
+	WriteStatusPartial  WriteStatus = "partial"  // Below W and reported as a failure; may still be visible on some replicas
+	WriteStatusRejected WriteStatus = "rejected" // Not written anywhere
+)
+
+// Acknowledged reports whether the write reached the write quorum, counting
+// stand-ins
+func (s WriteStatus) Acknowledged() bool {
+	return s == WriteStatusDurable || s == WriteStatusSloppy
 }
 
-// Replicator handles data replication across nodes
-type Replicator struct {
-	ring              *ring.ConsistentHashRing
-	storage           *storage.LevelDBStorage
-	currentNode       *node.Node
-	replicationFactor int
-	quorumSize        int
-	httpClient        *http.Client
-
-	// Health monitoring
-	nodeHealth      map[string]*HealthStatus
-	healthMutex     sync.RWMutex
-	healthTicker    *time.Ticker
-	stopHealthCheck chan bool
-}
-
-// NewReplicator creates a new replicator instance
-func NewReplicator(hashRing *ring.ConsistentHashRing, localStorage *storage.LevelDBStorage, currentNode *node.Node) *Replicator {
-	replicator := &Replicator{
-		ring:              hashRing,
-		storage:           localStorage,
-		currentNode:       currentNode,
-		replicationFactor: 3,
-		quorumSize:        2,
-		httpClient: &http.Client{
-			Timeout: 2 * time.Second, // 2 second timeout for health checks
-		},
-		nodeHealth:      make(map[string]*HealthStatus),
-		healthMutex:     sync.RWMutex{},
-		stopHealthCheck: make(chan bool),
-	}
-
-	// Start health monitoring
-	replicator.startHealthMonitoring()
+// QuorumError is returned when a write can't reach W acknowledgements. Result
+// lists the replicas that did store the write; it is not rolled back.
+type QuorumError struct {
+	Result *WriteResult
+	Reason string
+}
 
-	return replicator
+func (e *QuorumError) Error() string {
+	return e.Reason
 }
 
-// startHealthMonitoring begins periodic health checks of all cluster nodes
-func (r *Replicator) startHealthMonitoring() {
-	r.healthTicker = time.NewTicker(3 * time.Second) // Check every 3 seconds
+// ReadResult represents the result of a read operation across replicas
+type ReadResult struct {
+	Key         string                           `json:"key"`
+	Value       string                           `json:"value"`
+	VectorClock *storage.VectorClock             `json:"vector_clock,omitempty"` // The tombstone's when the key is deleted
+	Winner      string                           `json:"winner,omitempty"`       // Replica holding the returned version
+	Siblings    []*storage.StorageValue          `json:"siblings,omitempty"`     // Concurrent versions
+	Responses   map[string]*storage.StorageValue `json:"responses"`
+	FailedNodes []string                         `json:"failed_nodes,omitempty"`
+	NodeID      string                           `json:"node_id"`
+	// Consistency requirements the read was held to
+	ConsistencyLevel  ConsistencyLevel `json:"consistency_level"`
+	RequiredResponses int              `json:"required_responses"`
+}
 
-	go func() {
-		for {
-			select {
-			case <-r.healthTicker.C:
-				r.performHealthChecks()
-			case <-r.stopHealthCheck:
-				r.healthTicker.Stop()
-				return
-			}
-		}
-	}()
+// ConsistencyError is returned when a request asks for strong consistency but
+// the effective R and W quorums don't overlap
+type ConsistencyError struct {
+	N int
+	R int
+	W int
+}
 
-	fmt.Printf("🩺 Health monitoring started (checking every 3 seconds)\n")
+func (e *ConsistencyError) Error() string {
+	return fmt.Sprintf("strong consistency requested but R=%d + W=%d does not exceed N=%d", e.R, e.W, e.N)
 }
 
-// performHealthChecks checks the health of all nodes in the cluster
-func (r *Replicator) performHealthChecks() {
-	nodes := r.ring.GetAllNodes()
+// Replicator handles data replication across nodes
+type Replicator struct {
+	ring        *ring.ConsistentHashRing
+	storage     *storage.LevelDBStorage
+	currentNode *node.Node
+	transport   Transport
+	clock       clock.Clock
+
+	// Last clock each target acknowledged, used to send event deltas
+	ackedClocks map[string]*storage.VectorClock
+	ackMutex    sync.RWMutex
+
+	// Durable background replication for single-ack writes
+	queue            *ReplicationQueue
+	asyncReplication bool
+
+	// Hinted handoff for replicas that miss a write
+	hints          *HintStore
+	replayingHints map[string]bool
+	hintMutex      sync.Mutex
+
+	// N/R/W settings, cluster-wide and per namespace
+	config           *ReplicationConfig
+	namespaceConfigs map[string]*ReplicationConfig
+	sloppyQuorum     bool
+	configMutex      sync.RWMutex
+	settings         ClusterSettings // Spreads namespace overrides; nil = local only
+
+	// Which nodes are alive comes from the membership service
+	members   *membership.Service
+	retryCtx  context.Context
+	stopRetry context.CancelFunc
+}
 
-	for _, node := range nodes {
-		// Don't check ourselves
-		if node.ID == r.currentNode.ID {
-			r.updateNodeHealth(node.ID, true, 0, 0)
-			continue
-		}
+// NewReplicator creates a new replicator instance. It sends to the nodes
+// members reports alive. Background work runs on clk (nil = wall clock).
+func NewReplicator(hashRing *ring.ConsistentHashRing, localStorage *storage.LevelDBStorage, currentNode *node.Node, config *ReplicationConfig, peerTransport Transport, members *membership.Service, clk clock.Clock) *Replicator {
+	if config == nil {
+		config = DefaultReplicationConfig()
+	}
+	if peerTransport == nil {
+		peerTransport = NewHTTPTransport(nil)
+	}
 
-		// Check remote node health
-		go r.checkNodeHealth(node)
+	if members == nil {
+		members = membership.New(currentNode, clk)
 	}
-}
 
-// checkNodeHealth performs a health check on a specific node
-func (r *Replicator) checkNodeHealth(targetNode *node.Node) {
-	start := time.Now()
+	retryCtx, stopRetry := context.WithCancel(context.Background())
 
-	// Try to contact the node's status endpoint
-	url := fmt.Sprintf("http://%s/api/v1/status", targetNode.Address)
+	replicator := &Replicator{
+		ring:             hashRing,
+		storage:          localStorage,
+		currentNode:      currentNode,
+		transport:        peerTransport,
+		clock:            clock.OrReal(clk),
+		config:           config,
+		namespaceConfigs: make(map[string]*ReplicationConfig),
+		sloppyQuorum:     true,
+		replayingHints:   make(map[string]bool),
+		ackedClocks:      make(map[string]*storage.VectorClock),
+		members:          members,
+		retryCtx:         retryCtx,
+		stopRetry:        stopRetry,
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
+	// Hints live next to the node's data so they survive restarts
+	hints, err := NewHintStore(localStorage.DataPath()+"-hints", replicator.clock)
+	if err != nil {
+		fmt.Printf("⚠️ Hinted handoff disabled: %v\n", err)
+	} else {
+		replicator.hints = hints
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	// The async replication queue resumes where the previous run left off
+	queue, err := NewReplicationQueue(localStorage.DataPath()+"-queue", replicator.deliverBatch, replicator.clock)
 	if err != nil {
-		r.recordHealthCheckFailure(targetNode.ID, start)
-		return
+		fmt.Printf("⚠️ Async replication disabled: %v\n", err)
+	} else {
+		replicator.queue = queue
 	}
 
-	resp, err := r.httpClient.Do(req)
-	responseTime := time.Since(start)
+	// Deliver what nodes missed once they are back, and keep retrying
+	members.Subscribe(replicator.onMembershipChange)
+	replicator.clock.Every(retryCtx, hintRetryInterval, replicator.retryHints)
+	replicator.clock.Every(retryCtx, tombstoneGCInterval, replicator.purgeTombstones)
 
-	if err != nil || resp.StatusCode != 200 {
-		r.recordHealthCheckFailure(targetNode.ID, start)
-		if resp != nil {
-			resp.Body.Close()
-		}
-		return
+	return replicator
+}
+
+// purgeTombstones drops tombstones older than TombstoneGracePeriod
+func (r *Replicator) purgeTombstones() {
+	before := r.clock.Now().Add(-TombstoneGracePeriod).Unix()
+	if _, err := r.storage.PurgeTombstones(before); err != nil {
+		fmt.Printf("⚠️ Tombstone GC failed: %v\n", err)
 	}
+}
 
-	resp.Body.Close()
-	r.updateNodeHealth(targetNode.ID, true, responseTime, 0)
+// onMembershipChange delivers what a node missed once it is alive again
+func (r *Replicator) onMembershipChange(change membership.Change) {
+	if change.Member.Status == "alive" && (change.Previous == "dead" || change.Previous == "left") {
+		fmt.Printf("💚 Node %s RECOVERED\n", change.Member.NodeID)
+		r.onNodeRecovered(change.Member.NodeID)
+	}
 }
 
-// recordHealthCheckFailure records a failed health check
-func (r *Replicator) recordHealthCheckFailure(nodeID string, startTime time.Time) {
-	responseTime := time.Since(startTime)
+// retryHints retries hints for targets that are alive but still have some
+// pending (e.g. an earlier replay was interrupted)
+func (r *Replicator) retryHints() {
+	if r.hints == nil {
+		return
+	}
+	for _, target := range r.hints.Targets() {
+		if r.isNodeAlive(target) {
+			target := target
+			r.clock.Go(func() { r.replayHints(target) })
+		}
+	}
+}
 
-	r.healthMutex.Lock()
-	defer r.healthMutex.Unlock()
+// ConfigForKey returns the N/R/W settings that apply to a key, taking
+// namespace overrides into account
+func (r *Replicator) ConfigForKey(key string) ReplicationConfig {
+	r.configMutex.RLock()
+	defer r.configMutex.RUnlock()
 
-	health, exists := r.nodeHealth[nodeID]
-	if !exists {
-		health = &HealthStatus{
-			NodeID: nodeID,
-		}
-		r.nodeHealth[nodeID] = health
+	if override, exists := r.namespaceConfigs[NamespaceOf(key)]; exists {
+		return *override
 	}
+	return *r.config
+}
 
-	health.IsAlive = false
-	health.LastChecked = time.Now()
-	health.ResponseTime = responseTime
-	health.FailureCount++
+// SetNamespaceConfig overrides N/R/W for every key in a namespace on this
+// node only. Zero fields inherit the cluster-wide defaults. Use
+// ConfigureNamespace to change an override on every node.
+func (r *Replicator) SetNamespaceConfig(namespace string, override *ReplicationConfig) error {
+	r.configMutex.Lock()
+	defer r.configMutex.Unlock()
 
-	// Log failure detection
-	if health.FailureCount == 1 {
-		fmt.Printf("💀 Node %s detected as FAILED (connection refused)\n", nodeID)
+	config, err := r.resolveNamespaceConfig(namespace, override)
+	if err != nil {
+		return err
 	}
+
+	r.namespaceConfigs[namespace] = &config
+	fmt.Printf("⚙️ Namespace %s: N=%d R=%d W=%d (strong: %v)\n", namespace, config.N, config.R, config.W, config.IsStrong())
+	return nil
 }
 
-// updateNodeHealth updates the health status of a node
-func (r *Replicator) updateNodeHealth(nodeID string, isAlive bool, responseTime time.Duration, failureCount int) {
-	r.healthMutex.Lock()
-	defer r.healthMutex.Unlock()
+// resolveNamespaceConfig fills an override's zero fields from the cluster-wide
+// defaults and validates it. Callers hold configMutex.
+func (r *Replicator) resolveNamespaceConfig(namespace string, override *ReplicationConfig) (ReplicationConfig, error) {
+	if namespace == "" {
+		return ReplicationConfig{}, fmt.Errorf("namespace must not be empty")
+	}
 
-	health, exists := r.nodeHealth[nodeID]
-	if !exists {
-		health = &HealthStatus{
-			NodeID: nodeID,
-		}
-		r.nodeHealth[nodeID] = health
+	config := *override
+	if config.N == 0 {
+		config.N = r.config.N
+	}
+	if config.R == 0 {
+		config.R = r.config.R
+	}
+	if config.W == 0 {
+		config.W = r.config.W
+	}
+	if err := config.Validate(); err != nil {
+		return ReplicationConfig{}, fmt.Errorf("invalid config for namespace %s: %v", namespace, err)
 	}
+	return config, nil
+}
+
+// quorumPlan is the resolved replication settings for a single operation
+type quorumPlan struct {
+	config   ReplicationConfig
+	level    ConsistencyLevel
+	required int // Replicas that must answer (R for reads, W for writes)
+}
+
+// planQuorum resolves the consistency options of a request against the key's
+// configuration and checks the strong consistency requirement
+func (r *Replicator) planQuorum(key string, opts *ConsistencyOptions, isRead bool) (*quorumPlan, error) {
+	if opts == nil {
+		opts = &ConsistencyOptions{}
+	}
+
+	config := r.ConfigForKey(key)
+	plan := &quorumPlan{config: config, level: opts.Level}
 
-	wasAlive := health.IsAlive
-	health.IsAlive = isAlive
-	health.LastChecked = time.Now()
-	health.ResponseTime = responseTime
+	readQuorum, writeQuorum := config.R, config.W
+	if isRead {
+		plan.required = requiredReplicas(opts.Level, config.N, config.R)
+		readQuorum = plan.required
+	} else {
+		plan.required = requiredReplicas(opts.Level, config.N, config.W)
+		writeQuorum = plan.required
+	}
 
-	if failureCount > 0 {
-		health.FailureCount = failureCount
-	} else if isAlive && !wasAlive {
-		// Node recovered
-		health.FailureCount = 0
-		fmt.Printf("💚 Node %s RECOVERED (%.2fms response time)\n", nodeID, float64(responseTime.Nanoseconds())/1000000)
+	if opts.Strong && (opts.Level == ConsistencyLocal || readQuorum+writeQuorum <= config.N) {
+		return nil, &ConsistencyError{N: config.N, R: readQuorum, W: writeQuorum}
 	}
+
+	return plan, nil
 }
 
 // getAliveNodes returns only the nodes that are currently alive
@@ -227,18 +353,9 @@ func (r *Replicator) getAliveNodes() []*node.Node {
 	allNodes := r.ring.GetAllNodes()
 	aliveNodes := make([]*node.Node, 0)
 
-	r.healthMutex.RLock()
-	defer r.healthMutex.RUnlock()
-
 	for _, node := range allNodes {
-		if node.ID == r.currentNode.ID {
-			// Current node is always considered alive
-			aliveNodes = append(aliveNodes, node)
-			continue
-		}
-
-		health, exists := r.nodeHealth[node.ID]
-		if exists && health.IsAlive {
+		// The current node is always alive
+		if node.ID == r.currentNode.ID || r.members.IsAlive(node.ID) {
 			aliveNodes = append(aliveNodes, node)
 		}
 	}
@@ -247,117 +364,147 @@ func (r *Replicator) getAliveNodes() []*node.Node {
 }
 
 // WriteWithReplication writes data with replication and vector clock sync
-func (r *Replicator) WriteWithReplication(key, value string) (*WriteResult, error) {
-	// Check if we have enough alive nodes for quorum
-	aliveNodes := r.getAliveNodes()
-	if len(aliveNodes) < r.quorumSize {
-		return &WriteResult{
-			Key:              key,
-			Value:            value,
-			SuccessfulNodes:  []string{},
-			FailedNodes:      []string{},
-			ReplicationLevel: len(aliveNodes),
-			QuorumAchieved:   false,
-		}, fmt.Errorf("insufficient alive nodes: have %d, need %d for quorum", len(aliveNodes), r.quorumSize)
-	}
-
-	fmt.Printf("🔍 Write attempt: %d alive nodes, need %d for quorum\n", len(aliveNodes), r.quorumSize)
-
-	// Store locally first and get the event
-	err := r.storage.Put(key, value)
+func (r *Replicator) WriteWithReplication(key, value string, opts *ConsistencyOptions) (*WriteResult, error) {
+	plan, err := r.planQuorum(key, opts, false)
 	if err != nil {
-		return nil, fmt.Errorf("local write failed: %v", err)
+		return nil, err
 	}
 
-	// Get the event that was just created for this write
-	eventLog := r.storage.GetEventLog()
-	var sourceEvent *storage.Event
-	if len(eventLog.Events) > 0 {
-		sourceEvent = eventLog.Events[len(eventLog.Events)-1] // Get the latest event
+	// Check if we have enough alive nodes for quorum
+	aliveNodes := r.getAliveNodes()
+	if len(aliveNodes) < plan.required {
+		result := &WriteResult{
+			Key:               key,
+			Value:             value,
+			SuccessfulNodes:   []string{},
+			FailedNodes:       []string{},
+			ReplicationLevel:  len(aliveNodes),
+			QuorumAchieved:    false,
+			ConsistencyLevel:  plan.level,
+			RequiredAcks:      plan.required,
+			ReplicationFactor: plan.config.N,
+			Status:            WriteStatusRejected,
+		}
+		return result, &QuorumError{
+			Result: result,
+			Reason: fmt.Sprintf("insufficient alive nodes: have %d, need %d for quorum", len(aliveNodes), plan.required),
+		}
 	}
 
-	successfulNodes := []string{r.currentNode.ID}
-	failedNodes := []string{}
+	fmt.Printf("🔍 Write attempt: %d alive nodes, need %d for quorum\n", len(aliveNodes), plan.required)
 
-	// Get target nodes for replication
-	targetNodes := r.ring.GetNodesForKey(key, r.replicationFactor)
-
-	// Replicate to other nodes with vector clock sync
-	for _, targetNode := range targetNodes {
-		if targetNode.ID == r.currentNode.ID {
-			continue // Skip self
+	// Owners apply the write locally first and get the event; any other
+	// coordinator only records the event it replicates with
+	var sourceEvent *storage.Event
+	if r.IsOwner(key) {
+		err = r.storage.Put(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("local write failed: %v", err)
 		}
 
-		// Only replicate to alive nodes
-		if !r.isNodeAlive(targetNode.ID) {
-			failedNodes = append(failedNodes, targetNode.ID)
-			continue
+		// Get the event that was just created for this write
+		events := r.storage.GetEventLog().Events
+		if len(events) > 0 {
+			sourceEvent = events[len(events)-1] // Get the latest event
 		}
+	} else {
+		sourceEvent = r.storage.RecordEvent("put", key, value)
+	}
 
-		// Create replication request with vector clock info
-		request := ReplicationRequest{
-			Key:         key,
-			Value:       value,
-			Operation:   "put",
-			SourceNode:  r.currentNode.ID,
-			Timestamp:   time.Now().Unix(),
-			EventLog:    eventLog,
-			VectorClock: eventLog.Current,
-			SourceEvent: sourceEvent,
-		}
+	// Replicate to the key's other owners with vector clock sync
+	fanout := r.replicate(key, plan, ReplicationRequest{
+		Key:         key,
+		Value:       value,
+		Operation:   "put",
+		SourceNode:  r.currentNode.ID,
+		Timestamp:   r.clock.Now().Unix(),
+		VectorClock: r.storage.CurrentClock(),
+		SourceEvent: sourceEvent,
+	})
+	successfulNodes := fanout.successful
+
+	quorumAchieved := len(successfulNodes) >= plan.required
+
+	return r.finishWrite(&WriteResult{
+		Key:               key,
+		Value:             value,
+		VectorClock:       eventClock(sourceEvent),
+		SuccessfulNodes:   successfulNodes,
+		FailedNodes:       fanout.failed,
+		HintedNodes:       fanout.hinted,
+		StandIns:          fanout.standIns,
+		PendingNodes:      fanout.pending,
+		QueuedNodes:       fanout.queued,
+		ReplicaLatencyMs:  latenciesInMs(fanout.latencies),
+		ReplicationLevel:  len(successfulNodes),
+		QuorumAchieved:    quorumAchieved,
+		ConsistencyLevel:  plan.level,
+		RequiredAcks:      plan.required,
+		ReplicationFactor: plan.config.N,
+	}, opts)
+}
 
-		success := r.replicateToNode(targetNode, &request)
-		if success {
-			successfulNodes = append(successfulNodes, targetNode.ID)
-		} else {
-			failedNodes = append(failedNodes, targetNode.ID)
+// eventClock returns a copy of the event's vector clock, or nil without an event
+func eventClock(event *storage.Event) *storage.VectorClock {
+	if event == nil || event.VectorClock == nil {
+		return nil
+	}
+	return event.VectorClock.Copy()
+}
+
+// finishWrite sets the durability status of a completed write and turns a
+// missed write quorum into a QuorumError unless the client accepts partial
+// writes. Stand-in acks count towards the quorum, but a write that only
+// reached it through stand-ins is reported as sloppy rather than durable.
+func (r *Replicator) finishWrite(result *WriteResult, opts *ConsistencyOptions) (*WriteResult, error) {
+	result.StandInAcks = len(result.StandIns)
+	result.OwnerAcks = len(result.SuccessfulNodes) - result.StandInAcks
+
+	if result.QuorumAchieved {
+		result.Status = WriteStatusDurable
+		if result.OwnerAcks < result.RequiredAcks {
+			result.Status = WriteStatusSloppy
+			fmt.Printf("⚠️ %s reached write quorum through stand-ins (%d owner, %d stand-in acks)\n", result.Key, result.OwnerAcks, result.StandInAcks)
 		}
+		return result, nil
 	}
 
-	quorumAchieved := len(successfulNodes) >= r.quorumSize
+	if opts != nil && opts.AcceptPartial {
+		result.Status = WriteStatusAccepted
+		fmt.Printf("⚠️ %s accepted below write quorum (%d/%d acks)\n", result.Key, len(result.SuccessfulNodes), result.RequiredAcks)
+		return result, nil
+	}
 
-	return &WriteResult{
-		Key:              key,
-		Value:            value,
-		SuccessfulNodes:  successfulNodes,
-		FailedNodes:      failedNodes,
-		ReplicationLevel: len(successfulNodes),
-		QuorumAchieved:   quorumAchieved,
-	}, nil
+	result.Status = WriteStatusPartial
+	return result, &QuorumError{
+		Result: result,
+		Reason: fmt.Sprintf("write quorum not reached for %s: %d of %d acknowledgements", result.Key, len(result.SuccessfulNodes), result.RequiredAcks),
+	}
 }
 
 // replicateToNode sends replication request to a specific node
 func (r *Replicator) replicateToNode(targetNode *node.Node, request *ReplicationRequest) bool {
-	url := fmt.Sprintf("http://%s/internal/replicate", targetNode.Address)
+	// Ship only the events this target hasn't acknowledged yet
+	delta := *request
+	delta.Events = r.deltaFor(targetNode.ID, request.SourceEvent)
 
-	requestBody, err := json.Marshal(request)
-	if err != nil {
-		fmt.Printf("❌ Failed to marshal replication request for %s: %v\n", targetNode.ID, err)
-		return false
-	}
+	ctx, cancel := r.clock.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
 
-	client := &http.Client{Timeout: 2 * time.Second}
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(requestBody))
+	response, err := r.transport.Replicate(ctx, targetNode, &delta)
 	if err != nil {
 		fmt.Printf("❌ Replication failed to %s: %v\n", targetNode.ID, err)
 		return false
 	}
-	defer resp.Body.Close()
-
-	var response ReplicationResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	if err != nil {
-		fmt.Printf("❌ Failed to decode replication response from %s: %v\n", targetNode.ID, err)
-		return false
-	}
 
 	if response.Success {
 		fmt.Printf("✅ Replication successful to %s\n", targetNode.ID)
 
-		// If we got an updated vector clock back, we could merge it here
+		// Remember what the target has seen so the next delta starts there
 		if response.UpdatedClock != nil {
 			fmt.Printf("🕰️ Received updated vector clock from %s: %s\n",
 				targetNode.ID, response.UpdatedClock.String())
+			r.recordAck(targetNode.ID, response.UpdatedClock)
 		}
 
 		return true
@@ -367,13 +514,15 @@ func (r *Replicator) replicateToNode(targetNode *node.Node, request *Replication
 	}
 }
 
+// IsNodeAlive reports whether the membership service has a node as alive
+// (or only suspected)
+func (r *Replicator) IsNodeAlive(nodeID string) bool {
+	return r.isNodeAlive(nodeID)
+}
+
 // isNodeAlive checks if a specific node is alive
 func (r *Replicator) isNodeAlive(nodeID string) bool {
-	r.healthMutex.RLock()
-	defer r.healthMutex.RUnlock()
-
-	health, exists := r.nodeHealth[nodeID]
-	return exists && health.IsAlive
+	return r.members.IsAlive(nodeID)
 }
 
 // GetReplicationStatus returns current replication status including health information
@@ -381,39 +530,328 @@ func (r *Replicator) GetReplicationStatus() map[string]interface{} {
 	aliveNodes := r.getAliveNodes()
 	allNodes := r.ring.GetAllNodes()
 
-	r.healthMutex.RLock()
-	healthSummary := make(map[string]*HealthStatus)
-	for k, v := range r.nodeHealth {
-		healthSummary[k] = v
+	healthSummary := make(map[string]membership.Member)
+	for _, member := range r.members.Members() {
+		healthSummary[member.NodeID] = member
+	}
+
+	r.configMutex.RLock()
+	config := *r.config
+	namespaces := make(map[string]ReplicationConfig)
+	for namespace, override := range r.namespaceConfigs {
+		namespaces[namespace] = *override
 	}
-	r.healthMutex.RUnlock()
+	r.configMutex.RUnlock()
 
 	return map[string]interface{}{
-		"replication_factor": r.replicationFactor,
-		"quorum_size":        r.quorumSize,
+		"replication_factor": config.N,
+		"quorum_size":        config.W,
+		"read_quorum":        config.R,
+		"write_quorum":       config.W,
+		"strong_consistency": config.IsStrong(),
+		"namespaces":         namespaces,
 		"total_nodes":        len(allNodes),
 		"alive_nodes":        len(aliveNodes),
 		"current_node":       r.currentNode.ID,
-		"quorum_available":   len(aliveNodes) >= r.quorumSize,
+		"quorum_available":   len(aliveNodes) >= config.W,
+		"sloppy_quorum":      r.IsSloppyQuorum(),
 		"node_health":        healthSummary,
+		"hinted_handoff":     r.GetHintStats(),
+		"acked_clocks":       r.ackedClockSnapshot(),
+		"async_replication":  r.GetQueueStats(),
+	}
+}
+
+// GetReplicationFactor returns the cluster-wide number of replicas kept for each key
+func (r *Replicator) GetReplicationFactor() int {
+	r.configMutex.RLock()
+	defer r.configMutex.RUnlock()
+	return r.config.N
+}
+
+// PushVersion writes an existing version of a key to a replica without creating
+// a new event, so the replica ends up with the same vector clock as the source.
+// A tombstone is pushed as a delete. It is used to repair stale replicas.
+func (r *Replicator) PushVersion(targetNode *node.Node, key string, version *storage.StorageValue) error {
+	request := r.versionRequest(key, version)
+
+	if targetNode.ID == r.currentNode.ID {
+		var err error
+		if version.Deleted {
+			err = r.storage.DeleteReplicated(key, request.SourceEvent)
+		} else {
+			err = r.storage.PutReplicated(key, version.Value, request.SourceEvent)
+		}
+		if err == storage.ErrSuperseded {
+			return nil // Already newer here
+		}
+		return err
+	}
+
+	if !r.replicateToNode(targetNode, request) {
+		return fmt.Errorf("failed to push %s to %s", key, targetNode.ID)
+	}
+	return nil
+}
+
+// versionRequest builds the replication request that writes a stored version
+// (a delete for a tombstone) under the event that produced it
+func (r *Replicator) versionRequest(key string, version *storage.StorageValue) *ReplicationRequest {
+	operation := "put"
+	if version.Deleted {
+		operation = "delete"
+	}
+
+	sourceEvent := &storage.Event{
+		ID:          version.Metadata["event_id"],
+		Type:        operation,
+		Key:         key,
+		Value:       version.Value,
+		NodeID:      version.Metadata["node_id"],
+		VectorClock: version.GetVectorClock(),
+		Timestamp:   version.Timestamp,
+	}
+
+	return &ReplicationRequest{
+		Key:         key,
+		Value:       version.Value,
+		Operation:   operation,
+		SourceNode:  r.currentNode.ID,
+		Timestamp:   r.clock.Now().Unix(),
+		VectorClock: sourceEvent.VectorClock,
+		SourceEvent: sourceEvent,
 	}
 }
 
-// ReadWithQuorum reads data with quorum requirements
-func (r *Replicator) ReadWithQuorum(key string) (*storage.StorageValue, error) {
+// replicaRead is one replica's answer to a quorum read
+type replicaRead struct {
+	nodeID string
+	value  *storage.StorageValue // nil if the replica doesn't have the key
+	err    error
+}
+
+// ReadWithQuorum reads a key from its replicas, waits for R responses and
+// returns the newest version according to vector clocks; a newest tombstone
+// means the key is missing. Concurrent versions are returned as siblings.
+// With sloppy quorum enabled, an owner that is down or fails is replaced by
+// the stand-in a write would have picked for it. Replicas found to be stale
+// are repaired in the background once every replica has answered.
+func (r *Replicator) ReadWithQuorum(key string, opts *ConsistencyOptions) (*ReadResult, error) {
+	plan, err := r.planQuorum(key, opts, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.level == ConsistencyLocal {
+		return r.readLocal(key, plan)
+	}
+
 	// Check if we have enough alive nodes for quorum
 	aliveNodes := r.getAliveNodes()
-	if len(aliveNodes) < r.quorumSize {
-		return nil, fmt.Errorf("insufficient alive nodes for read quorum: have %d, need %d", len(aliveNodes), r.quorumSize)
+	if len(aliveNodes) < plan.required {
+		return nil, fmt.Errorf("insufficient alive nodes for read quorum: have %d, need %d", len(aliveNodes), plan.required)
+	}
+
+	targetNodes, candidates := r.extendedPreferenceList(key, plan.config.N)
+	pool := newStandInPool(targetNodes, candidates)
+	sloppy := r.IsSloppyQuorum()
+	reads := make(chan *replicaRead, len(targetNodes))
+
+	for _, targetNode := range targetNodes {
+		target := targetNode
+		r.clock.Go(func() {
+			reads <- r.readFromOwner(target, pool, sloppy, key)
+		})
+	}
+
+	// Wait until R replicas have answered (or all of them failed)
+	result := &ReadResult{
+		Key:               key,
+		Responses:         make(map[string]*storage.StorageValue),
+		NodeID:            r.currentNode.ID,
+		ConsistencyLevel:  plan.level,
+		RequiredResponses: plan.required,
+	}
+	collected := make([]*replicaRead, 0, len(targetNodes))
+	successes := 0
+	for len(collected) < len(targetNodes) && successes < plan.required {
+		read := <-reads
+		collected = append(collected, read)
+		if read.err != nil {
+			result.FailedNodes = append(result.FailedNodes, read.nodeID)
+			continue
+		}
+		successes++
+		result.Responses[read.nodeID] = read.value
+	}
+
+	// Let the remaining replicas answer in the background and repair stale ones
+	r.clock.Go(func() { r.readRepair(key, reads, collected, len(targetNodes)) })
+
+	if successes < plan.required {
+		return result, fmt.Errorf("read quorum not reached for %s: %d of %d replicas responded", key, successes, plan.required)
+	}
+
+	winner, siblings := storage.ReconcileVersions(result.Responses)
+	if winner == "" {
+		return result, storage.ErrKeyNotFound
+	}
+	if result.Responses[winner].Deleted {
+		// Carry the tombstone's clock, so clients can tell which delete they saw
+		result.VectorClock = result.Responses[winner].GetVectorClock()
+		result.Winner = winner
+		return result, storage.ErrKeyNotFound
+	}
+
+	winning := result.Responses[winner]
+	result.Value = winning.Value
+	result.VectorClock = winning.GetVectorClock()
+	result.Winner = winner
+	for _, sibling := range siblings {
+		result.Siblings = append(result.Siblings, result.Responses[sibling])
+	}
+
+	return result, nil
+}
+
+// readLocal serves a LOCAL read from this node's storage only
+func (r *Replicator) readLocal(key string, plan *quorumPlan) (*ReadResult, error) {
+	value, err := r.storage.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReadResult{
+		Key:               key,
+		Value:             value.Value,
+		VectorClock:       value.GetVectorClock(),
+		Winner:            r.currentNode.ID,
+		Responses:         map[string]*storage.StorageValue{r.currentNode.ID: value},
+		NodeID:            r.currentNode.ID,
+		ConsistencyLevel:  plan.level,
+		RequiredResponses: plan.required,
+	}, nil
+}
+
+// readFromOwner reads a key from one owner, falling back to the stand-ins in
+// ring order when sloppy quorum is enabled and the owner can't answer. The
+// read carries the ID of the node that answered.
+func (r *Replicator) readFromOwner(owner *node.Node, pool *standInPool, sloppy bool, key string) *replicaRead {
+	read := r.readFromReplica(owner, key)
+	if read.err == nil || !sloppy {
+		return read
 	}
 
-	// For now, just read from local storage
-	// In a full implementation, we'd read from multiple nodes and resolve conflicts
-	return r.storage.Get(key)
+	for candidate := pool.next(r); candidate != nil; candidate = pool.next(r) {
+		if standIn := r.readFromReplica(candidate, key); standIn.err == nil {
+			return standIn
+		}
+	}
+	return read
+}
+
+// readFromReplica reads a key from a single replica (locally or over HTTP)
+func (r *Replicator) readFromReplica(targetNode *node.Node, key string) *replicaRead {
+	read := &replicaRead{nodeID: targetNode.ID}
+
+	if targetNode.ID == r.currentNode.ID {
+		// Like remote replicas, answer with tombstones so they win over
+		// older values and get repaired onto replicas that missed the delete
+		value, err := r.storage.GetVersion(key)
+		if err != nil && err != storage.ErrKeyNotFound {
+			read.err = err
+		}
+		read.value = value
+		return read
+	}
+
+	if !r.isNodeAlive(targetNode.ID) {
+		read.err = fmt.Errorf("node %s is not alive", targetNode.ID)
+		return read
+	}
+
+	read.value, read.err = r.FetchVersion(targetNode, key)
+	return read
+}
+
+// readRepair waits for the replicas that hadn't answered when the read
+// returned, then pushes the newest version (possibly a tombstone) to every
+// replica holding an older version or none at all. A replica without the key
+// never saw it: a delete would have left a tombstone. When replicas hold
+// concurrent versions nothing is pushed, so no sibling is overwritten; the
+// conflict is resolved by the next write.
+func (r *Replicator) readRepair(key string, reads chan *replicaRead, collected []*replicaRead, total int) {
+	for len(collected) < total {
+		collected = append(collected, <-reads)
+	}
+
+	versions := make(map[string]*storage.StorageValue)
+	for _, read := range collected {
+		if read.err == nil {
+			versions[read.nodeID] = read.value
+		}
+	}
+
+	winner, siblings := storage.ReconcileVersions(versions)
+	if winner == "" {
+		return
+	}
+	if len(siblings) > 0 {
+		fmt.Printf("⚠️ Read repair of %s skipped: %d replicas hold concurrent versions\n", key, len(siblings)+1)
+		return
+	}
+	winning := versions[winner]
+	winningClock := winning.GetVectorClock()
+
+	replicaIDs := make([]string, 0, len(versions))
+	for replicaID := range versions {
+		replicaIDs = append(replicaIDs, replicaID)
+	}
+	sort.Strings(replicaIDs)
+
+	for _, replicaID := range replicaIDs {
+		version := versions[replicaID]
+		if replicaID == winner {
+			continue
+		}
+		if version != nil && version.GetVectorClock().Compare(winningClock) != storage.Before {
+			continue
+		}
+
+		targetNode := r.ring.GetNode(replicaID)
+		if targetNode == nil {
+			continue
+		}
+
+		if err := r.PushVersion(targetNode, key, winning); err != nil {
+			fmt.Printf("❌ Read repair of %s on %s failed: %v\n", key, replicaID, err)
+			continue
+		}
+		fmt.Printf("🔧 Read repair: pushed %s [%s] to stale replica %s\n", key, winningClock.String(), replicaID)
+	}
+}
+
+// FetchVersion asks another node for its stored version of a key without
+// logging a read there. A nil value with a nil error means the node doesn't
+// have the key.
+func (r *Replicator) FetchVersion(targetNode *node.Node, key string) (*storage.StorageValue, error) {
+	ctx, cancel := r.clock.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	version, err := r.transport.FetchVersion(ctx, targetNode, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch version from %s: %v", targetNode.ID, err)
+	}
+	return version, nil
 }
 
 // HandleReplicationRequest processes incoming replication requests with vector clock sync
 func (r *Replicator) HandleReplicationRequest(req *ReplicationRequest) *ReplicationResponse {
+	// A stand-in holds the mutation for its owner instead of applying it
+	if req.HintedFor != "" && req.HintedFor != r.currentNode.ID {
+		return r.acceptHandoff(req)
+	}
+
 	switch req.Operation {
 	case "put":
 		// Store the data using replicated method to avoid duplicate events
@@ -425,28 +863,29 @@ func (r *Replicator) HandleReplicationRequest(req *ReplicationRequest) *Replicat
 			// Fallback to regular put if no source event
 			err = r.storage.Put(req.Key, req.Value)
 		}
-		
+
+		if err == storage.ErrSuperseded {
+			return r.skippedStale(req)
+		}
 		if err != nil {
 			return &ReplicationResponse{
 				Success:   false,
 				Message:   "Replication failed",
 				NodeID:    r.currentNode.ID,
-				Timestamp: time.Now().Unix(),
+				Timestamp: r.clock.Now().Unix(),
 				Error:     err.Error(),
 			}
 		}
 
-		// Merge vector clock and event log if provided
-		if req.EventLog != nil && req.VectorClock != nil {
-			fmt.Printf("🕰️ Merging vector clock from %s\n", req.SourceNode)
-			r.storage.MergeVectorClock(req.EventLog)
-		}
+		// Merge the event delta if provided
+		fmt.Printf("🕰️ Merging event delta from %s (%d events)\n", req.SourceNode, len(req.Events))
+		r.mergeDelta(req)
 
 		return &ReplicationResponse{
 			Success:      true,
 			Message:      "Replication successful",
 			NodeID:       r.currentNode.ID,
-			Timestamp:    time.Now().Unix(),
+			Timestamp:    r.clock.Now().Unix(),
 			UpdatedClock: r.storage.GetEventLog().Current,
 		}
 
@@ -460,28 +899,29 @@ func (r *Replicator) HandleReplicationRequest(req *ReplicationRequest) *Replicat
 			// Fallback to regular delete if no source event
 			err = r.storage.Delete(req.Key)
 		}
-		
+
+		if err == storage.ErrSuperseded {
+			return r.skippedStale(req)
+		}
 		if err != nil {
 			return &ReplicationResponse{
 				Success:   false,
 				Message:   "Delete replication failed",
 				NodeID:    r.currentNode.ID,
-				Timestamp: time.Now().Unix(),
+				Timestamp: r.clock.Now().Unix(),
 				Error:     err.Error(),
 			}
 		}
 
-		// Merge vector clock for delete operations too
-		if req.EventLog != nil && req.VectorClock != nil {
-			fmt.Printf("🕰️ Merging vector clock from %s for delete\n", req.SourceNode)
-			r.storage.MergeVectorClock(req.EventLog)
-		}
+		// Merge the event delta for delete operations too
+		fmt.Printf("🕰️ Merging event delta from %s for delete (%d events)\n", req.SourceNode, len(req.Events))
+		r.mergeDelta(req)
 
 		return &ReplicationResponse{
 			Success:      true,
 			Message:      "Delete replication successful",
 			NodeID:       r.currentNode.ID,
-			Timestamp:    time.Now().Unix(),
+			Timestamp:    r.clock.Now().Unix(),
 			UpdatedClock: r.storage.GetEventLog().Current,
 		}
 
@@ -490,118 +930,124 @@ func (r *Replicator) HandleReplicationRequest(req *ReplicationRequest) *Replicat
 			Success:   false,
 			Message:   "Unknown operation",
 			NodeID:    r.currentNode.ID,
-			Timestamp: time.Now().Unix(),
+			Timestamp: r.clock.Now().Unix(),
 			Error:     "unsupported operation: " + req.Operation,
 		}
 	}
 }
 
-// DeleteWithReplication deletes data with replication and vector clock sync
-func (r *Replicator) DeleteWithReplication(key string) (*WriteResult, error) {
-	// Check if we have enough alive nodes for quorum
-	aliveNodes := r.getAliveNodes()
-	if len(aliveNodes) < r.quorumSize {
-		return &WriteResult{
-			Key:              key,
-			Value:            "",
-			SuccessfulNodes:  []string{},
-			FailedNodes:      []string{},
-			ReplicationLevel: len(aliveNodes),
-			QuorumAchieved:   false,
-		}, fmt.Errorf("insufficient alive nodes: have %d, need %d for quorum", len(aliveNodes), r.quorumSize)
+// skippedStale answers a mutation that storage refused because the stored
+// version (or tombstone) already supersedes it. Hints and retries can arrive
+// after newer writes, and concurrent writes lose to the last-write-wins
+// winner; the sender has nothing left to deliver either way.
+func (r *Replicator) skippedStale(req *ReplicationRequest) *ReplicationResponse {
+	fmt.Printf("⏭️ Ignoring stale %s replication for key %s from %s\n", req.Operation, req.Key, req.SourceNode)
+	return &ReplicationResponse{
+		Success:      true,
+		Message:      "Replication skipped: newer version already stored",
+		NodeID:       r.currentNode.ID,
+		Timestamp:    r.clock.Now().Unix(),
+		UpdatedClock: r.storage.GetEventLog().Current,
 	}
+}
 
-	fmt.Printf("🗑️ Delete attempt: %d alive nodes, need %d for quorum\n", len(aliveNodes), r.quorumSize)
-
-	// Delete locally first and get the event
-	err := r.storage.Delete(key)
+// DeleteWithReplication deletes data with replication and vector clock sync
+func (r *Replicator) DeleteWithReplication(key string, opts *ConsistencyOptions) (*WriteResult, error) {
+	plan, err := r.planQuorum(key, opts, false)
 	if err != nil {
-		return nil, fmt.Errorf("local delete failed: %v", err)
+		return nil, err
 	}
 
-	// Get the event that was just created for this delete
-	eventLog := r.storage.GetEventLog()
-	var sourceEvent *storage.Event
-	if len(eventLog.Events) > 0 {
-		sourceEvent = eventLog.Events[len(eventLog.Events)-1] // Get the latest event
-	}
-
-	successfulNodes := []string{r.currentNode.ID}
-	failedNodes := []string{}
-
-	// Get target nodes for replication
-	targetNodes := r.ring.GetNodesForKey(key, r.replicationFactor)
-
-	// Replicate delete to other nodes with vector clock sync
-	for _, targetNode := range targetNodes {
-		if targetNode.ID == r.currentNode.ID {
-			continue // Skip self
+	// Check if we have enough alive nodes for quorum
+	aliveNodes := r.getAliveNodes()
+	if len(aliveNodes) < plan.required {
+		result := &WriteResult{
+			Key:               key,
+			Value:             "",
+			SuccessfulNodes:   []string{},
+			FailedNodes:       []string{},
+			ReplicationLevel:  len(aliveNodes),
+			QuorumAchieved:    false,
+			ConsistencyLevel:  plan.level,
+			RequiredAcks:      plan.required,
+			ReplicationFactor: plan.config.N,
+			Status:            WriteStatusRejected,
 		}
-
-		// Only replicate to alive nodes
-		if !r.isNodeAlive(targetNode.ID) {
-			failedNodes = append(failedNodes, targetNode.ID)
-			continue
+		return result, &QuorumError{
+			Result: result,
+			Reason: fmt.Sprintf("insufficient alive nodes: have %d, need %d for quorum", len(aliveNodes), plan.required),
 		}
+	}
+
+	fmt.Printf("🗑️ Delete attempt: %d alive nodes, need %d for quorum\n", len(aliveNodes), plan.required)
 
-		// Create replication request with vector clock info
-		request := ReplicationRequest{
-			Key:         key,
-			Value:       "", // Empty for delete
-			Operation:   "delete",
-			SourceNode:  r.currentNode.ID,
-			Timestamp:   time.Now().Unix(),
-			EventLog:    eventLog,
-			VectorClock: eventLog.Current,
-			SourceEvent: sourceEvent,
+	// Owners apply the delete locally first and get the event; any other
+	// coordinator only records the event it replicates with
+	var sourceEvent *storage.Event
+	if r.IsOwner(key) {
+		err = r.storage.Delete(key)
+		if err != nil {
+			return nil, fmt.Errorf("local delete failed: %v", err)
 		}
 
-		success := r.replicateToNode(targetNode, &request)
-		if success {
-			successfulNodes = append(successfulNodes, targetNode.ID)
-		} else {
-			failedNodes = append(failedNodes, targetNode.ID)
+		// Get the event that was just created for this delete
+		events := r.storage.GetEventLog().Events
+		if len(events) > 0 {
+			sourceEvent = events[len(events)-1] // Get the latest event
 		}
+	} else {
+		sourceEvent = r.storage.RecordEvent("delete", key, "")
 	}
 
-	quorumAchieved := len(successfulNodes) >= r.quorumSize
-
-	return &WriteResult{
-		Key:              key,
-		Value:            "",
-		SuccessfulNodes:  successfulNodes,
-		FailedNodes:      failedNodes,
-		ReplicationLevel: len(successfulNodes),
-		QuorumAchieved:   quorumAchieved,
-	}, nil
+	// Replicate to the key's other owners with vector clock sync
+	fanout := r.replicate(key, plan, ReplicationRequest{
+		Key:         key,
+		Value:       "", // Empty for delete
+		Operation:   "delete",
+		SourceNode:  r.currentNode.ID,
+		Timestamp:   r.clock.Now().Unix(),
+		VectorClock: r.storage.CurrentClock(),
+		SourceEvent: sourceEvent,
+	})
+	successfulNodes := fanout.successful
+
+	quorumAchieved := len(successfulNodes) >= plan.required
+
+	return r.finishWrite(&WriteResult{
+		Key:               key,
+		Value:             "",
+		VectorClock:       eventClock(sourceEvent),
+		SuccessfulNodes:   successfulNodes,
+		FailedNodes:       fanout.failed,
+		HintedNodes:       fanout.hinted,
+		StandIns:          fanout.standIns,
+		PendingNodes:      fanout.pending,
+		QueuedNodes:       fanout.queued,
+		ReplicaLatencyMs:  latenciesInMs(fanout.latencies),
+		ReplicationLevel:  len(successfulNodes),
+		QuorumAchieved:    quorumAchieved,
+		ConsistencyLevel:  plan.level,
+		RequiredAcks:      plan.required,
+		ReplicationFactor: plan.config.N,
+	}, opts)
 }
 
-// Stop stops the health monitoring
-// MarkNodeAlive marks a node as alive in the health system (used by gossip discovery)
-func (r *Replicator) MarkNodeAlive(nodeID string) {
-	r.healthMutex.Lock()
-	defer r.healthMutex.Unlock()
-
-	health, exists := r.nodeHealth[nodeID]
-	if !exists {
-		health = &HealthStatus{
-			NodeID: nodeID,
-		}
-		r.nodeHealth[nodeID] = health
+// onNodeRecovered delivers what a node missed while it was down
+func (r *Replicator) onNodeRecovered(nodeID string) {
+	r.clock.Go(func() { r.replayHints(nodeID) })
+	if r.queue != nil {
+		r.queue.Kick(nodeID)
 	}
-
-	health.IsAlive = true
-	health.LastChecked = time.Now()
-	health.FailureCount = 0
-	health.ResponseTime = 0
-
-	fmt.Printf("💚 Node %s marked as alive via gossip discovery\n", nodeID)
 }
 
+// Stop stops background work and closes the hint store and queue
 func (r *Replicator) Stop() {
-	close(r.stopHealthCheck)
-	if r.healthTicker != nil {
-		r.healthTicker.Stop()
+	r.stopRetry()
+	if r.hints != nil {
+		r.hints.Close()
+	}
+	if r.queue != nil {
+		r.queue.Close()
 	}
 }
 
@@ -0,0 +1,184 @@
+package replication
+
+import (
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/membership"
+	"dynamodb/internal/node"
+	"dynamodb/internal/ring"
+	"dynamodb/internal/storage"
+	"dynamodb/internal/transport"
+)
+
+// testCluster runs a replicator per node on an in-memory network and a
+// simulated clock. Every node starts out knowing every other node as alive.
+type testCluster struct {
+	t       *testing.T
+	clock   *clock.Simulated
+	network *transport.Network
+	nodes   map[string]*Replicator
+}
+
+func newTestCluster(t *testing.T, config ReplicationConfig, ids ...string) *testCluster {
+	t.Helper()
+
+	c := &testCluster{
+		t:       t,
+		clock:   clock.NewSimulated(time.Unix(0, 0)),
+		network: transport.NewNetwork(1),
+		nodes:   make(map[string]*Replicator),
+	}
+	c.network.SetClock(c.clock)
+
+	dataDir := t.TempDir()
+	for _, id := range ids {
+		localStorage, err := storage.NewLevelDBStorage(id, dataDir)
+		if err != nil {
+			t.Fatalf("NewLevelDBStorage(%s): %v", id, err)
+		}
+		localStorage.SetClock(c.clock)
+
+		self := node.NewNode(id, id+":8000")
+		hashRing := ring.NewConsistentHashRing()
+		hashRing.AddNode(self)
+
+		members := membership.New(self, c.clock)
+		members.FeedRing(hashRing)
+		for _, other := range ids {
+			members.Join(other, other+":8000")
+		}
+
+		nodeConfig := config
+		replicator := NewReplicator(hashRing, localStorage, self, &nodeConfig,
+			NewMemoryTransport(c.network, self.Address), members, c.clock)
+		replicator.RegisterMemory(c.network)
+		c.nodes[id] = replicator
+
+		t.Cleanup(func() {
+			replicator.Stop()
+			localStorage.Close()
+		})
+	}
+	return c
+}
+
+// run calls f on the clock's scheduler and advances the clock until f returns
+func (c *testCluster) run(f func()) {
+	c.t.Helper()
+
+	done := make(chan struct{})
+	c.clock.Go(func() {
+		defer close(done)
+		f()
+	})
+
+	for elapsed := time.Duration(0); elapsed < time.Minute; elapsed += 100 * time.Millisecond {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		c.clock.Advance(100 * time.Millisecond)
+	}
+	c.t.Fatalf("call still running after a minute of simulated time")
+}
+
+// crash takes a node off the network and has every other node see it dead
+func (c *testCluster) crash(id string) {
+	c.network.Unregister(id + ":8000")
+	for otherID, replicator := range c.nodes {
+		if otherID != id {
+			replicator.members.Update(id, id+":8000", "dead", 0)
+		}
+	}
+}
+
+// owners returns the IDs of a key's owners, primary first, and of the nodes
+// after them in ring order
+func (c *testCluster) owners(key string) (owners, rest []string) {
+	for _, replicator := range c.nodes {
+		ownerNodes, restNodes := replicator.extendedPreferenceList(key, replicator.config.N)
+		for _, n := range ownerNodes {
+			owners = append(owners, n.ID)
+		}
+		for _, n := range restNodes {
+			rest = append(rest, n.ID)
+		}
+		return owners, rest
+	}
+	return nil, nil
+}
+
+// write coordinates a put on one node
+func (c *testCluster) write(coordinator, key, value string) (*WriteResult, error) {
+	var result *WriteResult
+	var err error
+	c.run(func() {
+		result, err = c.nodes[coordinator].WriteWithReplication(key, value, nil)
+	})
+	return result, err
+}
+
+// read coordinates a quorum read on one node
+func (c *testCluster) read(coordinator, key string) (*ReadResult, error) {
+	var result *ReadResult
+	var err error
+	c.run(func() {
+		result, err = c.nodes[coordinator].ReadWithQuorum(key, nil)
+	})
+	return result, err
+}
+
+func TestDeleteLeavesTombstoneOnEveryOwner(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c")
+	if _, err := c.write("a", "user:1", "v1"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	written, _ := c.nodes["b"].storage.GetVersion("user:1")
+
+	var err error
+	c.run(func() { _, err = c.nodes["a"].DeleteWithReplication("user:1", nil) })
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	c.run(func() { c.clock.Sleep(time.Second) })
+
+	tombstone, _ := c.nodes["a"].storage.GetVersion("user:1")
+	for id, replicator := range c.nodes {
+		stored, err := replicator.storage.GetVersion("user:1")
+		if err != nil || !stored.Deleted {
+			t.Errorf("%s holds %+v, %v; want a tombstone", id, stored, err)
+			continue
+		}
+		if stored.GetVectorClock().Compare(tombstone.GetVectorClock()) != storage.Equal {
+			t.Errorf("%s's tombstone clock %s, want the coordinator's %s", id, stored.GetVectorClock(), tombstone.GetVectorClock())
+		}
+	}
+
+	// The write the delete followed can't bring the key back
+	response := c.nodes["b"].HandleReplicationRequest(&ReplicationRequest{
+		Key:       "user:1",
+		Value:     "v1",
+		Operation: "put",
+		SourceEvent: &storage.Event{
+			ID:          "late",
+			Type:        "put",
+			Key:         "user:1",
+			Value:       "v1",
+			NodeID:      "a",
+			VectorClock: written.GetVectorClock(),
+			Timestamp:   written.Timestamp,
+		},
+	})
+	if !response.Success {
+		t.Errorf("stale put answered %+v, want it skipped successfully", response)
+	}
+	if stored, _ := c.nodes["b"].storage.GetVersion("user:1"); !stored.Deleted {
+		t.Errorf("stale put resurrected user:1 on b: %+v", stored)
+	}
+	if _, err := c.read("c", "user:1"); err != storage.ErrKeyNotFound {
+		t.Errorf("read after delete: %v, want ErrKeyNotFound", err)
+	}
+}
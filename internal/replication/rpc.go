@@ -0,0 +1,231 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+
+	"dynamodb/internal/node"
+	"dynamodb/internal/rpc"
+	"dynamodb/internal/rpc/internodepb"
+	"dynamodb/internal/storage"
+)
+
+// RegisterRPC exposes the replication service on a gRPC server
+func (r *Replicator) RegisterRPC(server *grpc.Server) {
+	internodepb.RegisterReplicationServer(server, &replicationServer{replicator: r})
+}
+
+// replicationServer serves the Replication gRPC service
+type replicationServer struct {
+	internodepb.UnimplementedReplicationServer
+	replicator *Replicator
+}
+
+func (s *replicationServer) Replicate(ctx context.Context, req *internodepb.ReplicateRequest) (*internodepb.ReplicateResponse, error) {
+	response := s.replicator.HandleReplicationRequest(requestFromProto(req))
+	return responseToProto(response), nil
+}
+
+func (s *replicationServer) ReplicateBatch(stream internodepb.Replication_ReplicateBatchServer) error {
+	r := s.replicator
+	response := &internodepb.BatchReplicateResponse{
+		NodeId: r.currentNode.ID,
+	}
+
+	// Apply mutations as they arrive; after the first failure the rest of
+	// the stream is drained so the sender can retry from there
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if response.Error != "" {
+			continue
+		}
+
+		result := r.HandleReplicationRequest(requestFromProto(req))
+		if !result.Success {
+			response.Error = fmt.Sprintf("%s %s: %s", req.Operation, req.Key, result.Error)
+			continue
+		}
+		response.Applied++
+	}
+
+	response.UpdatedClock = rpc.ClockToProto(r.storage.CurrentClock())
+	response.Timestamp = r.clock.Now().Unix()
+	return stream.SendAndClose(response)
+}
+
+func (s *replicationServer) FetchVersion(ctx context.Context, req *internodepb.FetchVersionRequest) (*internodepb.FetchVersionResponse, error) {
+	r := s.replicator
+	response := &internodepb.FetchVersionResponse{
+		NodeId: r.currentNode.ID,
+	}
+
+	value, err := r.storage.GetVersion(req.Key)
+	if err != nil {
+		return response, nil
+	}
+
+	response.Found = true
+	response.Version = rpc.VersionToProto(value)
+	return response, nil
+}
+
+func (s *replicationServer) Ping(ctx context.Context, req *internodepb.PingRequest) (*internodepb.PingResponse, error) {
+	return &internodepb.PingResponse{NodeId: s.replicator.currentNode.ID}, nil
+}
+
+// GRPCTransport sends replication traffic to the peers' Replication gRPC service
+type GRPCTransport struct {
+	client *rpc.Client
+}
+
+// NewGRPCTransport creates a replication transport on a gRPC connection pool
+func NewGRPCTransport(client *rpc.Client) *GRPCTransport {
+	return &GRPCTransport{client: client}
+}
+
+// replicationClient returns the Replication client for target
+func (t *GRPCTransport) replicationClient(target *node.Node) (internodepb.ReplicationClient, error) {
+	conn, err := t.client.Conn(target.Address)
+	if err != nil {
+		return nil, err
+	}
+	return internodepb.NewReplicationClient(conn), nil
+}
+
+// Replicate sends one mutation over gRPC
+func (t *GRPCTransport) Replicate(ctx context.Context, target *node.Node, request *ReplicationRequest) (*ReplicationResponse, error) {
+	client, err := t.replicationClient(target)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Replicate(ctx, requestToProto(request))
+	if err != nil {
+		return nil, err
+	}
+	return responseFromProto(response), nil
+}
+
+// ReplicateBatch streams queued mutations over gRPC, one message each
+func (t *GRPCTransport) ReplicateBatch(ctx context.Context, target *node.Node, batch *BatchReplicationRequest) (*BatchReplicationResponse, error) {
+	client, err := t.replicationClient(target)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := client.ReplicateBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, request := range batch.Requests {
+		// io.EOF means the server ended the stream; its status comes with CloseAndRecv
+		if err := stream.Send(requestToProto(request)); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	response, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+
+	return &BatchReplicationResponse{
+		Applied:      int(response.Applied),
+		NodeID:       response.NodeId,
+		Error:        response.Error,
+		UpdatedClock: rpc.ClockFromProto(response.UpdatedClock),
+		Timestamp:    response.Timestamp,
+	}, nil
+}
+
+// FetchVersion asks for a stored version over gRPC
+func (t *GRPCTransport) FetchVersion(ctx context.Context, target *node.Node, key string) (*storage.StorageValue, error) {
+	client, err := t.replicationClient(target)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.FetchVersion(ctx, &internodepb.FetchVersionRequest{Key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Found {
+		return nil, nil
+	}
+	return rpc.VersionFromProto(response.Version), nil
+}
+
+// Ping calls the target's Ping RPC
+func (t *GRPCTransport) Ping(ctx context.Context, target *node.Node) error {
+	client, err := t.replicationClient(target)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Ping(ctx, &internodepb.PingRequest{})
+	return err
+}
+
+func requestToProto(request *ReplicationRequest) *internodepb.ReplicateRequest {
+	return &internodepb.ReplicateRequest{
+		Key:         request.Key,
+		Value:       request.Value,
+		Operation:   request.Operation,
+		SourceNode:  request.SourceNode,
+		Timestamp:   request.Timestamp,
+		Events:      rpc.EventsToProto(request.Events),
+		VectorClock: rpc.ClockToProto(request.VectorClock),
+		SourceEvent: rpc.EventToProto(request.SourceEvent),
+		HintedFor:   request.HintedFor,
+	}
+}
+
+func requestFromProto(request *internodepb.ReplicateRequest) *ReplicationRequest {
+	return &ReplicationRequest{
+		Key:         request.Key,
+		Value:       request.Value,
+		Operation:   request.Operation,
+		SourceNode:  request.SourceNode,
+		Timestamp:   request.Timestamp,
+		Events:      rpc.EventsFromProto(request.Events),
+		VectorClock: rpc.ClockFromProto(request.VectorClock),
+		SourceEvent: rpc.EventFromProto(request.SourceEvent),
+		HintedFor:   request.HintedFor,
+	}
+}
+
+func responseToProto(response *ReplicationResponse) *internodepb.ReplicateResponse {
+	return &internodepb.ReplicateResponse{
+		Success:      response.Success,
+		Message:      response.Message,
+		NodeId:       response.NodeID,
+		Timestamp:    response.Timestamp,
+		Error:        response.Error,
+		UpdatedClock: rpc.ClockToProto(response.UpdatedClock),
+	}
+}
+
+func responseFromProto(response *internodepb.ReplicateResponse) *ReplicationResponse {
+	return &ReplicationResponse{
+		Success:      response.Success,
+		Message:      response.Message,
+		NodeID:       response.NodeId,
+		Timestamp:    response.Timestamp,
+		Error:        response.Error,
+		UpdatedClock: rpc.ClockFromProto(response.UpdatedClock),
+	}
+}
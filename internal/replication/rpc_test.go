@@ -0,0 +1,137 @@
+package replication
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"dynamodb/internal/membership"
+	"dynamodb/internal/node"
+	"dynamodb/internal/ring"
+	"dynamodb/internal/rpc"
+	"dynamodb/internal/storage"
+	"dynamodb/internal/transport"
+)
+
+// newRPCTarget serves a replicator's Replication service on a loopback port.
+// The returned node's address is the gRPC address itself, so clients reach it
+// with a port offset of 0.
+func newRPCTarget(t *testing.T, id string) (*Replicator, *node.Node) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	self := node.NewNode(id, listener.Addr().String())
+
+	localStorage, err := storage.NewLevelDBStorage(id, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLevelDBStorage: %v", err)
+	}
+	hashRing := ring.NewConsistentHashRing()
+	hashRing.AddNode(self)
+	replicator := NewReplicator(hashRing, localStorage, self, DefaultReplicationConfig(),
+		NewMemoryTransport(transport.NewNetwork(1), self.Address), membership.New(self, nil), nil)
+
+	server := rpc.NewServer()
+	replicator.RegisterRPC(server)
+	go server.Serve(listener)
+
+	t.Cleanup(func() {
+		server.Stop()
+		replicator.Stop()
+		localStorage.Close()
+	})
+	return replicator, self
+}
+
+func newRPCTransport(t *testing.T) *GRPCTransport {
+	t.Helper()
+
+	client := rpc.NewClient(0)
+	t.Cleanup(client.Close)
+	return NewGRPCTransport(client)
+}
+
+func TestGRPCReplicateBatchAppliesInOrder(t *testing.T) {
+	replicator, target := newRPCTarget(t, "b")
+	grpcTransport := newRPCTransport(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := grpcTransport.ReplicateBatch(ctx, target, &BatchReplicationRequest{
+		Requests: []*ReplicationRequest{
+			{Key: "user:1", Value: "v1", Operation: "put", SourceNode: "a"},
+			{Key: "user:2", Value: "v1", Operation: "put", SourceNode: "a"},
+			{Key: "user:1", Operation: "delete", SourceNode: "a"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ReplicateBatch: %v", err)
+	}
+	if response.Applied != 3 || response.Error != "" || response.NodeID != "b" {
+		t.Errorf("response = %+v, want 3 applied by b without error", response)
+	}
+	if response.UpdatedClock == nil || response.UpdatedClock.Clocks["b"] == 0 {
+		t.Errorf("updated clock %v doesn't carry b's counter", response.UpdatedClock)
+	}
+
+	// The delete left a tombstone, which reads back as such over gRPC
+	tombstone, err := grpcTransport.FetchVersion(ctx, target, "user:1")
+	if err != nil {
+		t.Fatalf("FetchVersion: %v", err)
+	}
+	if tombstone == nil || !tombstone.Deleted {
+		t.Errorf("user:1 fetched as %+v, want a tombstone", tombstone)
+	}
+	if value, err := replicator.storage.GetVersion("user:2"); err != nil || value.Value != "v1" {
+		t.Errorf("user:2 on b = %v, %v; want v1", value, err)
+	}
+}
+
+func TestGRPCReplicateBatchStopsAtFirstFailure(t *testing.T) {
+	replicator, target := newRPCTarget(t, "b")
+	grpcTransport := newRPCTransport(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := grpcTransport.ReplicateBatch(ctx, target, &BatchReplicationRequest{
+		Requests: []*ReplicationRequest{
+			{Key: "user:1", Value: "v1", Operation: "put", SourceNode: "a"},
+			{Key: "user:2", Value: "v1", Operation: "rename", SourceNode: "a"},
+			{Key: "user:3", Value: "v1", Operation: "put", SourceNode: "a"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ReplicateBatch: %v", err)
+	}
+	if response.Applied != 1 || !strings.Contains(response.Error, "rename user:2") {
+		t.Errorf("response = %+v, want 1 applied and an error naming the rename", response)
+	}
+
+	// Nothing after the failure is applied, so the sender can resume from it
+	if _, err := replicator.storage.GetVersion("user:3"); err == nil {
+		t.Errorf("user:3 was applied after the failed mutation")
+	}
+}
+
+func TestGRPCFetchVersionOfMissingKey(t *testing.T) {
+	_, target := newRPCTarget(t, "b")
+	grpcTransport := newRPCTransport(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	version, err := grpcTransport.FetchVersion(ctx, target, "user:1")
+	if err != nil || version != nil {
+		t.Errorf("FetchVersion of a missing key = %+v, %v; want nil, nil", version, err)
+	}
+	if err := grpcTransport.Ping(ctx, target); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package replication
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"dynamodb/internal/node"
+)
+
+// SetSloppyQuorum enables or disables writing to stand-ins for dead replicas
+func (r *Replicator) SetSloppyQuorum(enabled bool) {
+	r.configMutex.Lock()
+	defer r.configMutex.Unlock()
+	r.sloppyQuorum = enabled
+}
+
+// IsSloppyQuorum reports whether writes may use stand-in replicas
+func (r *Replicator) IsSloppyQuorum() bool {
+	r.configMutex.RLock()
+	defer r.configMutex.RUnlock()
+	return r.sloppyQuorum
+}
+
+// Owners returns the N nodes responsible for a key, primary first
+func (r *Replicator) Owners(key string) []*node.Node {
+	return r.ring.GetNodesForKey(key, r.ConfigForKey(key).N)
+}
+
+// IsOwner reports whether this node is one of the key's N replicas
+func (r *Replicator) IsOwner(key string) bool {
+	for _, owner := range r.Owners(key) {
+		if owner.ID == r.currentNode.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// extendedPreferenceList returns every node in ring order from the key's
+// position. The first n entries are the key's owners; the rest are the
+// stand-in candidates used when an owner is down.
+func (r *Replicator) extendedPreferenceList(key string, n int) (owners, standIns []*node.Node) {
+	nodes := r.ring.GetNodesForKey(key, len(r.ring.GetAllNodes()))
+	if len(nodes) <= n {
+		return nodes, nil
+	}
+	return nodes[:n], nodes[n:]
+}
+
+// standInPool hands out stand-in candidates to concurrent replications so
+// each candidate stands in for at most one owner per write
+type standInPool struct {
+	candidates []*node.Node
+	used       map[string]bool
+	mu         sync.Mutex
+}
+
+func newStandInPool(owners, candidates []*node.Node) *standInPool {
+	used := make(map[string]bool)
+	for _, owner := range owners {
+		used[owner.ID] = true
+	}
+	return &standInPool{candidates: candidates, used: used}
+}
+
+// next returns the first healthy candidate not handed out yet, or nil. The
+// coordinator is a candidate like any other node, so writes and reads
+// coordinated anywhere pick the same stand-ins for the same dead owners.
+func (p *standInPool) next(r *Replicator) *node.Node {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, candidate := range p.candidates {
+		if p.used[candidate.ID] {
+			continue
+		}
+		if candidate.ID != r.currentNode.ID && !r.isNodeAlive(candidate.ID) {
+			continue
+		}
+		p.used[candidate.ID] = true
+		return candidate
+	}
+	return nil
+}
+
+// writeToStandIn sends the mutation to healthy candidates in ring order,
+// tagged with the owner it stands in for, until one accepts it. Returns the
+// stand-in's ID ("" if none accepted it) and the latency of each attempt.
+func (r *Replicator) writeToStandIn(ownerID string, pool *standInPool, request ReplicationRequest) (string, map[string]time.Duration) {
+	request.HintedFor = ownerID
+	latencies := make(map[string]time.Duration)
+
+	for candidate := pool.next(r); candidate != nil; candidate = pool.next(r) {
+		start := r.clock.Now()
+		var ok bool
+		if candidate.ID == r.currentNode.ID {
+			ok = r.acceptHandoff(&request).Success
+		} else {
+			ok = r.replicateToNode(candidate, &request)
+		}
+		latencies[candidate.ID] = r.clock.Since(start)
+
+		if ok {
+			fmt.Printf("🔀 %s stood in for %s on key %s\n", candidate.ID, ownerID, request.Key)
+			return candidate.ID, latencies
+		}
+	}
+
+	return "", latencies
+}
+
+// acceptHandoff stores a mutation this node received as a stand-in. The
+// mutation is applied locally, so quorum reads that ask this node in place of
+// the owner see it, and kept in the hint store, tagged with its owner, for
+// the regular hint replay to deliver once the owner is reachable.
+func (r *Replicator) acceptHandoff(req *ReplicationRequest) *ReplicationResponse {
+	owner := req.HintedFor
+	handoff := *req
+	handoff.HintedFor = ""
+
+	if applied := r.HandleReplicationRequest(&handoff); !applied.Success {
+		return applied
+	}
+	if !r.storeHint(owner, &handoff) {
+		return &ReplicationResponse{
+			Success:   false,
+			Message:   "Stand-in write failed",
+			NodeID:    r.currentNode.ID,
+			Timestamp: r.clock.Now().Unix(),
+			Error:     "hinted handoff unavailable on " + r.currentNode.ID,
+		}
+	}
+
+	fmt.Printf("🔀 Holding %s %s for %s until it returns\n", req.Operation, req.Key, owner)
+	return &ReplicationResponse{
+		Success:   true,
+		Message:   "Stored as stand-in for " + owner,
+		NodeID:    r.currentNode.ID,
+		Timestamp: r.clock.Now().Unix(),
+	}
+}
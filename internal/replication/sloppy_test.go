@@ -0,0 +1,113 @@
+package replication
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStandInAcksCountTowardsW(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c", "d", "e")
+	owners, rest := c.owners("user:1")
+	c.crash(owners[1])
+	c.crash(owners[2])
+
+	result, err := c.write(owners[0], "user:1", "v1")
+	if err != nil {
+		t.Fatalf("write with two owners down: %v", err)
+	}
+
+	// The write returns at W acks: the owner and whichever stand-in answered
+	if len(result.SuccessfulNodes) != 2 || result.SuccessfulNodes[0] != owners[0] {
+		t.Fatalf("successful nodes = %v, want %s and a stand-in", result.SuccessfulNodes, owners[0])
+	}
+	standIn := result.SuccessfulNodes[1]
+	if standIn != rest[0] && standIn != rest[1] {
+		t.Errorf("stand-in %s isn't one of the next healthy nodes %v", standIn, rest[:2])
+	}
+	if len(result.StandIns) != 1 {
+		t.Errorf("stand-ins = %v, want the one that acknowledged", result.StandIns)
+	}
+	for owner, got := range result.StandIns {
+		if got != standIn || (owner != owners[1] && owner != owners[2]) {
+			t.Errorf("stand-ins = %v, want %s standing in for a dead owner", result.StandIns, standIn)
+		}
+	}
+
+	// Only one owner has it, so the write isn't reported like an owner quorum
+	if result.Status != WriteStatusSloppy || result.OwnerAcks != 1 || result.StandInAcks != 1 {
+		t.Errorf("status %s with %d owner and %d stand-in acks, want sloppy with 1 and 1",
+			result.Status, result.OwnerAcks, result.StandInAcks)
+	}
+}
+
+func TestQuorumReadAsksStandIns(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c", "d", "e")
+	owners, rest := c.owners("user:1")
+	c.crash(owners[1])
+	c.crash(owners[2])
+
+	if _, err := c.write(owners[0], "user:1", "v1"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// Every coordinator, the stand-ins included, finds the write
+	for _, coordinator := range []string{owners[0], rest[0], rest[1]} {
+		result, err := c.read(coordinator, "user:1")
+		if err != nil {
+			t.Fatalf("read on %s: %v", coordinator, err)
+		}
+		if result.Value != "v1" {
+			t.Errorf("read on %s = %q, want v1", coordinator, result.Value)
+		}
+		if _, ok := result.Responses[rest[0]]; !ok {
+			t.Errorf("read on %s didn't ask stand-in %s: responses from %v", coordinator, rest[0], result.Responses)
+		}
+	}
+}
+
+func TestStandInWriteReadableAfterOwnerRecovers(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 3, R: 3, W: 2}, "a", "b", "c", "d")
+	owners, _ := c.owners("user:1")
+	c.crash(owners[2])
+
+	if _, err := c.write(owners[0], "user:1", "v1"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// The returning owner gets its copy from the stand-in's hint
+	c.nodes[owners[2]].RegisterMemory(c.network)
+	for id, replicator := range c.nodes {
+		if id != owners[2] {
+			replicator.members.Update(owners[2], owners[2]+":8000", "alive", 1)
+		}
+	}
+	c.run(func() { c.clock.Sleep(2 * hintRetryInterval) })
+
+	value, err := c.nodes[owners[2]].storage.Get("user:1")
+	if err != nil || value.Value != "v1" {
+		t.Fatalf("owner %s after recovery: %v, %v; want v1", owners[2], value, err)
+	}
+}
+
+func TestStrictQuorumIgnoresStandIns(t *testing.T) {
+	c := newTestCluster(t, ReplicationConfig{N: 3, R: 2, W: 2}, "a", "b", "c", "d", "e")
+	for _, replicator := range c.nodes {
+		replicator.SetSloppyQuorum(false)
+	}
+	owners, _ := c.owners("user:1")
+	c.crash(owners[1])
+	c.crash(owners[2])
+
+	result, err := c.write(owners[0], "user:1", "v1")
+	var quorumErr *QuorumError
+	if !errors.As(err, &quorumErr) {
+		t.Fatalf("write with two owners down = %v, want a QuorumError", err)
+	}
+	if len(result.StandIns) != 0 || len(result.HintedNodes) != 2 {
+		t.Errorf("stand-ins = %v, hinted = %v; want no stand-ins and both owners hinted", result.StandIns, result.HintedNodes)
+	}
+
+	if _, err := c.read(owners[0], "user:1"); err == nil {
+		t.Errorf("read with two owners down succeeded without sloppy quorum")
+	}
+}
@@ -0,0 +1,129 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"dynamodb/internal/node"
+	"dynamodb/internal/storage"
+	"dynamodb/internal/transport"
+)
+
+// Transport carries replication traffic to other nodes. Every call is bounded
+// by ctx.
+type Transport interface {
+	// Replicate applies a single mutation on target
+	Replicate(ctx context.Context, target *node.Node, request *ReplicationRequest) (*ReplicationResponse, error)
+	// ReplicateBatch applies queued mutations on target in order, stopping at
+	// the first failure
+	ReplicateBatch(ctx context.Context, target *node.Node, batch *BatchReplicationRequest) (*BatchReplicationResponse, error)
+	// FetchVersion returns target's stored version of key, or nil if target
+	// doesn't have it
+	FetchVersion(ctx context.Context, target *node.Node, key string) (*storage.StorageValue, error)
+	// Ping checks that target is up
+	Ping(ctx context.Context, target *node.Node) error
+}
+
+// HTTPTransport sends replication traffic as JSON to the /internal endpoints
+type HTTPTransport struct {
+	client *transport.Client
+}
+
+// NewHTTPTransport creates a replication transport on the shared HTTP client
+func NewHTTPTransport(client *transport.Client) *HTTPTransport {
+	if client == nil {
+		client = transport.NewClient(nil)
+	}
+	return &HTTPTransport{client: client}
+}
+
+// Replicate posts one mutation to the target's /internal/replicate endpoint
+func (t *HTTPTransport) Replicate(ctx context.Context, target *node.Node, request *ReplicationRequest) (*ReplicationResponse, error) {
+	url := fmt.Sprintf("http://%s/internal/replicate", target.Address)
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal replication request: %v", err)
+	}
+
+	resp, err := t.client.Post(ctx, url, "application/json", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response ReplicationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode replication response: %v", err)
+	}
+	return &response, nil
+}
+
+// ReplicateBatch posts a batch to the target's /internal/replicate/batch endpoint
+func (t *HTTPTransport) ReplicateBatch(ctx context.Context, target *node.Node, batch *BatchReplicationRequest) (*BatchReplicationResponse, error) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("http://%s/internal/replicate/batch", target.Address)
+	resp, err := t.client.Post(ctx, url, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response BatchReplicationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode batch response from %s: %v", target.ID, err)
+	}
+	return &response, nil
+}
+
+// FetchVersion reads the target's /internal/data/{key} endpoint
+func (t *HTTPTransport) FetchVersion(ctx context.Context, target *node.Node, key string) (*storage.StorageValue, error) {
+	endpoint := fmt.Sprintf("http://%s/internal/data/%s", target.Address, url.PathEscape(key))
+
+	resp, err := t.client.Get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Found   bool                  `json:"found"`
+		Version *storage.StorageValue `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode version: %v", err)
+	}
+
+	if !response.Found {
+		return nil, nil
+	}
+	return response.Version, nil
+}
+
+// Ping checks the target's /api/v1/status endpoint
+func (t *HTTPTransport) Ping(ctx context.Context, target *node.Node) error {
+	url := fmt.Sprintf("http://%s/api/v1/status", target.Address)
+
+	resp, err := t.client.Get(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -15,6 +15,14 @@ type VirtualNode struct {
 	NodeID string
 }
 
+// RingRange represents the slice of the hash space owned by one virtual node,
+// together with the physical nodes that replicate keys falling into it
+type RingRange struct {
+	Start    uint32   `json:"start"` // Exclusive
+	End      uint32   `json:"end"`   // Inclusive
+	Replicas []string `json:"replicas"`
+}
+
 // ConsistentHashRing implements consistent hashing with virtual nodes
 type ConsistentHashRing struct {
 	mu           sync.RWMutex
@@ -146,6 +154,63 @@ func (chr *ConsistentHashRing) GetNodesForKey(key string, replicationFactor int)
 	return nodes
 }
 
+// GetRanges returns every range of the ring in hash order, each with the
+// preference list of replicationFactor nodes responsible for it
+func (chr *ConsistentHashRing) GetRanges(replicationFactor int) []RingRange {
+	chr.mu.RLock()
+	defer chr.mu.RUnlock()
+
+	if len(chr.virtualNodes) == 0 {
+		return nil
+	}
+
+	ranges := make([]RingRange, 0, len(chr.virtualNodes))
+	for i, vn := range chr.virtualNodes {
+		// The first range wraps around from the last virtual node
+		prev := chr.virtualNodes[len(chr.virtualNodes)-1]
+		if i > 0 {
+			prev = chr.virtualNodes[i-1]
+		}
+
+		replicas := make([]string, 0, replicationFactor)
+		seenNodes := make(map[string]bool)
+		for j := i; len(replicas) < replicationFactor && len(seenNodes) < len(chr.nodes); j++ {
+			nodeID := chr.virtualNodes[j%len(chr.virtualNodes)].NodeID
+			if !seenNodes[nodeID] {
+				replicas = append(replicas, nodeID)
+				seenNodes[nodeID] = true
+			}
+		}
+
+		ranges = append(ranges, RingRange{
+			Start:    prev.Hash,
+			End:      vn.Hash,
+			Replicas: replicas,
+		})
+	}
+
+	return ranges
+}
+
+// FindRange returns the index of the range in ranges (as returned by
+// GetRanges) that contains the given key
+func (chr *ConsistentHashRing) FindRange(ranges []RingRange, key string) int {
+	if len(ranges) == 0 {
+		return -1
+	}
+
+	hash := chr.hash(key)
+	idx := sort.Search(len(ranges), func(i int) bool {
+		return ranges[i].End >= hash
+	})
+
+	// Keys past the last virtual node belong to the wrap-around range
+	if idx == len(ranges) {
+		idx = 0
+	}
+	return idx
+}
+
 // GetAllNodes returns all physical nodes in the ring
 func (chr *ConsistentHashRing) GetAllNodes() []*node.Node {
 	chr.mu.RLock()
@@ -0,0 +1,156 @@
+package rpc
+
+import (
+	"dynamodb/internal/rpc/internodepb"
+	"dynamodb/internal/storage"
+)
+
+// ClockToProto converts a vector clock to its wire form
+func ClockToProto(clock *storage.VectorClock) *internodepb.VectorClock {
+	if clock == nil {
+		return nil
+	}
+
+	clocks := make(map[string]int64, len(clock.Clocks))
+	for nodeID, counter := range clock.Clocks {
+		clocks[nodeID] = counter
+	}
+	return &internodepb.VectorClock{Clocks: clocks}
+}
+
+// ClockFromProto converts a wire vector clock back to storage form
+func ClockFromProto(clock *internodepb.VectorClock) *storage.VectorClock {
+	if clock == nil {
+		return nil
+	}
+
+	result := storage.NewVectorClock()
+	for nodeID, counter := range clock.Clocks {
+		result.Clocks[nodeID] = counter
+	}
+	return result
+}
+
+// EventToProto converts an event to its wire form
+func EventToProto(event *storage.Event) *internodepb.Event {
+	if event == nil {
+		return nil
+	}
+
+	return &internodepb.Event{
+		Id:          event.ID,
+		Type:        event.Type,
+		Key:         event.Key,
+		Value:       event.Value,
+		NodeId:      event.NodeID,
+		VectorClock: ClockToProto(event.VectorClock),
+		Timestamp:   event.Timestamp,
+		CausalHash:  event.CausalHash,
+	}
+}
+
+// EventFromProto converts a wire event back to storage form
+func EventFromProto(event *internodepb.Event) *storage.Event {
+	if event == nil {
+		return nil
+	}
+
+	return &storage.Event{
+		ID:          event.Id,
+		Type:        event.Type,
+		Key:         event.Key,
+		Value:       event.Value,
+		NodeID:      event.NodeId,
+		VectorClock: ClockFromProto(event.VectorClock),
+		Timestamp:   event.Timestamp,
+		CausalHash:  event.CausalHash,
+	}
+}
+
+// EventsToProto converts a list of events to wire form
+func EventsToProto(events []*storage.Event) []*internodepb.Event {
+	if len(events) == 0 {
+		return nil
+	}
+
+	result := make([]*internodepb.Event, len(events))
+	for i, event := range events {
+		result[i] = EventToProto(event)
+	}
+	return result
+}
+
+// EventsFromProto converts a list of wire events back to storage form
+func EventsFromProto(events []*internodepb.Event) []*storage.Event {
+	if len(events) == 0 {
+		return nil
+	}
+
+	result := make([]*storage.Event, len(events))
+	for i, event := range events {
+		result[i] = EventFromProto(event)
+	}
+	return result
+}
+
+// VersionToProto converts a stored version to its wire form
+func VersionToProto(version *storage.StorageValue) *internodepb.StoredVersion {
+	if version == nil {
+		return nil
+	}
+
+	return &internodepb.StoredVersion{
+		Value:       version.Value,
+		Timestamp:   version.Timestamp,
+		Version:     int64(version.Version),
+		Metadata:    version.Metadata,
+		VectorClock: ClockToProto(version.VectorClock),
+		Deleted:     version.Deleted,
+	}
+}
+
+// VersionFromProto converts a wire version back to storage form
+func VersionFromProto(version *internodepb.StoredVersion) *storage.StorageValue {
+	if version == nil {
+		return nil
+	}
+
+	metadata := version.Metadata
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+
+	return &storage.StorageValue{
+		Value:       version.Value,
+		Timestamp:   version.Timestamp,
+		Version:     int(version.Version),
+		Metadata:    metadata,
+		VectorClock: ClockFromProto(version.VectorClock),
+		Deleted:     version.Deleted,
+	}
+}
+
+// LeafToProto converts a Merkle leaf to its wire form
+func LeafToProto(leaf *storage.MerkleNode) *internodepb.MerkleLeaf {
+	return &internodepb.MerkleLeaf{
+		Key:         leaf.Key,
+		Hash:        leaf.Hash,
+		Value:       leaf.Value,
+		ValueHash:   leaf.ValueHash,
+		VectorClock: ClockToProto(leaf.VectorClock),
+		Deleted:     leaf.Deleted,
+	}
+}
+
+// LeafFromProto converts a wire Merkle leaf back to storage form
+func LeafFromProto(leaf *internodepb.MerkleLeaf) *storage.MerkleNode {
+	return &storage.MerkleNode{
+		Hash:        leaf.Hash,
+		IsLeaf:      true,
+		Key:         leaf.Key,
+		Value:       leaf.Value,
+		ValueHash:   leaf.ValueHash,
+		VectorClock: ClockFromProto(leaf.VectorClock),
+		Deleted:     leaf.Deleted,
+	}
+}
@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"reflect"
+	"testing"
+
+	"dynamodb/internal/storage"
+)
+
+func clockOf(counters map[string]int64) *storage.VectorClock {
+	clock := storage.NewVectorClock()
+	for nodeID, counter := range counters {
+		clock.Clocks[nodeID] = counter
+	}
+	return clock
+}
+
+func TestVersionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		version *storage.StorageValue
+	}{
+		{
+			name: "value",
+			version: &storage.StorageValue{
+				Value:       "v1",
+				Timestamp:   1700000000,
+				Version:     3,
+				Metadata:    map[string]string{"content-type": "text/plain"},
+				VectorClock: clockOf(map[string]int64{"a": 2, "b": 1}),
+			},
+		},
+		{
+			name: "tombstone",
+			version: &storage.StorageValue{
+				Timestamp:   1700000001,
+				Version:     4,
+				Metadata:    map[string]string{},
+				VectorClock: clockOf(map[string]int64{"a": 3, "b": 1}),
+				Deleted:     true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VersionFromProto(VersionToProto(tt.version))
+			if !reflect.DeepEqual(got, tt.version) {
+				t.Errorf("round trip gave %+v, want %+v", got, tt.version)
+			}
+		})
+	}
+
+	if VersionFromProto(VersionToProto(nil)) != nil {
+		t.Errorf("nil version didn't round-trip to nil")
+	}
+}
+
+// Proto drops empty maps; storage code expects Metadata to be writable
+func TestVersionFromProtoAlwaysHasMetadata(t *testing.T) {
+	got := VersionFromProto(VersionToProto(&storage.StorageValue{Value: "v1"}))
+	if got.Metadata == nil {
+		t.Errorf("metadata is nil after the round trip")
+	}
+}
+
+func TestLeafRoundTrip(t *testing.T) {
+	for _, deleted := range []bool{false, true} {
+		leaf := &storage.MerkleNode{
+			Hash:        "leaf-hash",
+			IsLeaf:      true,
+			Key:         "user:1",
+			Value:       "v1",
+			ValueHash:   "value-hash",
+			VectorClock: clockOf(map[string]int64{"a": 1}),
+			Deleted:     deleted,
+		}
+		if got := LeafFromProto(LeafToProto(leaf)); !reflect.DeepEqual(got, leaf) {
+			t.Errorf("round trip gave %+v, want %+v", got, leaf)
+		}
+	}
+}
+
+func TestEventsRoundTrip(t *testing.T) {
+	events := []*storage.Event{
+		{ID: "e1", Type: "put", Key: "user:1", Value: "v1", NodeID: "a",
+			VectorClock: clockOf(map[string]int64{"a": 1}), Timestamp: 1, CausalHash: "h1"},
+		{ID: "e2", Type: "delete", Key: "user:1", NodeID: "b",
+			VectorClock: clockOf(map[string]int64{"a": 1, "b": 1}), Timestamp: 2, CausalHash: "h2"},
+	}
+
+	if got := EventsFromProto(EventsToProto(events)); !reflect.DeepEqual(got, events) {
+		t.Errorf("round trip gave %+v, want %+v", got, events)
+	}
+	if got := EventsFromProto(EventsToProto(nil)); got != nil {
+		t.Errorf("no events round-tripped to %v", got)
+	}
+}
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+		wantErr bool
+	}{
+		{address: "127.0.0.1:8080", want: "127.0.0.1:9080"},
+		{address: "[::1]:8080", want: "[::1]:9080"},
+		{address: "node-1", wantErr: true},
+		{address: "node-1:http", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Address(tt.address, DefaultPortOffset)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Address(%q) = %q, %v; want %q, error %v", tt.address, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
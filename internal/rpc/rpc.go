@@ -0,0 +1,123 @@
+// Package rpc carries node-to-node traffic (replication, read repair, Merkle
+// exchange and gossip) over gRPC as an alternative to the JSON/HTTP endpoints.
+//
+// Nodes are addressed by their HTTP address everywhere else in the cluster, so
+// a peer's gRPC endpoint is derived from it: same host, HTTP port plus a port
+// offset that every node in the cluster shares.
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative internodepb/internode.proto
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// DefaultPortOffset is added to a node's HTTP port to get its gRPC port
+const DefaultPortOffset = 1000
+
+// Transport names accepted by the --internal-transport flag
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+// Address returns the gRPC address of the node listening for HTTP on httpAddress
+func Address(httpAddress string, portOffset int) (string, error) {
+	host, portStr, err := net.SplitHostPort(httpAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid node address %q: %v", httpAddress, err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid port in node address %q: %v", httpAddress, err)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port+portOffset)), nil
+}
+
+// Client keeps one multiplexed gRPC connection per peer
+type Client struct {
+	portOffset int
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewClient creates a connection pool for peers serving gRPC at their HTTP
+// port plus portOffset
+func NewClient(portOffset int) *Client {
+	return &Client{
+		portOffset: portOffset,
+		conns:      make(map[string]*grpc.ClientConn),
+	}
+}
+
+// Conn returns the connection to the peer at httpAddress, dialing it on first
+// use. Dialing is lazy: connection errors surface on the first call.
+func (c *Client) Conn(httpAddress string) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if conn, exists := c.conns[httpAddress]; exists {
+		return conn, nil
+	}
+
+	target, err := Address(httpAddress, c.portOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    30 * time.Second,
+			Timeout: 5 * time.Second,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %v", target, err)
+	}
+
+	c.conns[httpAddress] = conn
+	return conn, nil
+}
+
+// Close closes every peer connection
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for address, conn := range c.conns {
+		conn.Close()
+		delete(c.conns, address)
+	}
+}
+
+// NewServer creates the gRPC server the internal services register on
+func NewServer() *grpc.Server {
+	return grpc.NewServer(
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
+	)
+}
+
+// Serve listens on address and serves gRPC until the server is stopped
+func Serve(server *grpc.Server, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", address, err)
+	}
+
+	return server.Serve(listener)
+}
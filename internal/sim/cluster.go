@@ -0,0 +1,425 @@
+// Package sim runs a whole cluster inside one process on simulated time. Nodes
+// talk over an in-memory network, every timer runs on one simulated clock and
+// all randomness comes from the seed. The seed fixes the scenario (writes and
+// faults, in order and in simulated time), not the run: the nodes' goroutines
+// run concurrently on the Go runtime, so two runs of a seed can interleave
+// them differently and reach different outcomes.
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"dynamodb/internal/clock"
+	"dynamodb/internal/gossip"
+	"dynamodb/internal/membership"
+	"dynamodb/internal/node"
+	"dynamodb/internal/replication"
+	"dynamodb/internal/ring"
+	"dynamodb/internal/storage"
+	"dynamodb/internal/transport"
+)
+
+// simEpoch is where simulated time starts, so traces don't depend on the wall clock
+var simEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Config describes a simulated cluster
+type Config struct {
+	Nodes       int
+	Seed        int64
+	Step        time.Duration // Simulated time per step
+	Latency     time.Duration // Network latency per call
+	Jitter      time.Duration // Random extra latency per call
+	Replication *replication.ReplicationConfig
+	DataDir     string      // Node data lives here (default: a temp dir removed on Close)
+	Invariants  []Invariant // Checked after every step, on top of the defaults
+}
+
+// DefaultConfig returns a three node cluster stepping 100ms at a time
+func DefaultConfig() *Config {
+	return &Config{
+		Nodes:       3,
+		Seed:        1,
+		Step:        100 * time.Millisecond,
+		Latency:     time.Millisecond,
+		Jitter:      2 * time.Millisecond,
+		Replication: replication.DefaultReplicationConfig(),
+	}
+}
+
+// Node is one simulated cluster member. Its storage survives crashes; the
+// rest is rebuilt on restart.
+type Node struct {
+	ID      string
+	Address string
+	Up      bool
+
+	Storage    *storage.LevelDBStorage
+	Ring       *ring.ConsistentHashRing
+	Members    *membership.Service
+	Replicator *replication.Replicator
+	Gossip     *gossip.GossipManager
+
+	// Values this node held when it crashed (its data is on disk)
+	heldAtCrash map[string]string
+	// Highest vector clock seen since the node last started
+	lastClock *storage.VectorClock
+}
+
+// AckedWrite is a write the cluster acknowledged to a client
+type AckedWrite struct {
+	Key         string
+	Value       string
+	Coordinator string
+	Step        int
+}
+
+// Cluster is a simulated cluster driven step by step
+type Cluster struct {
+	config  *Config
+	Clock   *clock.Simulated
+	Network *transport.Network
+
+	dataDir     string
+	removeData  bool
+	nodes       []*Node
+	byID        map[string]*Node
+	rng         *rand.Rand
+	step        int
+	trace       []string
+	violations  []Violation
+	invariants  []Invariant
+	reported    map[string]bool
+	pendingOps  int
+	writeSerial int
+
+	mu    sync.Mutex
+	acked []*AckedWrite
+}
+
+// NewCluster creates and starts a simulated cluster. Node 1 is the seed the
+// others join through.
+func NewCluster(config *Config) (*Cluster, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if config.Nodes < 1 {
+		return nil, fmt.Errorf("a simulated cluster needs at least one node, got %d", config.Nodes)
+	}
+	if config.Step <= 0 {
+		config.Step = 100 * time.Millisecond
+	}
+	if config.Replication == nil {
+		config.Replication = replication.DefaultReplicationConfig()
+	}
+
+	dataDir := config.DataDir
+	removeData := false
+	if dataDir == "" {
+		dir, err := os.MkdirTemp("", "dynamodb-sim-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create simulation data dir: %v", err)
+		}
+		dataDir = dir
+		removeData = true
+	}
+
+	c := &Cluster{
+		config:     config,
+		Clock:      clock.NewSimulated(simEpoch),
+		Network:    transport.NewNetwork(config.Seed),
+		dataDir:    dataDir,
+		removeData: removeData,
+		byID:       make(map[string]*Node),
+		rng:        rand.New(rand.NewSource(config.Seed)),
+		invariants: append(DefaultInvariants(), config.Invariants...),
+		reported:   make(map[string]bool),
+	}
+	c.Network.SetClock(c.Clock)
+	c.Network.SetLatency(config.Latency, config.Jitter)
+
+	for i := 1; i <= config.Nodes; i++ {
+		n := &Node{
+			ID:      fmt.Sprintf("node-%d", i),
+			Address: fmt.Sprintf("sim-node-%d:8080", i),
+		}
+		c.nodes = append(c.nodes, n)
+		c.byID[n.ID] = n
+
+		if err := c.start(n); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	c.Clock.Settle()
+	return c, nil
+}
+
+// start opens a node's storage and starts its replicator and gossip, joining
+// through the lowest numbered live node
+func (c *Cluster) start(n *Node) error {
+	localStorage, err := storage.NewLevelDBStorage(n.ID, c.dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to open storage for %s: %v", n.ID, err)
+	}
+	localStorage.SetClock(c.Clock)
+
+	currentNode := node.NewNode(n.ID, n.Address)
+	hashRing := ring.NewConsistentHashRing()
+	hashRing.AddNode(currentNode)
+
+	members := membership.New(currentNode, c.Clock)
+	members.FeedRing(hashRing)
+
+	replicationConfig := *c.config.Replication
+	replicator := replication.NewReplicator(hashRing, localStorage, currentNode, &replicationConfig,
+		replication.NewMemoryTransport(c.Network, n.Address), members, c.Clock)
+
+	gossipConfig := gossip.DefaultGossipConfig()
+	gossipConfig.Clock = c.Clock
+	gossipConfig.Seed = c.rng.Int63n(1<<62) + 1
+	gossipManager := gossip.NewGossipManager(currentNode, gossipConfig, gossip.NewMemoryTransport(c.Network, n.Address))
+
+	gossipManager.SetMembership(members)
+
+	n.Storage = localStorage
+	n.Ring = hashRing
+	n.Members = members
+	n.Replicator = replicator
+	n.Gossip = gossipManager
+	n.Up = true
+	n.heldAtCrash = nil
+	n.lastClock = localStorage.CurrentClock()
+
+	replicator.RegisterMemory(c.Network)
+	gossipManager.RegisterMemory(c.Network)
+	gossipManager.Start()
+
+	for _, seed := range c.nodes {
+		if seed != n && seed.Up {
+			gossipManager.AddSeeds(seed.Address)
+			break
+		}
+	}
+	return nil
+}
+
+// Nodes returns the cluster members in ID order
+func (c *Cluster) Nodes() []*Node {
+	return c.nodes
+}
+
+// Node returns a member by ID
+func (c *Cluster) Node(id string) *Node {
+	return c.byID[id]
+}
+
+// LiveNodes returns the members that are up
+func (c *Cluster) LiveNodes() []*Node {
+	live := make([]*Node, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		if n.Up {
+			live = append(live, n)
+		}
+	}
+	return live
+}
+
+// Step advances simulated time by one step and checks the invariants
+func (c *Cluster) Step() {
+	c.step++
+	c.Clock.Advance(c.config.Step)
+	c.check(c.invariants)
+}
+
+// Run advances the cluster by steps
+func (c *Cluster) Run(steps int) {
+	for i := 0; i < steps; i++ {
+		c.Step()
+	}
+}
+
+// Crash stops a node without warning. Its data stays on disk.
+func (c *Cluster) Crash(id string) error {
+	n := c.byID[id]
+	if n == nil {
+		return fmt.Errorf("unknown node %s", id)
+	}
+	if !n.Up {
+		return fmt.Errorf("%s is already down", id)
+	}
+
+	c.logf("💥 crash %s", id)
+	c.Network.Unregister(n.Address)
+
+	// Remember what the node held so durability checks count its disk, also
+	// for writes that are acknowledged after the crash
+	held := make(map[string]string)
+	keys, _ := n.Storage.ListKeys()
+	for _, key := range keys {
+		if version, err := n.Storage.GetVersion(key); err == nil && !version.Deleted {
+			held[key] = version.Value
+		}
+	}
+
+	n.Up = false
+	n.heldAtCrash = held
+	n.Gossip.Stop()
+	n.Replicator.Stop()
+	n.Storage.Close()
+
+	c.Clock.Settle()
+	return nil
+}
+
+// Restart brings a crashed node back on its old data
+func (c *Cluster) Restart(id string) error {
+	n := c.byID[id]
+	if n == nil {
+		return fmt.Errorf("unknown node %s", id)
+	}
+	if n.Up {
+		return fmt.Errorf("%s is already up", id)
+	}
+
+	c.logf("🔄 restart %s", id)
+	if err := c.start(n); err != nil {
+		return err
+	}
+
+	c.Clock.Settle()
+	return nil
+}
+
+// Partition splits the cluster into groups of node IDs that can't reach each other
+func (c *Cluster) Partition(groups ...[]string) {
+	c.logf("✂️ partition %v", groups)
+
+	addressGroups := make([][]string, len(groups))
+	for i, group := range groups {
+		for _, id := range group {
+			if n := c.byID[id]; n != nil {
+				addressGroups[i] = append(addressGroups[i], n.Address)
+			}
+		}
+	}
+	c.Network.Partition(addressGroups...)
+}
+
+// Heal removes all partitions
+func (c *Cluster) Heal() {
+	c.logf("🩹 heal")
+	c.Network.Heal()
+}
+
+// SetDropRate drops the given fraction of calls
+func (c *Cluster) SetDropRate(rate float64) {
+	c.logf("🎲 drop rate %.2f", rate)
+	c.Network.SetDropRate(rate)
+}
+
+// Put starts a client write through the coordinator. The write completes in
+// the background as simulated time advances; acknowledged writes are
+// recorded for the durability checks.
+func (c *Cluster) Put(coordinator, key, value string) error {
+	n := c.byID[coordinator]
+	if n == nil {
+		return fmt.Errorf("unknown node %s", coordinator)
+	}
+	if !n.Up {
+		return fmt.Errorf("%s is down", coordinator)
+	}
+
+	step := c.step
+	replicator := n.Replicator
+	c.logf("✏️ put %s=%s via %s", key, value, coordinator)
+
+	c.mu.Lock()
+	c.pendingOps++
+	c.mu.Unlock()
+
+	c.Clock.Go(func() {
+		result, err := replicator.WriteWithReplication(key, value, nil)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.pendingOps--
+
+		if err != nil || result == nil || !result.Status.Acknowledged() {
+			return
+		}
+		c.acked = append(c.acked, &AckedWrite{Key: key, Value: value, Coordinator: coordinator, Step: step})
+	})
+	return nil
+}
+
+// NextKey returns a key no earlier write used
+func (c *Cluster) NextKey() string {
+	c.writeSerial++
+	return fmt.Sprintf("sim-key-%04d", c.writeSerial)
+}
+
+// AckedWrites returns the writes acknowledged so far
+func (c *Cluster) AckedWrites() []*AckedWrite {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]*AckedWrite(nil), c.acked...)
+}
+
+// PendingOps returns the client operations still in flight
+func (c *Cluster) PendingOps() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pendingOps
+}
+
+// Trace returns the actions taken so far, one line each
+func (c *Cluster) Trace() []string {
+	return append([]string(nil), c.trace...)
+}
+
+// Violations returns the invariant violations found so far
+func (c *Cluster) Violations() []Violation {
+	return append([]Violation(nil), c.violations...)
+}
+
+// Elapsed returns the simulated time since the cluster started
+func (c *Cluster) Elapsed() time.Duration {
+	return c.Clock.Since(simEpoch)
+}
+
+// Close stops every live node and removes the data dir if the cluster made it
+func (c *Cluster) Close() {
+	for _, n := range c.nodes {
+		if !n.Up {
+			continue
+		}
+		c.Network.Unregister(n.Address)
+		n.Up = false
+		n.Gossip.Stop()
+		n.Replicator.Stop()
+		n.Storage.Close()
+	}
+
+	if c.removeData {
+		os.RemoveAll(c.dataDir)
+	}
+}
+
+// logf appends a line to the trace, stamped with the step and simulated time
+func (c *Cluster) logf(format string, args ...interface{}) {
+	line := fmt.Sprintf("[step %4d +%8s] ", c.step, c.Elapsed().Truncate(time.Millisecond))
+	c.trace = append(c.trace, line+fmt.Sprintf(format, args...))
+}
+
+// holds reports whether a live node stores the acknowledged value
+func holds(n *Node, write *AckedWrite) bool {
+	version, err := n.Storage.GetVersion(write.Key)
+	return err == nil && !version.Deleted && version.Value == write.Value
+}
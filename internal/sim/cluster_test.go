@@ -0,0 +1,58 @@
+package sim
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// playSeed runs a generated scenario on a fresh cluster
+func playSeed(t *testing.T, seed int64) *Result {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.Seed = seed
+	config.Nodes = 4
+	config.DataDir = t.TempDir()
+
+	cluster, err := NewCluster(config)
+	if err != nil {
+		t.Fatalf("NewCluster: %v", err)
+	}
+	defer cluster.Close()
+
+	return cluster.Play(RandomScenario(seed, config.Nodes, 400), 10*time.Second)
+}
+
+// The seed fixes the scenario; which writes get acknowledged depends on how
+// the goroutines interleave and may differ between runs
+func TestSameSeedSameScenario(t *testing.T) {
+	first := playSeed(t, 2)
+	second := playSeed(t, 2)
+
+	if first.Acked == 0 {
+		t.Fatalf("no write was acknowledged in %d steps", first.Steps)
+	}
+	if first.Writes != second.Writes || first.Steps != second.Steps || first.Elapsed != second.Elapsed {
+		t.Errorf("two runs of seed 2 played %d writes in %d steps (%s) and %d writes in %d steps (%s)",
+			first.Writes, first.Steps, first.Elapsed, second.Writes, second.Steps, second.Elapsed)
+	}
+	if !reflect.DeepEqual(first.Trace, second.Trace) {
+		for i := 0; i < len(first.Trace) && i < len(second.Trace); i++ {
+			if first.Trace[i] != second.Trace[i] {
+				t.Errorf("two runs of seed 2 first differ at trace line %d:\n  %s\n  %s", i, first.Trace[i], second.Trace[i])
+				return
+			}
+		}
+		t.Errorf("two runs of seed 2 traced %d and %d actions", len(first.Trace), len(second.Trace))
+	}
+}
+
+func TestInvariantsHoldUnderFaults(t *testing.T) {
+	for _, seed := range []int64{2, 3, 5} {
+		result := playSeed(t, seed)
+		for _, violation := range result.Violations {
+			t.Errorf("seed %d: %s", seed, violation)
+		}
+	}
+}
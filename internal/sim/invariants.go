@@ -0,0 +1,189 @@
+package sim
+
+import (
+	"fmt"
+	"time"
+)
+
+// Invariant is a property checked against the cluster. Check returns one
+// message per violation.
+type Invariant struct {
+	Name  string
+	Check func(c *Cluster) []string
+}
+
+// Violation is an invariant that failed at some step
+type Violation struct {
+	Step      int           `json:"step"`
+	Elapsed   time.Duration `json:"elapsed"`
+	Invariant string        `json:"invariant"`
+	Detail    string        `json:"detail"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("[step %d +%s] %s: %s", v.Step, v.Elapsed.Truncate(time.Millisecond), v.Invariant, v.Detail)
+}
+
+// DefaultInvariants are the safety properties checked after every step
+func DefaultInvariants() []Invariant {
+	return []Invariant{
+		{Name: "acked-writes-survive", Check: checkAckedWritesSurvive},
+		{Name: "vector-clock-monotonic", Check: checkVectorClockMonotonic},
+		{Name: "self-on-ring", Check: checkSelfOnRing},
+	}
+}
+
+// ConvergenceInvariants are the liveness properties that must hold once
+// faults are healed and the cluster had time to settle
+func ConvergenceInvariants() []Invariant {
+	return []Invariant{
+		{Name: "membership-converged", Check: checkMembershipConverged},
+		{Name: "acked-writes-readable", Check: checkAckedWritesReadable},
+	}
+}
+
+// Converge heals the network, restarts crashed nodes and lets the cluster
+// run for settle before checking the convergence invariants
+func (c *Cluster) Converge(settle time.Duration) []Violation {
+	c.Heal()
+	c.SetDropRate(0)
+	for _, n := range c.nodes {
+		if !n.Up {
+			c.Restart(n.ID)
+		}
+	}
+
+	steps := int(settle / c.config.Step)
+	c.Run(steps)
+
+	before := len(c.violations)
+	c.check(ConvergenceInvariants())
+	return c.violations[before:]
+}
+
+// check runs invariants and records what they report. A violation that
+// persists across steps is recorded once, when it is first seen.
+func (c *Cluster) check(invariants []Invariant) {
+	for _, invariant := range invariants {
+		for _, detail := range invariant.Check(c) {
+			if c.reported[invariant.Name+"\x00"+detail] {
+				continue
+			}
+			c.reported[invariant.Name+"\x00"+detail] = true
+
+			violation := Violation{
+				Step:      c.step,
+				Elapsed:   c.Elapsed(),
+				Invariant: invariant.Name,
+				Detail:    detail,
+			}
+			c.violations = append(c.violations, violation)
+			c.logf("❌ %s: %s", invariant.Name, detail)
+		}
+	}
+}
+
+// checkAckedWritesSurvive: every acknowledged write is held by a live node,
+// or was on disk at a crashed node when it went down
+func checkAckedWritesSurvive(c *Cluster) []string {
+	var problems []string
+
+	for _, write := range c.AckedWrites() {
+		found := false
+		for _, n := range c.nodes {
+			if (n.Up && holds(n, write)) || (!n.Up && n.heldAtCrash[write.Key] == write.Value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s=%s (acked at step %d via %s) is on no node", write.Key, write.Value, write.Step, write.Coordinator))
+		}
+	}
+	return problems
+}
+
+// checkVectorClockMonotonic: a running node's vector clock never goes back
+func checkVectorClockMonotonic(c *Cluster) []string {
+	var problems []string
+
+	for _, n := range c.LiveNodes() {
+		current := n.Storage.CurrentClock()
+		for nodeID, previous := range n.lastClock.Clocks {
+			if current.Clocks[nodeID] < previous {
+				problems = append(problems, fmt.Sprintf("%s: clock entry %s went back from %d to %d", n.ID, nodeID, previous, current.Clocks[nodeID]))
+			}
+		}
+		n.lastClock = current
+	}
+	return problems
+}
+
+// checkSelfOnRing: a running node always owns part of its own ring
+func checkSelfOnRing(c *Cluster) []string {
+	var problems []string
+
+	for _, n := range c.LiveNodes() {
+		if n.Ring.GetNode(n.ID) == nil {
+			problems = append(problems, fmt.Sprintf("%s is missing from its own ring", n.ID))
+		}
+	}
+	return problems
+}
+
+// checkMembershipConverged: every live node's membership service sees every
+// other live node alive
+func checkMembershipConverged(c *Cluster) []string {
+	var problems []string
+
+	live := c.LiveNodes()
+	for _, n := range live {
+		for _, other := range live {
+			member, known := n.Members.Member(other.ID)
+			switch {
+			case !known:
+				problems = append(problems, fmt.Sprintf("%s doesn't know %s", n.ID, other.ID))
+			case member.Status != "alive":
+				problems = append(problems, fmt.Sprintf("%s sees %s as %s", n.ID, other.ID, member.Status))
+			}
+		}
+	}
+	return problems
+}
+
+// checkAckedWritesReadable: every acknowledged write is held by enough of
+// its live owners (by the first live node's ring) that any read quorum of
+// them includes a copy
+func checkAckedWritesReadable(c *Cluster) []string {
+	live := c.LiveNodes()
+	if len(live) == 0 {
+		return nil
+	}
+
+	var problems []string
+	for _, write := range c.AckedWrites() {
+		config := live[0].Replicator.ConfigForKey(write.Key)
+
+		owners, holders := 0, 0
+		for _, owner := range live[0].Replicator.Owners(write.Key) {
+			n := c.byID[owner.ID]
+			if n == nil || !n.Up {
+				continue
+			}
+			owners++
+			if holds(n, write) {
+				holders++
+			}
+		}
+
+		// Any R of the owners include a copy only if N-R+1 of them hold it
+		needed := config.N - config.R + 1
+		if needed > owners {
+			needed = owners
+		}
+		if holders < needed {
+			problems = append(problems, fmt.Sprintf("%s=%s is on %d of %d live owners, a read quorum needs %d", write.Key, write.Value, holders, owners, needed))
+		}
+	}
+	return problems
+}
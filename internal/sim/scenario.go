@@ -0,0 +1,197 @@
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Action kinds a scenario can script
+const (
+	ActionPut       = "put"
+	ActionCrash     = "crash"
+	ActionRestart   = "restart"
+	ActionPartition = "partition"
+	ActionHeal      = "heal"
+	ActionDropRate  = "drop_rate"
+)
+
+// Action is one scripted event, applied before the step it is scheduled at
+type Action struct {
+	Step   int        `json:"step"`
+	Kind   string     `json:"kind"`
+	Node   string     `json:"node,omitempty"`   // put coordinator, crash/restart target
+	Key    string     `json:"key,omitempty"`    // put key (default: a fresh key)
+	Value  string     `json:"value,omitempty"`  // put value
+	Groups [][]string `json:"groups,omitempty"` // partition groups
+	Rate   float64    `json:"rate,omitempty"`   // drop rate
+}
+
+// Scenario is a script of actions over a number of steps
+type Scenario struct {
+	Steps   int      `json:"steps"`
+	Actions []Action `json:"actions"`
+}
+
+// Result summarizes a played scenario
+type Result struct {
+	Seed       int64         `json:"seed"`
+	Steps      int           `json:"steps"`
+	Elapsed    time.Duration `json:"elapsed"`
+	Writes     int           `json:"writes"`
+	Acked      int           `json:"acked"`
+	Trace      []string      `json:"trace"`
+	Violations []Violation   `json:"violations"`
+}
+
+// Play runs the scenario step by step, then heals the cluster and checks
+// that it converges within settle
+func (c *Cluster) Play(scenario *Scenario, settle time.Duration) *Result {
+	actions := append([]Action(nil), scenario.Actions...)
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].Step < actions[j].Step
+	})
+
+	writes := 0
+	next := 0
+	for step := 0; step < scenario.Steps; step++ {
+		for next < len(actions) && actions[next].Step <= step {
+			action := actions[next]
+			next++
+
+			if action.Kind == ActionPut {
+				writes++
+			}
+			if err := c.apply(action); err != nil {
+				c.logf("⚠️ %s skipped: %v", action.Kind, err)
+			}
+		}
+		c.Step()
+	}
+
+	c.Converge(settle)
+
+	return &Result{
+		Seed:       c.config.Seed,
+		Steps:      c.step,
+		Elapsed:    c.Elapsed(),
+		Writes:     writes,
+		Acked:      len(c.AckedWrites()),
+		Trace:      c.Trace(),
+		Violations: c.Violations(),
+	}
+}
+
+// apply performs one scripted action
+func (c *Cluster) apply(action Action) error {
+	switch action.Kind {
+	case ActionPut:
+		key := action.Key
+		if key == "" {
+			key = c.NextKey()
+		}
+		value := action.Value
+		if value == "" {
+			value = fmt.Sprintf("v%d", c.step)
+		}
+		return c.Put(action.Node, key, value)
+	case ActionCrash:
+		return c.Crash(action.Node)
+	case ActionRestart:
+		return c.Restart(action.Node)
+	case ActionPartition:
+		c.Partition(action.Groups...)
+		return nil
+	case ActionHeal:
+		c.Heal()
+		return nil
+	case ActionDropRate:
+		c.SetDropRate(action.Rate)
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q", action.Kind)
+	}
+}
+
+// RandomScenario generates a scenario from a seed: a steady stream of writes
+// through random coordinators, with crashes, restarts, partitions and packet
+// loss mixed in. At most a minority of nodes is down at a time.
+func RandomScenario(seed int64, nodes, steps int) *Scenario {
+	rng := rand.New(rand.NewSource(seed))
+
+	ids := make([]string, nodes)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("node-%d", i+1)
+	}
+
+	scenario := &Scenario{Steps: steps}
+	down := make(map[string]bool)
+	partitioned := false
+
+	// Let membership converge before the first fault
+	const warmup = 50
+
+	for step := warmup; step < steps; step++ {
+		// A write every few steps through a live coordinator
+		if rng.Intn(3) == 0 {
+			if coordinator := pickNode(rng, ids, down, false); coordinator != "" {
+				scenario.Actions = append(scenario.Actions, Action{Step: step, Kind: ActionPut, Node: coordinator})
+			}
+		}
+
+		if rng.Intn(40) != 0 {
+			continue
+		}
+
+		switch rng.Intn(5) {
+		case 0:
+			if len(down) < (nodes-1)/2 {
+				if target := pickNode(rng, ids, down, false); target != "" {
+					down[target] = true
+					scenario.Actions = append(scenario.Actions, Action{Step: step, Kind: ActionCrash, Node: target})
+				}
+			}
+		case 1:
+			if target := pickNode(rng, ids, down, true); target != "" {
+				delete(down, target)
+				scenario.Actions = append(scenario.Actions, Action{Step: step, Kind: ActionRestart, Node: target})
+			}
+		case 2:
+			if !partitioned && nodes > 1 {
+				shuffled := append([]string(nil), ids...)
+				rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+				cut := 1 + rng.Intn(nodes/2)
+				scenario.Actions = append(scenario.Actions, Action{
+					Step:   step,
+					Kind:   ActionPartition,
+					Groups: [][]string{shuffled[:cut], shuffled[cut:]},
+				})
+				partitioned = true
+			}
+		case 3:
+			if partitioned {
+				scenario.Actions = append(scenario.Actions, Action{Step: step, Kind: ActionHeal})
+				partitioned = false
+			}
+		case 4:
+			scenario.Actions = append(scenario.Actions, Action{Step: step, Kind: ActionDropRate, Rate: float64(rng.Intn(3)) * 0.1})
+		}
+	}
+
+	return scenario
+}
+
+// pickNode returns a random node that is down (or up), or "" if there is none
+func pickNode(rng *rand.Rand, ids []string, down map[string]bool, wantDown bool) string {
+	candidates := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if down[id] == wantDown {
+			candidates = append(candidates, id)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[rng.Intn(len(candidates))]
+}
@@ -0,0 +1,162 @@
+package sim
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"dynamodb/internal/history"
+	"dynamodb/internal/storage"
+)
+
+// simClient sends a history workload's requests to one simulated node
+type simClient struct {
+	node *Node
+}
+
+func (s *simClient) Node() string {
+	return s.node.ID
+}
+
+// Put counts writes that reached the write quorum as acknowledged
+func (s *simClient) Put(key, value string) (*storage.VectorClock, error) {
+	if !s.node.Up {
+		return nil, fmt.Errorf("%s is down", s.node.ID)
+	}
+
+	result, err := s.node.Replicator.WriteWithReplication(key, value, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Status.Acknowledged() {
+		return nil, fmt.Errorf("write not acknowledged: %s", result.Status)
+	}
+	return result.VectorClock, nil
+}
+
+// Delete counts only deletes that reached the write quorum as acknowledged
+func (s *simClient) Delete(key string) (*storage.VectorClock, error) {
+	if !s.node.Up {
+		return nil, fmt.Errorf("%s is down", s.node.ID)
+	}
+
+	result, err := s.node.Replicator.DeleteWithReplication(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Status.Acknowledged() {
+		return nil, fmt.Errorf("delete not acknowledged: %s", result.Status)
+	}
+	return result.VectorClock, nil
+}
+
+func (s *simClient) Get(key string) (*history.ReadResult, error) {
+	if !s.node.Up {
+		return nil, fmt.Errorf("%s is down", s.node.ID)
+	}
+
+	result, err := s.node.Replicator.ReadWithQuorum(key, nil)
+	if err == storage.ErrKeyNotFound {
+		read := &history.ReadResult{}
+		if result != nil {
+			read.VectorClock = result.VectorClock
+		}
+		return read, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	read := &history.ReadResult{
+		Found:       true,
+		Values:      []string{result.Value},
+		VectorClock: result.VectorClock,
+	}
+	for _, sibling := range result.Siblings {
+		read.Values = append(read.Values, sibling.Value)
+	}
+	return read, nil
+}
+
+// Clients returns a history client for every node
+func (c *Cluster) Clients() []history.Client {
+	clients := make([]history.Client, len(c.nodes))
+	for i, n := range c.nodes {
+		clients[i] = &simClient{node: n}
+	}
+	return clients
+}
+
+// RunWorkload drives the workload against the cluster while applying the
+// fault actions of faults (puts are ignored; steps count from the cluster's
+// start), then converges the cluster and reads every key through every node.
+// It returns the recorded history.
+func (c *Cluster) RunWorkload(w *history.Workload, faults *Scenario, settle time.Duration) *history.History {
+	recorder := history.NewRecorder(c.Clock)
+	clients := c.Clients()
+
+	actions := make([]Action, 0)
+	if faults != nil {
+		for _, action := range faults.Actions {
+			if action.Kind != ActionPut {
+				actions = append(actions, action)
+			}
+		}
+		sort.SliceStable(actions, func(i, j int) bool {
+			return actions[i].Step < actions[j].Step
+		})
+	}
+
+	// Let membership converge before the first request, as RandomScenario does
+	// before the first fault
+	c.Run(50)
+
+	c.logf("📝 workload: %d clients × %d ops on %d keys", w.Clients, w.OpsPerClient, w.Keys)
+	c.runUntil(func(done func()) {
+		w.Run(recorder, clients, c.Clock.Go)
+		done()
+	}, actions)
+
+	c.Converge(settle)
+
+	c.logf("📖 final reads")
+	c.runUntil(func(done func()) {
+		w.FinalReads(recorder, clients)
+		done()
+	}, nil)
+
+	return recorder.History()
+}
+
+// runUntil starts f in simulated time and steps the cluster until f calls
+// done, applying each action once its step is reached
+func (c *Cluster) runUntil(f func(done func()), actions []Action) {
+	var mu sync.Mutex
+	finished := false
+	c.Clock.Go(func() {
+		f(func() {
+			mu.Lock()
+			finished = true
+			mu.Unlock()
+		})
+	})
+
+	next := 0
+	for {
+		mu.Lock()
+		stop := finished
+		mu.Unlock()
+		if stop {
+			return
+		}
+
+		for next < len(actions) && actions[next].Step <= c.step {
+			if err := c.apply(actions[next]); err != nil {
+				c.logf("⚠️ %s skipped: %v", actions[next].Kind, err)
+			}
+			next++
+		}
+		c.Step()
+	}
+}
@@ -5,20 +5,34 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
-	"time"
+
+	"dynamodb/internal/clock"
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 )
 
+// ErrKeyNotFound is returned when a key has no stored value
+var ErrKeyNotFound = fmt.Errorf("key not found")
+
+// ErrSuperseded is returned when a replicated mutation is not newer than the
+// version already stored (tombstones included), so it was not applied
+var ErrSuperseded = fmt.Errorf("a newer version is already stored")
+
 // StorageValue represents a value with metadata
 type StorageValue struct {
 	Value     string            `json:"value"`
 	Timestamp int64             `json:"timestamp"`
 	Version   int               `json:"version"`
 	Metadata  map[string]string `json:"metadata"`
+	// Vector clock of the event that produced this value
+	VectorClock *VectorClock `json:"vector_clock,omitempty"`
+	// Set on the tombstone a delete leaves behind. It keeps the delete's
+	// vector clock so older writes arriving later can't bring the key back.
+	Deleted bool `json:"deleted,omitempty"`
 }
 
 // LevelDBStorage implements distributed storage with LevelDB
@@ -29,6 +43,7 @@ type LevelDBStorage struct {
 	mu       sync.RWMutex
 	// Vector clock integration
 	eventLog *EventLog
+	clock    clock.Clock
 }
 
 // NewLevelDBStorage creates a new LevelDB storage instance with vector clock support
@@ -60,6 +75,7 @@ func NewLevelDBStorage(nodeID, dataPath string) (*LevelDBStorage, error) {
 		nodeID:   nodeID,
 		dataPath: fullPath,
 		eventLog: NewEventLog(nodeID),
+		clock:    clock.Real(),
 	}
 
 	fmt.Printf("✅ LevelDB storage initialized at %s\n", fullPath)
@@ -68,34 +84,26 @@ func NewLevelDBStorage(nodeID, dataPath string) (*LevelDBStorage, error) {
 	return storage, nil
 }
 
+// SetClock sets the clock that timestamps values and events (nil = wall clock)
+func (s *LevelDBStorage) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clock = clock.OrReal(c)
+	s.eventLog.clock = s.clock
+}
+
 // Put stores a key-value pair with vector clock event logging
 func (s *LevelDBStorage) Put(key, value string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Log the event with vector clock
+	// Log the event with vector clock, after what we store for the key
+	s.followStored(key)
 	event := s.eventLog.AddEvent("put", key, value)
 
 	// Create storage value with metadata including vector clock
-	storageValue := StorageValue{
-		Value:     value,
-		Timestamp: time.Now().Unix(),
-		Version:   1, // TODO: Implement proper versioning
-		Metadata: map[string]string{
-			"node_id":      s.nodeID,
-			"event_id":     event.ID,
-			"vector_clock": event.VectorClock.String(),
-		},
-	}
-
-	// Serialize and store
-	data, err := json.Marshal(storageValue)
-	if err != nil {
-		return err
-	}
-
-	err = s.db.Put([]byte(key), data, nil)
-	if err != nil {
+	if err := s.write(key, eventVersion(value, event, false)); err != nil {
 		return err
 	}
 
@@ -103,32 +111,15 @@ func (s *LevelDBStorage) Put(key, value string) error {
 	return nil
 }
 
-// PutReplicated stores a key-value pair from replication without creating a new event
+// PutReplicated stores a key-value pair from replication without creating a
+// new event. It returns ErrSuperseded, storing nothing, unless the source
+// event supersedes the stored version (see Supersedes).
 func (s *LevelDBStorage) PutReplicated(key, value string, sourceEvent *Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	// Use the source event instead of creating a new one
-	storageValue := StorageValue{
-		Value:     value,
-		Timestamp: time.Now().Unix(),
-		Version:   1,
-		Metadata: map[string]string{
-			"node_id":      sourceEvent.NodeID,
-			"event_id":     sourceEvent.ID,
-			"vector_clock": sourceEvent.VectorClock.String(),
-			"replicated":   "true", // Mark as replicated
-		},
-	}
-
-	// Serialize and store
-	data, err := json.Marshal(storageValue)
-	if err != nil {
-		return err
-	}
-
-	err = s.db.Put([]byte(key), data, nil)
-	if err != nil {
+	if err := s.writeIfNewer(key, replicatedVersion(value, sourceEvent, false)); err != nil {
 		return err
 	}
 
@@ -137,6 +128,18 @@ func (s *LevelDBStorage) PutReplicated(key, value string, sourceEvent *Event) er
 	return nil
 }
 
+// RecordEvent logs a mutation this node coordinates for other replicas
+// without storing it locally, and returns the event to replicate with
+func (s *LevelDBStorage) RecordEvent(eventType, key, value string) *Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := s.eventLog.AddEvent(eventType, key, value)
+	fmt.Printf("🧭 %s %s coordinated for its owners [%s] at event %s\n",
+		strings.ToUpper(eventType), key, event.VectorClock.String(), event.ID)
+	return event
+}
+
 // Get retrieves a value by key with vector clock event logging
 func (s *LevelDBStorage) Get(key string) (*StorageValue, error) {
 	s.mu.RLock()
@@ -145,10 +148,34 @@ func (s *LevelDBStorage) Get(key string) (*StorageValue, error) {
 	// Log the read event
 	event := s.eventLog.AddEvent("get", key, "")
 
+	value, err := s.read(key)
+	if err != nil {
+		return nil, err
+	}
+	if value.Deleted {
+		return nil, ErrKeyNotFound
+	}
+
+	fmt.Printf("📖 GET %s [%s] at event %s\n", key, event.VectorClock.String(), event.ID)
+	return value, nil
+}
+
+// GetVersion retrieves the stored version of a key without logging a read event.
+// It is used for internal comparisons between replicas, so it returns
+// tombstones too.
+func (s *LevelDBStorage) GetVersion(key string) (*StorageValue, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.read(key)
+}
+
+// read decodes the stored version of a key. Callers hold mu.
+func (s *LevelDBStorage) read(key string) (*StorageValue, error) {
 	data, err := s.db.Get([]byte(key), nil)
 	if err != nil {
 		if err == leveldb.ErrNotFound {
-			return nil, fmt.Errorf("key not found")
+			return nil, ErrKeyNotFound
 		}
 		return nil, err
 	}
@@ -159,20 +186,20 @@ func (s *LevelDBStorage) Get(key string) (*StorageValue, error) {
 		return nil, err
 	}
 
-	fmt.Printf("📖 GET %s [%s] at event %s\n", key, event.VectorClock.String(), event.ID)
 	return &value, nil
 }
 
-// Delete removes a key-value pair with vector clock event logging
+// Delete removes a key-value pair with vector clock event logging. The key
+// is replaced by a tombstone carrying the delete's vector clock.
 func (s *LevelDBStorage) Delete(key string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Log the delete event
+	// Log the delete event, after what we store for the key
+	s.followStored(key)
 	event := s.eventLog.AddEvent("delete", key, "")
 
-	err := s.db.Delete([]byte(key), nil)
-	if err != nil {
+	if err := s.write(key, eventVersion("", event, true)); err != nil {
 		return err
 	}
 
@@ -180,13 +207,14 @@ func (s *LevelDBStorage) Delete(key string) error {
 	return nil
 }
 
-// DeleteReplicated removes a key-value pair from replication without creating a new event
+// DeleteReplicated leaves a tombstone from replication without creating a
+// new event, even if the key isn't stored here. Like PutReplicated it returns
+// ErrSuperseded if the stored version is newer.
 func (s *LevelDBStorage) DeleteReplicated(key string, sourceEvent *Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	err := s.db.Delete([]byte(key), nil)
-	if err != nil {
+	if err := s.writeIfNewer(key, replicatedVersion("", sourceEvent, true)); err != nil {
 		return err
 	}
 
@@ -195,22 +223,22 @@ func (s *LevelDBStorage) DeleteReplicated(key string, sourceEvent *Event) error
 	return nil
 }
 
-// Exists checks if a key exists
+// Exists checks if a key exists (a tombstone doesn't count)
 func (s *LevelDBStorage) Exists(key string) (bool, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	_, err := s.db.Get([]byte(key), nil)
+	value, err := s.read(key)
 	if err != nil {
-		if err == leveldb.ErrNotFound {
+		if err == ErrKeyNotFound {
 			return false, nil
 		}
 		return false, err
 	}
-	return true, nil
+	return !value.Deleted, nil
 }
 
-// ListKeys returns all keys in the database
+// ListKeys returns all keys in the database that aren't deleted
 func (s *LevelDBStorage) ListKeys() ([]string, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -220,6 +248,10 @@ func (s *LevelDBStorage) ListKeys() ([]string, error) {
 	defer iter.Release()
 
 	for iter.Next() {
+		var value StorageValue
+		if err := json.Unmarshal(iter.Value(), &value); err == nil && value.Deleted {
+			continue
+		}
 		keys = append(keys, string(iter.Key()))
 	}
 
@@ -230,6 +262,96 @@ func (s *LevelDBStorage) ListKeys() ([]string, error) {
 	return keys, nil
 }
 
+// PurgeTombstones removes tombstones written before the given Unix time and
+// returns how many it removed. Once a tombstone is gone, a replica that
+// missed the delete can bring the key back, so callers keep them for longer
+// than any replica may be down.
+func (s *LevelDBStorage) PurgeTombstones(before int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	iter := s.db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		var value StorageValue
+		if err := json.Unmarshal(iter.Value(), &value); err != nil {
+			continue
+		}
+		if value.Deleted && value.Timestamp < before {
+			batch.Delete(append([]byte(nil), iter.Key()...))
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+
+	if batch.Len() == 0 {
+		return 0, nil
+	}
+	if err := s.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+
+	fmt.Printf("🪦 Purged %d tombstones\n", batch.Len())
+	return batch.Len(), nil
+}
+
+// followStored merges the stored version's clock for key into ours, so the
+// next local event supersedes it. Callers hold mu.
+func (s *LevelDBStorage) followStored(key string) {
+	if existing, err := s.read(key); err == nil {
+		s.eventLog.Current.Update(existing.GetVectorClock())
+	}
+}
+
+// writeIfNewer stores a replicated version unless the stored one is at
+// least as new. Callers hold mu.
+func (s *LevelDBStorage) writeIfNewer(key string, version *StorageValue) error {
+	existing, err := s.read(key)
+	if err != nil && err != ErrKeyNotFound {
+		return err
+	}
+	if existing != nil && !Supersedes(version, existing) {
+		return ErrSuperseded
+	}
+	return s.write(key, version)
+}
+
+// write stores a version of key. Callers hold mu.
+func (s *LevelDBStorage) write(key string, version *StorageValue) error {
+	data, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+	return s.db.Put([]byte(key), data, nil)
+}
+
+// eventVersion is the version a local event writes
+func eventVersion(value string, event *Event, deleted bool) *StorageValue {
+	return &StorageValue{
+		Value:     value,
+		Timestamp: event.Timestamp,
+		Version:   1, // TODO: Implement proper versioning
+		Metadata: map[string]string{
+			"node_id":      event.NodeID,
+			"event_id":     event.ID,
+			"vector_clock": event.VectorClock.String(),
+		},
+		VectorClock: event.VectorClock.Copy(),
+		Deleted:     deleted,
+	}
+}
+
+// replicatedVersion is the version a replicated event writes. It keeps the
+// source event's timestamp so every replica orders concurrent versions alike.
+func replicatedVersion(value string, sourceEvent *Event, deleted bool) *StorageValue {
+	version := eventVersion(value, sourceEvent, deleted)
+	version.Metadata["replicated"] = "true" // Mark as replicated
+	return version
+}
+
 // GetStats returns storage statistics including vector clock info
 func (s *LevelDBStorage) GetStats() map[string]interface{} {
 	s.mu.RLock()
@@ -244,7 +366,7 @@ func (s *LevelDBStorage) GetStats() map[string]interface{} {
 		"vector_clock": s.eventLog.Current.String(),
 		"event_count":  len(s.eventLog.Events),
 		"known_nodes":  len(s.eventLog.Nodes),
-		"current_time": time.Now().Unix(),
+		"current_time": s.clock.Now().Unix(),
 	}
 }
 
@@ -255,6 +377,21 @@ func (s *LevelDBStorage) GetEventLog() *EventLog {
 	return s.eventLog
 }
 
+// CurrentClock returns a copy of this node's vector clock
+func (s *LevelDBStorage) CurrentClock() *VectorClock {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.eventLog.Current.Copy()
+}
+
+// EventsSince returns the logged events a node whose clock is since hasn't
+// seen, oldest first
+func (s *LevelDBStorage) EventsSince(since *VectorClock) []*Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.eventLog.GetEventsSince(since)
+}
+
 // MergeVectorClock merges another node's vector clock and events
 func (s *LevelDBStorage) MergeVectorClock(otherLog *EventLog) {
 	s.mu.Lock()
@@ -268,6 +405,20 @@ func (s *LevelDBStorage) MergeVectorClock(otherLog *EventLog) {
 	fmt.Printf("%s\n", s.eventLog.Current.String())
 }
 
+// MergeEvents adds events shipped from another node to the event log. The
+// clock only advances over events whose causal past the log holds.
+func (s *LevelDBStorage) MergeEvents(fromNode string, events []*Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Printf("🔄 Merging %d events from %s: %s -> ",
+		len(events), fromNode, s.eventLog.Current.String())
+
+	s.eventLog.MergeEvents(events)
+
+	fmt.Printf("%s\n", s.eventLog.Current.String())
+}
+
 // DetectConflicts finds conflicting concurrent operations
 func (s *LevelDBStorage) DetectConflicts() []*ConflictSet {
 	s.mu.RLock()
@@ -301,6 +452,11 @@ func (s *LevelDBStorage) GetCausalHistory(key string) []*Event {
 	return history
 }
 
+// DataPath returns the directory holding this node's database
+func (s *LevelDBStorage) DataPath() string {
+	return s.dataPath
+}
+
 // Close closes the LevelDB database
 func (s *LevelDBStorage) Close() error {
 	if s.db != nil {
@@ -11,14 +11,19 @@ import (
 
 // MerkleNode represents a node in the Merkle tree
 type MerkleNode struct {
-	Hash     string      `json:"hash"`
-	IsLeaf   bool        `json:"is_leaf"`
-	Key      string      `json:"key,omitempty"`   // Only for leaf nodes
-	Value    string      `json:"value,omitempty"` // Only for leaf nodes
-	Left     *MerkleNode `json:"left,omitempty"`  // Only for internal nodes
-	Right    *MerkleNode `json:"right,omitempty"` // Only for internal nodes
-	Level    int         `json:"level"`           // Tree level (0 = root)
-	Position int         `json:"position"`        // Position at this level
+	Hash   string `json:"hash"`
+	IsLeaf bool   `json:"is_leaf"`
+	Key    string `json:"key,omitempty"`   // Only for leaf nodes
+	Value  string `json:"value,omitempty"` // Only for leaf nodes
+	// Only for leaf nodes: hash of the key and value alone, so comparisons can
+	// tell value changes apart from metadata-only changes
+	ValueHash   string       `json:"value_hash,omitempty"`
+	VectorClock *VectorClock `json:"vector_clock,omitempty"`
+	Deleted     bool         `json:"deleted,omitempty"`
+	Left        *MerkleNode  `json:"left,omitempty"`  // Only for internal nodes
+	Right       *MerkleNode  `json:"right,omitempty"` // Only for internal nodes
+	Level       int          `json:"level"`           // Tree level (0 = root)
+	Position    int          `json:"position"`        // Position at this level
 }
 
 // MerkleTree represents the complete Merkle tree for a node's data
@@ -37,9 +42,12 @@ type TreeComparison struct {
 	TargetNodeID   string   `json:"target_node_id"`
 	IsConsistent   bool     `json:"is_consistent"`
 	MismatchedKeys []string `json:"mismatched_keys"`
-	MissingKeys    []string `json:"missing_keys"`
-	ExtraKeys      []string `json:"extra_keys"`
-	Timestamp      int64    `json:"timestamp"`
+	// MismatchedKeys split by kind of difference
+	ValueMismatchedKeys    []string `json:"value_mismatched_keys"`
+	MetadataMismatchedKeys []string `json:"metadata_mismatched_keys"`
+	MissingKeys            []string `json:"missing_keys"`
+	ExtraKeys              []string `json:"extra_keys"`
+	Timestamp              int64    `json:"timestamp"`
 }
 
 // BuildMerkleTree constructs a Merkle tree from the storage data
@@ -56,19 +64,22 @@ func (s *LevelDBStorage) BuildMerkleTree() (*MerkleTree, error) {
 	// Create leaf nodes
 	leaves := make([]*MerkleNode, 0, len(keys))
 	for i, key := range keys {
-		value, err := s.Get(key)
+		// Read without logging events so building a tree doesn't move our clock
+		value, err := s.GetVersion(key)
 		if err != nil {
 			continue // Skip keys that can't be read
 		}
 
-		leafHash := computeLeafHash(key, value.Value)
 		leaf := &MerkleNode{
-			Hash:     leafHash,
-			IsLeaf:   true,
-			Key:      key,
-			Value:    value.Value,
-			Level:    0,
-			Position: i,
+			Hash:        computeLeafHash(key, value),
+			IsLeaf:      true,
+			Key:         key,
+			Value:       value.Value,
+			ValueHash:   computeValueHash(key, value.Value),
+			VectorClock: value.GetVectorClock(),
+			Deleted:     value.Deleted,
+			Level:       0,
+			Position:    i,
 		}
 		leaves = append(leaves, leaf)
 	}
@@ -88,6 +99,27 @@ func (s *LevelDBStorage) BuildMerkleTree() (*MerkleTree, error) {
 	return tree, nil
 }
 
+// NewMerkleTreeFromLeaves builds a tree over an existing set of leaves, e.g. the
+// subset of another tree's leaves that fall into one ring range
+func NewMerkleTreeFromLeaves(nodeID string, leaves []*MerkleNode) *MerkleTree {
+	sorted := make([]*MerkleNode, len(leaves))
+	copy(sorted, leaves)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	root := buildTreeFromLeaves(sorted)
+
+	return &MerkleTree{
+		Root:      root,
+		NodeID:    nodeID,
+		Timestamp: time.Now().Unix(),
+		KeyCount:  len(sorted),
+		TreeDepth: calculateDepth(root),
+		Leaves:    sorted,
+	}
+}
+
 // buildTreeFromLeaves constructs the tree bottom-up from leaf nodes
 func buildTreeFromLeaves(leaves []*MerkleNode) *MerkleNode {
 	if len(leaves) == 0 {
@@ -141,10 +173,23 @@ func buildTreeFromLeaves(leaves []*MerkleNode) *MerkleNode {
 	return currentLevel[0]
 }
 
-// computeLeafHash computes hash for a leaf node (key-value pair)
-func computeLeafHash(key, value string) string {
+// computeLeafHash computes hash for a leaf node over the key, value and version
+// metadata (vector clock and tombstone), so replicas holding the same
+// value at different versions still disagree. Fields are length-prefixed to
+// keep the encoding unambiguous.
+func computeLeafHash(key string, value *StorageValue) string {
+	clock := value.GetVectorClock().String()
+
+	hasher := sha256.New()
+	hasher.Write([]byte(fmt.Sprintf("leaf:%d:%s:%d:%s:clock:%d:%s:deleted:%t",
+		len(key), key, len(value.Value), value.Value, len(clock), clock, value.Deleted)))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// computeValueHash computes hash for a leaf's key-value pair only
+func computeValueHash(key, value string) string {
 	hasher := sha256.New()
-	hasher.Write([]byte(fmt.Sprintf("leaf:%s:%s", key, value)))
+	hasher.Write([]byte(fmt.Sprintf("value:%d:%s:%d:%s", len(key), key, len(value), value)))
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
@@ -186,6 +231,9 @@ func CompareTrees(sourceTree, targetTree *MerkleTree) *TreeComparison {
 		MismatchedKeys: make([]string, 0),
 		MissingKeys:    make([]string, 0),
 		ExtraKeys:      make([]string, 0),
+
+		ValueMismatchedKeys:    make([]string, 0),
+		MetadataMismatchedKeys: make([]string, 0),
 	}
 
 	// Quick check: if root hashes match, trees are identical
@@ -211,6 +259,11 @@ func CompareTrees(sourceTree, targetTree *MerkleTree) *TreeComparison {
 		if targetLeaf, exists := targetLeaves[key]; exists {
 			if sourceLeaf.Hash != targetLeaf.Hash {
 				comparison.MismatchedKeys = append(comparison.MismatchedKeys, key)
+				if LeafValueDiffers(sourceLeaf, targetLeaf) {
+					comparison.ValueMismatchedKeys = append(comparison.ValueMismatchedKeys, key)
+				} else {
+					comparison.MetadataMismatchedKeys = append(comparison.MetadataMismatchedKeys, key)
+				}
 			}
 		} else {
 			comparison.MissingKeys = append(comparison.MissingKeys, key)
@@ -231,6 +284,16 @@ func CompareTrees(sourceTree, targetTree *MerkleTree) *TreeComparison {
 	return comparison
 }
 
+// LeafValueDiffers reports whether two leaves for the same key hold different
+// values, as opposed to the same value with different version metadata
+func LeafValueDiffers(a, b *MerkleNode) bool {
+	if a.ValueHash == "" || b.ValueHash == "" {
+		// Trees built by older nodes carry no value hash
+		return a.Value != b.Value
+	}
+	return a.ValueHash != b.ValueHash
+}
+
 // GetAllKeys returns all keys in the storage (helper method)
 func (s *LevelDBStorage) GetAllKeys() ([]string, error) {
 	keys := make([]string, 0)
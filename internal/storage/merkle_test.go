@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLeafHashCoversVersionMetadata(t *testing.T) {
+	base := &StorageValue{Value: "v1", VectorClock: &VectorClock{Clocks: map[string]int64{"n1": 1}}}
+
+	tests := []struct {
+		name  string
+		other *StorageValue
+		same  bool
+	}{
+		{name: "same version", same: true,
+			other: &StorageValue{Value: "v1", VectorClock: &VectorClock{Clocks: map[string]int64{"n1": 1}}}},
+		{name: "other value",
+			other: &StorageValue{Value: "v2", VectorClock: &VectorClock{Clocks: map[string]int64{"n1": 1}}}},
+		{name: "other vector clock",
+			other: &StorageValue{Value: "v1", VectorClock: &VectorClock{Clocks: map[string]int64{"n1": 2}}}},
+		{name: "tombstone",
+			other: &StorageValue{Value: "v1", VectorClock: &VectorClock{Clocks: map[string]int64{"n1": 1}}, Deleted: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := computeLeafHash("k", base) == computeLeafHash("k", tt.other); same != tt.same {
+				t.Errorf("leaf hashes equal = %t, want %t", same, tt.same)
+			}
+		})
+	}
+
+	// Length prefixes keep the key/value split unambiguous
+	if computeLeafHash("ab", &StorageValue{Value: "c"}) == computeLeafHash("a", &StorageValue{Value: "bc"}) {
+		t.Errorf("key ab with value c hashes like key a with value bc")
+	}
+}
+
+func TestCompareTreesClassifiesMismatches(t *testing.T) {
+	source := newTestStorage(t, "n1")
+	target := newTestStorage(t, "n2")
+
+	put := func(s *LevelDBStorage, key, value string, clock map[string]int64) {
+		t.Helper()
+		if err := s.PutReplicated(key, value, event("n3", key+value, clock, 10)); err != nil {
+			t.Fatalf("PutReplicated(%s): %v", key, err)
+		}
+	}
+
+	put(source, "same", "v1", map[string]int64{"n3": 1})
+	put(target, "same", "v1", map[string]int64{"n3": 1})
+	put(source, "value", "v1", map[string]int64{"n3": 1})
+	put(target, "value", "v2", map[string]int64{"n3": 2})
+	put(source, "clock", "v1", map[string]int64{"n3": 1})
+	put(target, "clock", "v1", map[string]int64{"n3": 2})
+	put(source, "deleted", "v1", map[string]int64{"n3": 1})
+	target.DeleteReplicated("deleted", event("n3", "del", map[string]int64{"n3": 2}, 11))
+	put(source, "missing", "v1", map[string]int64{"n3": 1})
+	put(target, "extra", "v1", map[string]int64{"n3": 1})
+
+	sourceTree, err := source.BuildMerkleTree()
+	if err != nil {
+		t.Fatalf("BuildMerkleTree: %v", err)
+	}
+	targetTree, err := target.BuildMerkleTree()
+	if err != nil {
+		t.Fatalf("BuildMerkleTree: %v", err)
+	}
+
+	comparison := CompareTrees(sourceTree, targetTree)
+	sorted := func(keys []string) []string {
+		out := append([]string(nil), keys...)
+		sort.Strings(out)
+		return out
+	}
+	checks := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"mismatched", comparison.MismatchedKeys, []string{"clock", "deleted", "value"}},
+		// A delete's tombstone has no value, so it differs in value
+		{"value mismatched", comparison.ValueMismatchedKeys, []string{"deleted", "value"}},
+		{"metadata mismatched", comparison.MetadataMismatchedKeys, []string{"clock"}},
+		{"missing", comparison.MissingKeys, []string{"missing"}},
+		{"extra", comparison.ExtraKeys, []string{"extra"}},
+	}
+	for _, check := range checks {
+		if got := sorted(check.got); !reflect.DeepEqual(got, check.want) {
+			t.Errorf("%s keys = %v, want %v", check.name, got, check.want)
+		}
+	}
+	if comparison.IsConsistent {
+		t.Errorf("trees compared consistent")
+	}
+
+	if !CompareTrees(sourceTree, sourceTree).IsConsistent {
+		t.Errorf("a tree isn't consistent with itself")
+	}
+}
+
+func TestTombstonesAreMerkleLeaves(t *testing.T) {
+	s := newTestStorage(t, "n1")
+	s.PutReplicated("live", "v1", event("n2", "a", map[string]int64{"n2": 1}, 10))
+	s.DeleteReplicated("gone", event("n2", "b", map[string]int64{"n2": 2}, 11))
+
+	tree, err := s.BuildMerkleTree()
+	if err != nil {
+		t.Fatalf("BuildMerkleTree: %v", err)
+	}
+	if tree.KeyCount != 2 {
+		t.Fatalf("tree has %d keys, want the live key and the tombstone", tree.KeyCount)
+	}
+	for _, leaf := range tree.Leaves {
+		if wantDeleted := leaf.Key == "gone"; leaf.Deleted != wantDeleted {
+			t.Errorf("leaf %s deleted = %t, want %t", leaf.Key, leaf.Deleted, wantDeleted)
+		}
+	}
+
+	// A tree rebuilt from the leaves (as a peer does after streaming them)
+	// has the same root
+	if rebuilt := NewMerkleTreeFromLeaves("n1", tree.Leaves); rebuilt.Root.Hash != tree.Root.Hash {
+		t.Errorf("rebuilt root %s, want %s", rebuilt.Root.Hash, tree.Root.Hash)
+	}
+}
+
+func TestLeafValueDiffers(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *MerkleNode
+		want bool
+	}{
+		{name: "same value hash", a: &MerkleNode{ValueHash: "x", Value: "v1"}, b: &MerkleNode{ValueHash: "x", Value: "v1"}},
+		{name: "other value hash", a: &MerkleNode{ValueHash: "x"}, b: &MerkleNode{ValueHash: "y"}, want: true},
+		// Trees from older nodes carry no value hash; the values decide
+		{name: "no value hash, same value", a: &MerkleNode{Value: "v1"}, b: &MerkleNode{ValueHash: "x", Value: "v1"}},
+		{name: "no value hash, other value", a: &MerkleNode{Value: "v1"}, b: &MerkleNode{Value: "v2"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LeafValueDiffers(tt.a, tt.b); got != tt.want {
+				t.Errorf("LeafValueDiffers = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestStorage(t *testing.T, nodeID string) *LevelDBStorage {
+	t.Helper()
+	s, err := NewLevelDBStorage(nodeID, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLevelDBStorage: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func event(nodeID, id string, clock map[string]int64, timestamp int64) *Event {
+	return &Event{ID: id, NodeID: nodeID, VectorClock: &VectorClock{Clocks: clock}, Timestamp: timestamp}
+}
@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"dynamodb/internal/clock"
 )
 
 // VectorClock represents a logical clock for tracking causality in distributed systems
@@ -30,6 +34,8 @@ type EventLog struct {
 	NodeID  string          `json:"node_id"`
 	Current *VectorClock    `json:"current_clock"`
 	Nodes   map[string]bool `json:"known_nodes"`
+
+	clock clock.Clock // Timestamps events
 }
 
 // NewVectorClock creates a new vector clock
@@ -46,6 +52,7 @@ func NewEventLog(nodeID string) *EventLog {
 		NodeID:  nodeID,
 		Current: NewVectorClock(),
 		Nodes:   make(map[string]bool),
+		clock:   clock.Real(),
 	}
 }
 
@@ -176,6 +183,34 @@ func (vc *VectorClock) String() string {
 	return result
 }
 
+// ParseVectorClock parses the representation produced by String
+func ParseVectorClock(s string) (*VectorClock, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return nil, fmt.Errorf("invalid vector clock: %q", s)
+	}
+
+	vc := NewVectorClock()
+	body := strings.TrimSpace(s[1 : len(s)-1])
+	if body == "" {
+		return vc, nil
+	}
+
+	for _, entry := range strings.Split(body, ", ") {
+		sep := strings.LastIndex(entry, ": ")
+		if sep < 0 {
+			return nil, fmt.Errorf("invalid vector clock entry: %q", entry)
+		}
+		timestamp, err := strconv.ParseInt(entry[sep+2:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vector clock entry: %q", entry)
+		}
+		vc.Clocks[entry[:sep]] = timestamp
+	}
+
+	return vc, nil
+}
+
 // AddEvent records a new event in the log with proper vector clock management
 func (el *EventLog) AddEvent(eventType, key, value string) *Event {
 	// Tick our own clock
@@ -183,14 +218,15 @@ func (el *EventLog) AddEvent(eventType, key, value string) *Event {
 	el.Nodes[el.NodeID] = true
 
 	// Create event
+	now := clock.OrReal(el.clock).Now()
 	event := &Event{
-		ID:          fmt.Sprintf("%s-%d-%d", el.NodeID, time.Now().UnixNano(), len(el.Events)),
+		ID:          fmt.Sprintf("%s-%d-%d", el.NodeID, now.UnixNano(), len(el.Events)),
 		Type:        eventType,
 		Key:         key,
 		Value:       value,
 		NodeID:      el.NodeID,
 		VectorClock: el.Current.Copy(),
-		Timestamp:   time.Now().Unix(),
+		Timestamp:   now.Unix(),
 		CausalHash:  computeEventHash(eventType, key, value, el.Current),
 	}
 
@@ -228,6 +264,59 @@ func (el *EventLog) MergeEventLog(other *EventLog) {
 	el.sortEventsByCausality()
 }
 
+// MergeEvents adds events shipped from another node and advances our clock
+// over the ones whose causal past is complete: an event is covered once its
+// node's previous event and every event it depends on are. Events behind a
+// gap wait for it to be filled, so the clock never covers an event that
+// isn't in the log.
+func (el *EventLog) MergeEvents(events []*Event) {
+	existingEvents := make(map[string]bool, len(el.Events))
+	for _, event := range el.Events {
+		existingEvents[event.ID] = true
+	}
+	for _, event := range events {
+		if event == nil || event.VectorClock == nil || existingEvents[event.ID] {
+			continue
+		}
+		existingEvents[event.ID] = true
+		el.Events = append(el.Events, event)
+		el.Nodes[event.NodeID] = true
+	}
+
+	// Delivering one event can make others deliverable
+	pending := el.GetEventsSince(el.Current)
+	for delivered := true; delivered; {
+		delivered = false
+		waiting := pending[:0]
+		for _, event := range pending {
+			if el.deliverable(event) {
+				el.Current.Update(event.VectorClock)
+				delivered = true
+			} else {
+				waiting = append(waiting, event)
+			}
+		}
+		pending = waiting
+	}
+
+	el.sortEventsByCausality()
+}
+
+// deliverable reports whether our clock covers everything event depends on:
+// its node's previous event and every other node's entry
+func (el *EventLog) deliverable(event *Event) bool {
+	for nodeID, timestamp := range event.VectorClock.Clocks {
+		limit := el.Current.Clocks[nodeID]
+		if nodeID == event.NodeID {
+			limit++
+		}
+		if timestamp > limit {
+			return false
+		}
+	}
+	return true
+}
+
 // DetectConflicts finds concurrent events that modified the same key
 func (el *EventLog) DetectConflicts() []*ConflictSet {
 	conflicts := make([]*ConflictSet, 0)
@@ -313,12 +402,15 @@ func computeEventHash(eventType, key, value string, vc *VectorClock) string {
 	return fmt.Sprintf("%x", hash)
 }
 
-// GetEventsSince returns events that happened after the given vector clock
+// GetEventsSince returns the events not covered by the given vector clock,
+// i.e. those a node whose clock is sinceVC hasn't seen. This includes events
+// concurrent with sinceVC, not only those that happened strictly after it.
 func (el *EventLog) GetEventsSince(sinceVC *VectorClock) []*Event {
 	result := make([]*Event, 0)
 
 	for _, event := range el.Events {
-		if sinceVC.HappensBefore(event.VectorClock) {
+		switch event.VectorClock.Compare(sinceVC) {
+		case After, Concurrent:
 			result = append(result, event)
 		}
 	}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"testing"
+)
+
+func TestMergeEventsCoversOnlyDeliveredEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		batches [][]*Event // Merged one after another
+		want    map[string]int64
+		waiting int // Logged events the clock doesn't cover yet
+	}{
+		{
+			name: "in order",
+			batches: [][]*Event{{
+				event("a", "a1", map[string]int64{"a": 1}, 1),
+				event("a", "a2", map[string]int64{"a": 2}, 2),
+			}},
+			want: map[string]int64{"a": 2},
+		},
+		{
+			name: "out of order",
+			batches: [][]*Event{{
+				event("a", "a2", map[string]int64{"a": 2}, 2),
+				event("a", "a1", map[string]int64{"a": 1}, 1),
+			}},
+			want: map[string]int64{"a": 2},
+		},
+		{
+			name: "gap in a node's events",
+			batches: [][]*Event{{
+				event("a", "a1", map[string]int64{"a": 1}, 1),
+				event("a", "a3", map[string]int64{"a": 3}, 3),
+			}},
+			want:    map[string]int64{"a": 1},
+			waiting: 1,
+		},
+		{
+			name: "gap filled later",
+			batches: [][]*Event{
+				{event("a", "a1", map[string]int64{"a": 1}, 1), event("a", "a3", map[string]int64{"a": 3}, 3)},
+				{event("a", "a2", map[string]int64{"a": 2}, 2)},
+			},
+			want: map[string]int64{"a": 3},
+		},
+		{
+			name: "depends on an event not shipped",
+			batches: [][]*Event{{
+				event("a", "a1", map[string]int64{"a": 1, "b": 2}, 1),
+				event("b", "b1", map[string]int64{"b": 1}, 1),
+			}},
+			want:    map[string]int64{"b": 1},
+			waiting: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := NewEventLog("c")
+			for _, batch := range tt.batches {
+				log.MergeEvents(batch)
+			}
+
+			if got := log.Current; got.Compare(&VectorClock{Clocks: tt.want}) != Equal {
+				t.Errorf("clock = %s, want %v", got, tt.want)
+			}
+			if got := len(log.GetEventsSince(log.Current)); got != tt.waiting {
+				t.Errorf("%d logged events not covered, want %d", got, tt.waiting)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package storage
+
+import "sort"
+
+// GetVectorClock returns the vector clock this value was written at. Values
+// written before the clock was stored as a field fall back to the metadata copy.
+func (sv *StorageValue) GetVectorClock() *VectorClock {
+	if sv.VectorClock != nil {
+		return sv.VectorClock
+	}
+
+	if encoded, ok := sv.Metadata["vector_clock"]; ok {
+		if vc, err := ParseVectorClock(encoded); err == nil {
+			return vc
+		}
+	}
+
+	return NewVectorClock()
+}
+
+// ReconcileVersions picks the newest version among replica responses, keyed by
+// replica ID. Nil entries (replica has no value) are ignored. It returns the
+// winning replica and the replicas holding versions concurrent with the winner
+// (siblings). Concurrent versions are ordered last-write-wins, the same way
+// replicas order them when storing, so every coordinator picks the same
+// winner. Tombstones take part like any other version.
+func ReconcileVersions(versions map[string]*StorageValue) (string, []string) {
+	replicaIDs := make([]string, 0, len(versions))
+	for replicaID, version := range versions {
+		if version != nil {
+			replicaIDs = append(replicaIDs, replicaID)
+		}
+	}
+	if len(replicaIDs) == 0 {
+		return "", nil
+	}
+	sort.Strings(replicaIDs)
+
+	// Keep only versions that no other version causally supersedes
+	frontier := make([]string, 0)
+	for _, candidate := range replicaIDs {
+		candidateClock := versions[candidate].GetVectorClock()
+		superseded := false
+		for _, other := range replicaIDs {
+			if other == candidate {
+				continue
+			}
+			if candidateClock.Compare(versions[other].GetVectorClock()) == Before {
+				superseded = true
+				break
+			}
+		}
+		if !superseded {
+			frontier = append(frontier, candidate)
+		}
+	}
+
+	sort.SliceStable(frontier, func(i, j int) bool {
+		return winsLastWrite(versions[frontier[i]], versions[frontier[j]])
+	})
+
+	winner := frontier[0]
+	winnerClock := versions[winner].GetVectorClock()
+
+	siblings := make([]string, 0)
+	for _, replicaID := range frontier[1:] {
+		if versions[replicaID].GetVectorClock().Compare(winnerClock) == Concurrent {
+			siblings = append(siblings, replicaID)
+		}
+	}
+
+	return winner, siblings
+}
+
+// Supersedes reports whether version a should replace version b on a
+// replica: a causally follows b, or the two are concurrent and a wins
+// last-write-wins. Equal versions don't supersede each other.
+func Supersedes(a, b *StorageValue) bool {
+	switch a.GetVectorClock().Compare(b.GetVectorClock()) {
+	case After:
+		return true
+	case Concurrent:
+		return winsLastWrite(a, b)
+	default:
+		return false
+	}
+}
+
+// winsLastWrite orders concurrent versions: the later timestamp wins, then
+// the writing node and the event ID break ties, so every replica resolves
+// the same conflict the same way
+func winsLastWrite(a, b *StorageValue) bool {
+	if a.Timestamp != b.Timestamp {
+		return a.Timestamp > b.Timestamp
+	}
+	if a.Metadata["node_id"] != b.Metadata["node_id"] {
+		return a.Metadata["node_id"] > b.Metadata["node_id"]
+	}
+	return a.Metadata["event_id"] > b.Metadata["event_id"]
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"testing"
+)
+
+func version(clock map[string]int64, timestamp int64, nodeID, eventID string) *StorageValue {
+	return &StorageValue{
+		Timestamp:   timestamp,
+		Metadata:    map[string]string{"node_id": nodeID, "event_id": eventID},
+		VectorClock: &VectorClock{Clocks: clock},
+	}
+}
+
+func TestSupersedes(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *StorageValue
+		want bool
+	}{
+		{
+			name: "causally after",
+			a:    version(map[string]int64{"n1": 2}, 1, "n1", "e2"),
+			b:    version(map[string]int64{"n1": 1}, 5, "n1", "e1"),
+			want: true,
+		},
+		{
+			name: "causally before",
+			a:    version(map[string]int64{"n1": 1}, 5, "n1", "e1"),
+			b:    version(map[string]int64{"n1": 2}, 1, "n1", "e2"),
+			want: false,
+		},
+		{
+			name: "equal",
+			a:    version(map[string]int64{"n1": 1}, 1, "n1", "e1"),
+			b:    version(map[string]int64{"n1": 1}, 1, "n1", "e1"),
+			want: false,
+		},
+		{
+			name: "concurrent, later timestamp wins",
+			a:    version(map[string]int64{"n1": 1}, 2, "n1", "e1"),
+			b:    version(map[string]int64{"n2": 1}, 1, "n2", "e2"),
+			want: true,
+		},
+		{
+			name: "concurrent, earlier timestamp loses",
+			a:    version(map[string]int64{"n2": 1}, 1, "n2", "e2"),
+			b:    version(map[string]int64{"n1": 1}, 2, "n1", "e1"),
+			want: false,
+		},
+		{
+			name: "concurrent, same timestamp, higher node wins",
+			a:    version(map[string]int64{"n2": 1}, 1, "n2", "e2"),
+			b:    version(map[string]int64{"n1": 1}, 1, "n1", "e1"),
+			want: true,
+		},
+		{
+			name: "concurrent, same timestamp and node, higher event wins",
+			a:    version(map[string]int64{"n1": 1, "n2": 1}, 1, "n1", "e2"),
+			b:    version(map[string]int64{"n1": 2}, 1, "n1", "e1"),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Supersedes(tt.a, tt.b); got != tt.want {
+				t.Errorf("Supersedes() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileVersionsPicksLastWriteAmongConcurrent(t *testing.T) {
+	versions := map[string]*StorageValue{
+		"r1": version(map[string]int64{"n1": 1}, 1, "n1", "e1"),
+		"r2": version(map[string]int64{"n2": 1}, 3, "n2", "e2"),
+		"r3": nil,
+	}
+
+	winner, siblings := ReconcileVersions(versions)
+	if winner != "r2" {
+		t.Errorf("winner = %q, want r2", winner)
+	}
+	if len(siblings) != 1 || siblings[0] != "r1" {
+		t.Errorf("siblings = %v, want [r1]", siblings)
+	}
+}
+
+func TestReplicatedWritesRespectTombstones(t *testing.T) {
+	s := newTestStorage(t, "n1")
+
+	put := event("n2", "put", map[string]int64{"n2": 1}, 10)
+	del := event("n2", "del", map[string]int64{"n2": 2}, 11)
+
+	if err := s.DeleteReplicated("k", del); err != nil {
+		t.Fatalf("DeleteReplicated: %v", err)
+	}
+	if err := s.PutReplicated("k", "old", put); err != ErrSuperseded {
+		t.Fatalf("PutReplicated after newer delete = %v, want ErrSuperseded", err)
+	}
+
+	if _, err := s.Get("k"); err != ErrKeyNotFound {
+		t.Errorf("Get = %v, want ErrKeyNotFound", err)
+	}
+	if exists, _ := s.Exists("k"); exists {
+		t.Error("Exists = true for a tombstone")
+	}
+	if keys, _ := s.ListKeys(); len(keys) != 0 {
+		t.Errorf("ListKeys = %v, want none", keys)
+	}
+
+	tombstone, err := s.GetVersion("k")
+	if err != nil || !tombstone.Deleted {
+		t.Fatalf("GetVersion = %+v, %v, want a tombstone", tombstone, err)
+	}
+	if tombstone.GetVectorClock().Compare(del.VectorClock) != Equal {
+		t.Errorf("tombstone clock = %s, want %s", tombstone.GetVectorClock(), del.VectorClock)
+	}
+}
+
+func TestLocalWriteFollowsStoredVersion(t *testing.T) {
+	s := newTestStorage(t, "n1")
+
+	if err := s.PutReplicated("k", "remote", event("n2", "e", map[string]int64{"n2": 5}, 10)); err != nil {
+		t.Fatalf("PutReplicated: %v", err)
+	}
+	if err := s.Delete("k"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	tombstone, _ := s.GetVersion("k")
+	remote := &VectorClock{Clocks: map[string]int64{"n2": 5}}
+	if tombstone.GetVectorClock().Compare(remote) != After {
+		t.Errorf("local delete clock %s doesn't follow %s", tombstone.GetVectorClock(), remote)
+	}
+}
+
+func TestPurgeTombstones(t *testing.T) {
+	s := newTestStorage(t, "n1")
+
+	s.DeleteReplicated("old", event("n2", "a", map[string]int64{"n2": 1}, 100))
+	s.DeleteReplicated("new", event("n2", "b", map[string]int64{"n2": 2}, 300))
+	s.PutReplicated("live", "v", event("n2", "c", map[string]int64{"n2": 3}, 50))
+
+	purged, err := s.PurgeTombstones(200)
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeTombstones = %d, %v, want 1", purged, err)
+	}
+	if _, err := s.GetVersion("old"); err != ErrKeyNotFound {
+		t.Errorf("old tombstone still stored: %v", err)
+	}
+	if _, err := s.GetVersion("new"); err != nil {
+		t.Errorf("new tombstone purged: %v", err)
+	}
+	if _, err := s.Get("live"); err != nil {
+		t.Errorf("live value purged: %v", err)
+	}
+}
@@ -0,0 +1,269 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"dynamodb/internal/fault"
+)
+
+// Config tunes the connection pool shared by all node-to-node traffic
+type Config struct {
+	MaxConnsPerPeer     int           // Open connections per peer (0 = unlimited)
+	MaxIdleConnsPerPeer int           // Keep-alive connections kept per peer
+	IdleConnTimeout     time.Duration // How long an idle connection is kept
+	DialTimeout         time.Duration // TCP connect timeout
+	KeepAlive           time.Duration // TCP keep-alive period
+	DefaultTimeout      time.Duration // Deadline for calls whose context has none
+}
+
+// DefaultConfig returns the pool settings used when none are given
+func DefaultConfig() *Config {
+	return &Config{
+		MaxConnsPerPeer:     32,
+		MaxIdleConnsPerPeer: 16,
+		IdleConnTimeout:     90 * time.Second,
+		DialTimeout:         2 * time.Second,
+		KeepAlive:           30 * time.Second,
+		DefaultTimeout:      5 * time.Second,
+	}
+}
+
+// PeerMetrics tracks the calls made to one peer address
+type PeerMetrics struct {
+	Requests     int64   `json:"requests"`
+	Failures     int64   `json:"failures"`
+	InFlight     int64   `json:"in_flight"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+	LastError    string  `json:"last_error,omitempty"`
+	LastUsed     int64   `json:"last_used"`
+
+	totalLatency time.Duration
+}
+
+// Metrics is a snapshot of the client's activity
+type Metrics struct {
+	ConnectionsOpened int64                   `json:"connections_opened"`
+	ConnectionsReused int64                   `json:"connections_reused"`
+	Requests          int64                   `json:"requests"`
+	Failures          int64                   `json:"failures"`
+	Peers             map[string]*PeerMetrics `json:"peers"`
+}
+
+// Client is the HTTP client for all node-to-node traffic (replication,
+// anti-entropy, gossip). It keeps pooled keep-alive connections per peer,
+// applies a default deadline to calls without one and records per-peer
+// metrics.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+
+	connsOpened int64
+	connsReused int64
+
+	peers   map[string]*PeerMetrics
+	peersMu sync.Mutex
+
+	// Sending node, set on every request so peers can tell who is calling
+	nodeID string
+	// Outbound fault injection (nil = none)
+	faults *fault.Injector
+}
+
+// NewClient creates a pooled inter-node client
+func NewClient(config *Config) *Client {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	c := &Client{
+		config: config,
+		peers:  make(map[string]*PeerMetrics),
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: config.KeepAlive,
+	}
+
+	c.httpClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				conn, err := dialer.DialContext(ctx, network, addr)
+				if err == nil {
+					atomic.AddInt64(&c.connsOpened, 1)
+				}
+				return conn, err
+			},
+			MaxConnsPerHost:     config.MaxConnsPerPeer,
+			MaxIdleConnsPerHost: config.MaxIdleConnsPerPeer,
+			IdleConnTimeout:     config.IdleConnTimeout,
+		},
+	}
+
+	return c
+}
+
+// SetNodeID names this node on every request it sends
+func (c *Client) SetNodeID(nodeID string) {
+	c.nodeID = nodeID
+}
+
+// SetFaults applies the injector's outbound rules to every request
+func (c *Client) SetFaults(injector *fault.Injector) {
+	c.faults = injector
+}
+
+// Do sends a request to a peer. The request's context bounds the call; if it
+// has no deadline, the configured default timeout applies.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	var cancel context.CancelFunc
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		ctx, cancel = context.WithTimeout(ctx, c.config.DefaultTimeout)
+	}
+
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				atomic.AddInt64(&c.connsReused, 1)
+			}
+		},
+	})
+	req = req.WithContext(ctx)
+	if c.nodeID != "" {
+		req.Header.Set(fault.NodeIDHeader, c.nodeID)
+	}
+
+	peer := c.peerMetrics(req.URL.Host)
+	c.peersMu.Lock()
+	peer.InFlight++
+	c.peersMu.Unlock()
+
+	start := time.Now()
+	var resp *http.Response
+	var err error
+	if c.faults != nil {
+		resp, err = c.faults.Send(req, c.httpClient.Do)
+	} else {
+		resp, err = c.httpClient.Do(req)
+	}
+	c.record(peer, time.Since(start), err)
+
+	if err != nil {
+		if cancel != nil {
+			cancel()
+		}
+		return nil, err
+	}
+
+	if cancel != nil {
+		// Keep the deadline alive until the caller is done with the body
+		resp.Body = CancelOnClose(resp.Body, cancel)
+	}
+	return resp, nil
+}
+
+// Get sends a GET request to a peer
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post sends a POST request to a peer
+func (c *Client) Post(ctx context.Context, url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// Metrics returns a snapshot of connection and per-peer call metrics
+func (c *Client) Metrics() *Metrics {
+	c.peersMu.Lock()
+	defer c.peersMu.Unlock()
+
+	metrics := &Metrics{
+		ConnectionsOpened: atomic.LoadInt64(&c.connsOpened),
+		ConnectionsReused: atomic.LoadInt64(&c.connsReused),
+		Peers:             make(map[string]*PeerMetrics, len(c.peers)),
+	}
+
+	for addr, peer := range c.peers {
+		snapshot := *peer
+		if peer.Requests > 0 {
+			snapshot.AvgLatencyMs = float64(peer.totalLatency.Nanoseconds()) / float64(peer.Requests) / 1000000
+		}
+		metrics.Peers[addr] = &snapshot
+		metrics.Requests += peer.Requests
+		metrics.Failures += peer.Failures
+	}
+
+	return metrics
+}
+
+// Close drops all idle pooled connections
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+func (c *Client) peerMetrics(addr string) *PeerMetrics {
+	c.peersMu.Lock()
+	defer c.peersMu.Unlock()
+
+	peer, exists := c.peers[addr]
+	if !exists {
+		peer = &PeerMetrics{}
+		c.peers[addr] = peer
+	}
+	return peer
+}
+
+func (c *Client) record(peer *PeerMetrics, latency time.Duration, err error) {
+	c.peersMu.Lock()
+	defer c.peersMu.Unlock()
+
+	peer.InFlight--
+	peer.Requests++
+	peer.LastUsed = time.Now().Unix()
+	peer.totalLatency += latency
+	if ms := float64(latency.Nanoseconds()) / 1000000; ms > peer.MaxLatencyMs {
+		peer.MaxLatencyMs = ms
+	}
+	if err != nil {
+		peer.Failures++
+		peer.LastError = fmt.Sprintf("%v", err)
+	}
+}
+
+// CancelOnClose wraps a response body so cancel runs once the body is
+// closed. Use it to return a response whose context deadline must outlive
+// the function that set it.
+func CancelOnClose(body io.ReadCloser, cancel context.CancelFunc) io.ReadCloser {
+	return &cancelOnClose{ReadCloser: body, cancel: cancel}
+}
+
+// cancelOnClose releases a call's deadline once its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// get sends a GET through the client and reads the whole body, so the
+// connection can go back to the pool
+func get(t *testing.T, c *Client, url string) error {
+	t.Helper()
+
+	resp, err := c.Get(context.Background(), url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	return err
+}
+
+func TestClientReusesPooledConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	c := NewClient(nil)
+	defer c.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := get(t, c, server.URL); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+
+	metrics := c.Metrics()
+	if metrics.ConnectionsOpened != 1 || metrics.ConnectionsReused != 4 {
+		t.Errorf("opened %d, reused %d connections for 5 sequential calls, want 1 and 4",
+			metrics.ConnectionsOpened, metrics.ConnectionsReused)
+	}
+
+	peer := metrics.Peers[strings.TrimPrefix(server.URL, "http://")]
+	if peer == nil || peer.Requests != 5 || peer.Failures != 0 || peer.InFlight != 0 {
+		t.Errorf("peer metrics = %+v, want 5 requests, no failures, none in flight", peer)
+	}
+}
+
+func TestClientDropsConnectionsAfterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    func(w http.ResponseWriter)
+		timeout time.Duration
+	}{
+		{
+			name: "connection closed mid-call",
+			fail: func(w http.ResponseWriter) {
+				if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+					conn.Close()
+				}
+			},
+		},
+		{
+			name:    "call timed out",
+			fail:    func(w http.ResponseWriter) { time.Sleep(200 * time.Millisecond) },
+			timeout: 50 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/fail" {
+					tt.fail(w)
+					return
+				}
+				io.WriteString(w, "ok")
+			}))
+			defer server.Close()
+
+			config := DefaultConfig()
+			if tt.timeout > 0 {
+				config.DefaultTimeout = tt.timeout
+			}
+			c := NewClient(config)
+			defer c.Close()
+
+			if err := get(t, c, server.URL+"/ok"); err != nil {
+				t.Fatalf("first call: %v", err)
+			}
+			if err := get(t, c, server.URL+"/fail"); err == nil {
+				t.Fatalf("failing call succeeded")
+			}
+
+			// The broken connection isn't handed out again: the next call dials
+			before := c.Metrics()
+			if err := get(t, c, server.URL+"/ok"); err != nil {
+				t.Fatalf("call after the failure: %v", err)
+			}
+			metrics := c.Metrics()
+			if metrics.ConnectionsOpened != before.ConnectionsOpened+1 || metrics.ConnectionsReused != before.ConnectionsReused {
+				t.Errorf("call after the failure opened %d and reused %d connections, want a new one",
+					metrics.ConnectionsOpened-before.ConnectionsOpened, metrics.ConnectionsReused-before.ConnectionsReused)
+			}
+
+			peer := metrics.Peers[strings.TrimPrefix(server.URL, "http://")]
+			if peer == nil || peer.Failures != 1 || peer.LastError == "" {
+				t.Errorf("peer metrics = %+v, want one failure with its error", peer)
+			}
+		})
+	}
+}
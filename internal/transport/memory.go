@@ -0,0 +1,237 @@
+package transport
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"dynamodb/internal/clock"
+)
+
+// ErrUnreachable is returned by the in-memory network when a call can't reach
+// its target: the target isn't registered, the link is partitioned or the
+// call was dropped
+var ErrUnreachable = errors.New("peer unreachable")
+
+// Network connects nodes running inside one process. Each node registers the
+// services it serves under its address; transports look them up per call.
+// Latency, random drops and partitions can be changed while the nodes run.
+type Network struct {
+	mu        sync.Mutex
+	endpoints map[string]map[string]interface{} // address -> service -> handler
+	blocked   map[string]map[string]bool        // from -> to
+	latency   time.Duration
+	jitter    time.Duration
+	dropRate  float64
+	seed      int64
+	clock     clock.Clock
+
+	// Calls each link carried at the current instant, so the randomness of
+	// a call depends on the call and not on when it arrived
+	calls   map[string]uint64
+	callsAt time.Time
+
+	delivered int64
+	dropped   int64
+}
+
+// NetworkStats counts the calls the network delivered and refused
+type NetworkStats struct {
+	Delivered int64 `json:"delivered"`
+	Dropped   int64 `json:"dropped"`
+}
+
+// NewNetwork creates an in-memory network. The seed drives latency jitter and
+// drops: each call draws from a source keyed by the seed, its link (from, to
+// and service), the time and its place among that link's calls at that time,
+// so the same calls get the same fate whichever order they arrive in.
+func NewNetwork(seed int64) *Network {
+	return &Network{
+		endpoints: make(map[string]map[string]interface{}),
+		blocked:   make(map[string]map[string]bool),
+		seed:      seed,
+		clock:     clock.Real(),
+		calls:     make(map[string]uint64),
+	}
+}
+
+// SetClock sets the clock latency and lost calls wait on. With a simulated
+// clock a lost call waits out the rest of its ctx deadline in simulated time.
+func (n *Network) SetClock(c clock.Clock) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.clock = clock.OrReal(c)
+}
+
+// Register makes handler reachable as service at address
+func (n *Network) Register(address, service string, handler interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.endpoints[address] == nil {
+		n.endpoints[address] = make(map[string]interface{})
+	}
+	n.endpoints[address][service] = handler
+}
+
+// Unregister removes every service at address, as if the node crashed.
+// Calls to it fail immediately like a refused connection.
+func (n *Network) Unregister(address string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.endpoints, address)
+}
+
+// SetLatency delays every call by latency plus a random share of jitter
+func (n *Network) SetLatency(latency, jitter time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.latency = latency
+	n.jitter = jitter
+}
+
+// SetDropRate drops the given fraction (0-1) of calls
+func (n *Network) SetDropRate(rate float64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.dropRate = rate
+}
+
+// Partition splits the network: addresses in different groups can't reach
+// each other. Addresses not listed keep their links.
+func (n *Network) Partition(groups ...[]string) {
+	for i, group := range groups {
+		for _, other := range groups[i+1:] {
+			for _, a := range group {
+				for _, b := range other {
+					n.Block(a, b)
+					n.Block(b, a)
+				}
+			}
+		}
+	}
+}
+
+// Block cuts the one-way link from one address to another
+func (n *Network) Block(from, to string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.blocked[from] == nil {
+		n.blocked[from] = make(map[string]bool)
+	}
+	n.blocked[from][to] = true
+}
+
+// Heal removes all partitions and blocked links
+func (n *Network) Heal() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.blocked = make(map[string]map[string]bool)
+}
+
+// Stats returns the delivered and dropped call counts
+func (n *Network) Stats() NetworkStats {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return NetworkStats{Delivered: n.delivered, Dropped: n.dropped}
+}
+
+// Call routes a call from one address to a service at another and returns
+// the handler to invoke. Unregistered targets fail at once; dropped and
+// partitioned calls hang until ctx expires, like packets lost on a real
+// network. Successful calls are delayed by the configured latency.
+func (n *Network) Call(ctx context.Context, from, to, service string) (interface{}, error) {
+	n.mu.Lock()
+	handler, registered := n.endpoints[to][service]
+	drop, jitter := n.draw(from, to, service)
+	lost := n.blocked[from][to] || (n.dropRate > 0 && drop < n.dropRate)
+	clk := n.clock
+	delay := n.latency
+	if n.jitter > 0 {
+		delay += time.Duration(jitter * float64(n.jitter))
+	}
+	if !registered || lost {
+		n.dropped++
+	} else {
+		n.delivered++
+	}
+	n.mu.Unlock()
+
+	if !registered {
+		return nil, fmt.Errorf("%w: no %s service at %s", ErrUnreachable, service, to)
+	}
+
+	if lost {
+		if deadline, ok := ctx.Deadline(); ok {
+			select {
+			case <-clk.After(deadline.Sub(clk.Now())):
+			case <-ctx.Done():
+			}
+		} else {
+			<-ctx.Done()
+		}
+		return nil, fmt.Errorf("%w: %s -> %s: timed out", ErrUnreachable, from, to)
+	}
+
+	if delay > 0 {
+		select {
+		case <-clk.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return handler, nil
+}
+
+// draw returns the two random numbers in [0, 1) that decide whether a call is
+// dropped and how much jitter it gets. Callers hold mu.
+func (n *Network) draw(from, to, service string) (float64, float64) {
+	now := n.clock.Now()
+	if !now.Equal(n.callsAt) {
+		n.calls = make(map[string]uint64)
+		n.callsAt = now
+	}
+
+	link := from + ">" + to + "/" + service
+	index := n.calls[link]
+	n.calls[link]++
+
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(n.seed))
+	h.Write(buf[:])
+	h.Write([]byte(link))
+	binary.BigEndian.PutUint64(buf[:], uint64(now.UnixNano()))
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], index)
+	h.Write(buf[:])
+
+	x := h.Sum64()
+	return unitFloat(splitmix(&x)), unitFloat(splitmix(&x))
+}
+
+// splitmix advances a SplitMix64 state and returns the next output
+func splitmix(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// unitFloat maps a random 64-bit value to [0, 1)
+func unitFloat(x uint64) float64 {
+	return float64(x>>11) / (1 << 53)
+}
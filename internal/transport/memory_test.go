@@ -0,0 +1,212 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"dynamodb/internal/clock"
+)
+
+// newTestNetwork creates a network on a simulated clock with a "ping" service
+// registered at every address
+func newTestNetwork(t *testing.T, seed int64, addresses ...string) (*Network, *clock.Simulated) {
+	t.Helper()
+
+	clk := clock.NewSimulated(time.Unix(0, 0))
+	network := NewNetwork(seed)
+	network.SetClock(clk)
+	for _, address := range addresses {
+		network.Register(address, "ping", address)
+	}
+	return network, clk
+}
+
+// call makes a ping call with a one second timeout, advancing the clock
+// until it returns, and reports the simulated time it took
+func call(t *testing.T, network *Network, clk *clock.Simulated, from, to string) (time.Duration, error) {
+	t.Helper()
+
+	var elapsed time.Duration
+	var err error
+	done := make(chan struct{})
+	clk.Go(func() {
+		defer close(done)
+
+		ctx, cancel := clk.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		start := clk.Now()
+		var handler interface{}
+		handler, err = network.Call(ctx, from, to, "ping")
+		elapsed = clk.Since(start)
+		if err == nil && handler != to {
+			err = fmt.Errorf("got handler %v, want %s's", handler, to)
+		}
+	})
+
+	for waited := time.Duration(0); waited <= 2*time.Second; waited += 10 * time.Millisecond {
+		select {
+		case <-done:
+			return elapsed, err
+		default:
+		}
+		clk.Advance(10 * time.Millisecond)
+	}
+	t.Fatalf("call %s -> %s still running after 2s", from, to)
+	return 0, nil
+}
+
+// fates makes calls at a single instant with an expired context, so lost
+// calls return at once, and reports which of them got through
+func fates(network *Network, links [][2]string) []bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	delivered := make([]bool, len(links))
+	for i, link := range links {
+		_, err := network.Call(ctx, link[0], link[1], "ping")
+		delivered[i] = err == nil
+	}
+	return delivered
+}
+
+func TestNetworkUnregisteredTargetFailsAtOnce(t *testing.T) {
+	network, clk := newTestNetwork(t, 1, "a", "b")
+	network.Unregister("b")
+
+	elapsed, err := call(t, network, clk, "a", "b")
+	if !errors.Is(err, ErrUnreachable) || elapsed != 0 {
+		t.Errorf("call to an unregistered node took %v: %v; want ErrUnreachable at once", elapsed, err)
+	}
+	if stats := network.Stats(); stats.Dropped != 1 || stats.Delivered != 0 {
+		t.Errorf("stats = %+v, want the call counted as dropped", stats)
+	}
+}
+
+func TestNetworkLatency(t *testing.T) {
+	network, clk := newTestNetwork(t, 1, "a", "b")
+
+	network.SetLatency(50*time.Millisecond, 0)
+	if elapsed, err := call(t, network, clk, "a", "b"); err != nil || elapsed != 50*time.Millisecond {
+		t.Errorf("call took %v: %v; want 50ms", elapsed, err)
+	}
+
+	network.SetLatency(50*time.Millisecond, 100*time.Millisecond)
+	for i := 0; i < 20; i++ {
+		elapsed, err := call(t, network, clk, "a", "b")
+		if err != nil || elapsed < 50*time.Millisecond || elapsed >= 150*time.Millisecond {
+			t.Errorf("call took %v: %v; want between 50ms and 150ms", elapsed, err)
+		}
+	}
+
+	// A latency longer than the caller's timeout is a timeout
+	network.SetLatency(2*time.Second, 0)
+	if elapsed, err := call(t, network, clk, "a", "b"); !errors.Is(err, context.DeadlineExceeded) || elapsed != time.Second {
+		t.Errorf("call took %v: %v; want the 1s deadline to expire", elapsed, err)
+	}
+}
+
+func TestNetworkPartitions(t *testing.T) {
+	network, clk := newTestNetwork(t, 1, "a", "b", "c")
+	network.Partition([]string{"a"}, []string{"b"})
+
+	tests := []struct {
+		from, to string
+		reaches  bool
+	}{
+		{"a", "b", false},
+		{"b", "a", false},
+		{"a", "c", true}, // c isn't in any group and keeps its links
+		{"c", "b", true},
+	}
+	for _, tt := range tests {
+		elapsed, err := call(t, network, clk, tt.from, tt.to)
+		if tt.reaches && err != nil {
+			t.Errorf("%s -> %s failed across no partition: %v", tt.from, tt.to, err)
+		}
+		// A partitioned call is lost, not refused: it waits out the timeout
+		if !tt.reaches && (!errors.Is(err, ErrUnreachable) || elapsed != time.Second) {
+			t.Errorf("%s -> %s took %v: %v; want ErrUnreachable after the 1s timeout", tt.from, tt.to, elapsed, err)
+		}
+	}
+
+	network.Heal()
+	if _, err := call(t, network, clk, "a", "b"); err != nil {
+		t.Errorf("a -> b after Heal: %v", err)
+	}
+}
+
+func TestNetworkBlockIsOneWay(t *testing.T) {
+	network, clk := newTestNetwork(t, 1, "a", "b")
+	network.Block("a", "b")
+
+	if _, err := call(t, network, clk, "a", "b"); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("a -> b over a blocked link: %v, want ErrUnreachable", err)
+	}
+	if _, err := call(t, network, clk, "b", "a"); err != nil {
+		t.Errorf("b -> a: %v, want it delivered", err)
+	}
+}
+
+func TestNetworkDropRate(t *testing.T) {
+	links := make([][2]string, 2000)
+	for i := range links {
+		links[i] = [2]string{"a", "b"}
+	}
+
+	for _, rate := range []float64{0, 0.3, 1} {
+		network, _ := newTestNetwork(t, 1, "a", "b")
+		network.SetDropRate(rate)
+
+		dropped := 0
+		for _, delivered := range fates(network, links) {
+			if !delivered {
+				dropped++
+			}
+		}
+		if got := float64(dropped) / float64(len(links)); math.Abs(got-rate) > 0.05 {
+			t.Errorf("drop rate %v dropped %.3f of calls", rate, got)
+		}
+		if stats := network.Stats(); stats.Dropped != int64(dropped) || stats.Delivered != int64(len(links)-dropped) {
+			t.Errorf("stats = %+v, want %d dropped", stats, dropped)
+		}
+	}
+}
+
+func TestNetworkDropsDependOnCallsNotArrivalOrder(t *testing.T) {
+	var links [][2]string
+	for i := 0; i < 50; i++ {
+		links = append(links, [2]string{"a", "c"}, [2]string{"b", "c"})
+	}
+	// The same calls, with the links' calls arriving in the other order
+	reordered := make([][2]string, 0, len(links))
+	for i := 0; i < 50; i++ {
+		reordered = append(reordered, [2]string{"b", "c"}, [2]string{"a", "c"})
+	}
+
+	run := func(seed int64, links [][2]string) map[string][]bool {
+		network, _ := newTestNetwork(t, seed, "a", "b", "c")
+		network.SetDropRate(0.5)
+
+		perLink := make(map[string][]bool)
+		for i, delivered := range fates(network, links) {
+			perLink[links[i][0]] = append(perLink[links[i][0]], delivered)
+		}
+		return perLink
+	}
+
+	first, second := run(7, links), run(7, reordered)
+	for _, from := range []string{"a", "b"} {
+		if fmt.Sprint(first[from]) != fmt.Sprint(second[from]) {
+			t.Errorf("calls from %s got different fates when arriving in another order", from)
+		}
+	}
+
+	if other := run(8, links); fmt.Sprint(other) == fmt.Sprint(first) {
+		t.Errorf("seeds 7 and 8 dropped the same calls")
+	}
+}